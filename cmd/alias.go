@@ -0,0 +1,103 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+var aliasUsage = fmt.Sprintf(`Используйте формат:
+/alias event_name - показать псевдонимы события
+/alias event_name alias - добавить событию ещё одну команду, например /alias new_year ny
+/unalias alias - удалить псевдоним
+
+Псевдоним можно написать по-русски, он будет транслитерирован: /alias new_year Новый год даст /novyy_god.
+У события может быть не больше %d псевдонимов.`, models.MaxAliases)
+
+// registerAliasHandlers регистрирует команды псевдонимов событий
+func registerAliasHandlers(b *bot.Bot, botName string, eventService *services.EventService, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/alias"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleAlias(ctx, b, update, eventService, permissions)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/unalias"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleUnalias(ctx, b, update, eventService, permissions)
+	})
+}
+
+func handleAlias(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService) {
+	chatID := update.Message.Chat.ID
+	name, rest := splitEventName(telegram.CommandArgs(update.Message.Text)[1:])
+	if name == "" {
+		sendMessage(ctx, b, chatID, aliasUsage)
+		return
+	}
+
+	if len(rest) == 0 {
+		event, err := eventService.GetEvent(chatID, name)
+		if err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+			return
+		}
+		if len(event.Aliases) == 0 {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("У события '%s' нет псевдонимов, его команда - /%s", event.Title(), event.Name))
+			return
+		}
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' открывается командами /%s, /%s", event.Title(), event.Name, strings.Join(event.Aliases, ", /")))
+		return
+	}
+
+	alias := trimQuotes(strings.TrimPrefix(strings.Join(rest, " "), "/"))
+	if models.IsSystemCommand(models.EventSlug(alias)) || models.IsSystemCommand(alias) {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("/%s - команда бота, выберите другой псевдоним", alias))
+		return
+	}
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	event, err := eventService.AddAlias(chatID, name, alias)
+	switch {
+	case errors.Is(err, storage.ErrEventNotFound):
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+		return
+	case err != nil:
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	added := event.Aliases[len(event.Aliases)-1]
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' теперь открывается и командой /%s", event.Title(), added))
+}
+
+func handleUnalias(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService) {
+	chatID := update.Message.Chat.ID
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) < 2 {
+		sendMessage(ctx, b, chatID, aliasUsage)
+		return
+	}
+
+	alias := trimQuotes(strings.TrimPrefix(strings.Join(parts[1:], " "), "/"))
+	if !models.IsValidEventName(alias) && models.IsValidDisplayName(alias) {
+		alias = models.EventSlug(alias)
+	}
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, alias) {
+		return
+	}
+	event, err := eventService.RemoveAlias(chatID, alias)
+	switch {
+	case errors.Is(err, storage.ErrEventNotFound):
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Псевдоним '%s' не найден", alias))
+		return
+	case err != nil:
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Псевдоним /%s удалён, событие '%s' по-прежнему открывается командой /%s", alias, event.Title(), event.Name))
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+const publishUsage = `Используйте формат:
+/publish catalog_name event_name - опубликовать событие чата в каталоге
+/unpublish catalog_name event_name - убрать событие из каталога
+
+Каталог создаётся при первой публикации, публиковать в него может только создавший его чат.
+Другие чаты видят события каталога после /subscribe catalog_name`
+
+const subscribeUsage = `Используйте формат:
+/subscribe catalog_name - подписать чат на каталог: его события появятся в /list и будут доступны как команды
+/unsubscribe catalog_name - отписать чат от каталога`
+
+// registerCatalogHandlers регистрирует команды общих каталогов событий
+func registerCatalogHandlers(b *bot.Bot, botName string, eventService *services.EventService, catalogService *services.CatalogService, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/publish"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handlePublish(ctx, b, update, eventService, catalogService, permissions)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/unpublish"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleUnpublish(ctx, b, update, eventService, catalogService, permissions)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/subscribe"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleSubscribe(ctx, b, update, catalogService, permissions)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/unsubscribe"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleUnsubscribe(ctx, b, update, catalogService, permissions)
+	})
+}
+
+func handlePublish(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, catalogService *services.CatalogService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	chatID := update.Message.Chat.ID
+	if len(parts) != 3 {
+		sendMessage(ctx, b, chatID, publishUsage)
+		return
+	}
+
+	catalog, name := parts[1], parts[2]
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	if err := catalogService.Publish(chatID, catalog, name); err != nil {
+		sendMessage(ctx, b, chatID, describeCatalogError(err, catalog, name))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' опубликовано в каталоге '%s'. Другие чаты могут подписаться командой /subscribe %s", name, catalog, catalog))
+}
+
+func handleUnpublish(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, catalogService *services.CatalogService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	chatID := update.Message.Chat.ID
+	if len(parts) != 3 {
+		sendMessage(ctx, b, chatID, publishUsage)
+		return
+	}
+
+	catalog, name := parts[1], parts[2]
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	if err := catalogService.Unpublish(chatID, catalog, name); err != nil {
+		sendMessage(ctx, b, chatID, describeCatalogError(err, catalog, name))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' убрано из каталога '%s'", name, catalog))
+}
+
+func handleSubscribe(ctx context.Context, b *bot.Bot, update *tgmodels.Update, catalogService *services.CatalogService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	chatID := update.Message.Chat.ID
+	if len(parts) == 1 {
+		subscriptions, err := catalogService.Subscriptions(chatID)
+		if err != nil {
+			sendMessage(ctx, b, chatID, "Ошибка при получении подписок")
+			return
+		}
+		if len(subscriptions) == 0 {
+			sendMessage(ctx, b, chatID, "Чат не подписан ни на один каталог.\n\n"+subscribeUsage)
+			return
+		}
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Подписки чата: %s\n\n%s", strings.Join(subscriptions, ", "), subscribeUsage))
+		return
+	}
+	if len(parts) != 2 {
+		sendMessage(ctx, b, chatID, subscribeUsage)
+		return
+	}
+
+	// Подписки меняют /list всего чата, поэтому это настройка чата, как /policy
+	if !checkPermission(ctx, b, chatID, permissions.CanManageChat(ctx, chatID, senderID(update.Message))) {
+		return
+	}
+	catalog := parts[1]
+	if err := catalogService.Subscribe(chatID, catalog); err != nil {
+		sendMessage(ctx, b, chatID, describeCatalogError(err, catalog, ""))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Чат подписан на каталог '%s'. Его события показываются в /list", catalog))
+}
+
+func handleUnsubscribe(ctx context.Context, b *bot.Bot, update *tgmodels.Update, catalogService *services.CatalogService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	chatID := update.Message.Chat.ID
+	if len(parts) != 2 {
+		sendMessage(ctx, b, chatID, subscribeUsage)
+		return
+	}
+
+	if !checkPermission(ctx, b, chatID, permissions.CanManageChat(ctx, chatID, senderID(update.Message))) {
+		return
+	}
+	catalog := parts[1]
+	if err := catalogService.Unsubscribe(chatID, catalog); err != nil {
+		sendMessage(ctx, b, chatID, describeCatalogError(err, catalog, ""))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Чат отписан от каталога '%s'", catalog))
+}
+
+// describeCatalogError переводит ошибку операции с каталогом в сообщение для пользователя
+func describeCatalogError(err error, catalog, name string) string {
+	switch {
+	case errors.Is(err, services.ErrInvalidCatalogName):
+		return "Имя каталога может содержать только латинские буквы, цифры и _"
+	case errors.Is(err, services.ErrCatalogOwnedByOtherChat):
+		return fmt.Sprintf("Каталог '%s' принадлежит другому чату", catalog)
+	case errors.Is(err, services.ErrOwnCatalog):
+		return fmt.Sprintf("Каталог '%s' принадлежит этому чату, его события и так видны здесь", catalog)
+	case errors.Is(err, storage.ErrCatalogNotFound):
+		return fmt.Sprintf("Каталог '%s' не найден", catalog)
+	case errors.Is(err, storage.ErrSubscriptionNotFound):
+		return fmt.Sprintf("Чат не подписан на каталог '%s'", catalog)
+	case errors.Is(err, storage.ErrEventNotFound) && name != "":
+		return fmt.Sprintf("Событие '%s' не найдено", name)
+	default:
+		return fmt.Sprintf("Ошибка: %s", err.Error())
+	}
+}
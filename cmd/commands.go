@@ -0,0 +1,66 @@
+package main
+
+import (
+	"context"
+
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+// baseCommands - системные команды меню "/". Это общее меню бота; в меню чата
+// после них идут активные события чата.
+var baseCommands = []scheduler.Command{
+	{Command: "new", Description: "Создать событие по шагам"},
+	{Command: "cancel", Description: "Отменить создание события"},
+	{Command: "set_date", Description: "Добавить событие (/set_date DD.MM.YYYY name)"},
+	{Command: "edit_date", Description: "Изменить дату (/edit_date name YYYY-MM-DD)"},
+	{Command: "edit_description", Description: "Изменить описание события"},
+	{Command: "rename", Description: "Переименовать событие"},
+	{Command: "delete", Description: "Удалить событие"},
+	{Command: "alias", Description: "Добавить событию ещё одну команду"},
+	{Command: "unalias", Description: "Удалить псевдоним события"},
+	{Command: "countup", Description: "Считать время, прошедшее с события"},
+	{Command: "remind", Description: "Напоминания о событии (/remind name 7d 1d 0)"},
+	{Command: "timezone", Description: "Часовой пояс чата (/timezone Asia/Yekaterinburg)"},
+	{Command: "event_timezone", Description: "Часовой пояс события"},
+	{Command: "publish", Description: "Опубликовать событие в каталоге"},
+	{Command: "subscribe", Description: "Подписаться на каталог событий"},
+	{Command: "visibility", Description: "Видимость события в других чатах"},
+	{Command: "policy", Description: "Кто может создавать и изменять события"},
+	{Command: "pin_countdown", Description: "Закрепить отсчёт до ближайших событий"},
+	{Command: "list", Description: "Список событий"},
+	{Command: "all", Description: "Все события"},
+	{Command: "active", Description: "Активные события"},
+	{Command: "outdated", Description: "Устаревшие события"},
+	{Command: "help", Description: "Справка"},
+}
+
+// botCommands переводит команды меню в формат Telegram
+func botCommands(commands []scheduler.Command) []tgmodels.BotCommand {
+	result := make([]tgmodels.BotCommand, len(commands))
+	for i, command := range commands {
+		result[i] = tgmodels.BotCommand{Command: command.Command, Description: command.Description}
+	}
+	return result
+}
+
+// commandMenuClient публикует меню команд чатов в Telegram
+type commandMenuClient struct {
+	b *bot.Bot
+}
+
+func (c commandMenuClient) SetChatCommands(ctx context.Context, chatID int64, commands []scheduler.Command) error {
+	_, err := c.b.SetMyCommands(ctx, &bot.SetMyCommandsParams{
+		Commands: botCommands(commands),
+		Scope:    &tgmodels.BotCommandScopeChat{ChatID: chatID},
+	})
+	return schedulerError(err)
+}
+
+func (c commandMenuClient) DeleteChatCommands(ctx context.Context, chatID int64) error {
+	_, err := c.b.DeleteMyCommands(ctx, &bot.DeleteMyCommandsParams{
+		Scope: &tgmodels.BotCommandScopeChat{ChatID: chatID},
+	})
+	return schedulerError(err)
+}
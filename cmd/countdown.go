@@ -0,0 +1,155 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+var pinCountdownUsage = fmt.Sprintf(`Используйте формат:
+/pin_countdown [n] - закрепить сообщение с n ближайшими событиями (по умолчанию %d, не больше %d); бот сам обновляет его
+/pin_countdown off - открепить и больше не обновлять
+
+Закреплять отсчёт могут администраторы чата, боту нужно право закреплять сообщения`, models.DefaultCountdownSize, models.MaxCountdownSize)
+
+// registerCountdownHandlers регистрирует команду закреплённого отсчёта
+func registerCountdownHandlers(b *bot.Bot, botName string, countdowns *scheduler.CountdownScheduler, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/pin_countdown"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handlePinCountdown(ctx, b, update, countdowns, permissions)
+	})
+}
+
+func handlePinCountdown(ctx context.Context, b *bot.Bot, update *tgmodels.Update, countdowns *scheduler.CountdownScheduler, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	chatID := update.Message.Chat.ID
+	if len(parts) > 2 {
+		sendMessage(ctx, b, chatID, pinCountdownUsage)
+		return
+	}
+
+	size := models.DefaultCountdownSize
+	off := false
+	if len(parts) == 2 {
+		if strings.EqualFold(parts[1], "off") {
+			off = true
+		} else {
+			n, err := strconv.Atoi(parts[1])
+			if err != nil || n < 1 || n > models.MaxCountdownSize {
+				sendMessage(ctx, b, chatID, pinCountdownUsage)
+				return
+			}
+			size = n
+		}
+	}
+	if !checkPermission(ctx, b, chatID, permissions.CanManageChat(ctx, chatID, senderID(update.Message))) {
+		return
+	}
+
+	if off {
+		err := countdowns.Unpin(ctx, chatID)
+		switch {
+		case errors.Is(err, storage.ErrCountdownNotFound):
+			sendMessage(ctx, b, chatID, "В этом чате нет закреплённого отсчёта")
+		case err != nil:
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		default:
+			sendMessage(ctx, b, chatID, "Отсчёт откреплён и больше не обновляется")
+		}
+		return
+	}
+
+	countdown, err := countdowns.Pin(ctx, chatID, size)
+	if countdown == nil {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Не удалось отправить отсчёт: %s", err.Error()))
+		return
+	}
+	if err != nil {
+		sendMessage(ctx, b, chatID, "Отсчёт отправлен, но закрепить его не удалось: дайте боту право закреплять сообщения. Сообщение всё равно будет обновляться.")
+	}
+}
+
+// countdownClient выполняет запросы закреплённого отсчёта к Telegram
+type countdownClient struct {
+	b *bot.Bot
+}
+
+func (c countdownClient) Post(ctx context.Context, chatID int64, text string) (int, error) {
+	message, err := c.b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:              chatID,
+		Text:                text,
+		DisableNotification: true,
+	})
+	if err != nil {
+		return 0, schedulerError(err)
+	}
+	return message.ID, nil
+}
+
+func (c countdownClient) Edit(ctx context.Context, chatID int64, messageID int, text string) error {
+	_, err := c.b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+		Text:      text,
+	})
+	return schedulerError(err)
+}
+
+func (c countdownClient) Pin(ctx context.Context, chatID int64, messageID int) error {
+	_, err := c.b.PinChatMessage(ctx, &bot.PinChatMessageParams{
+		ChatID:              chatID,
+		MessageID:           messageID,
+		DisableNotification: true,
+	})
+	return schedulerError(err)
+}
+
+func (c countdownClient) Unpin(ctx context.Context, chatID int64, messageID int) error {
+	_, err := c.b.UnpinChatMessage(ctx, &bot.UnpinChatMessageParams{
+		ChatID:    chatID,
+		MessageID: messageID,
+	})
+	return schedulerError(err)
+}
+
+func (c countdownClient) PinnedMessageID(ctx context.Context, chatID int64) (int, error) {
+	chat, err := c.b.GetChat(ctx, &bot.GetChatParams{ChatID: chatID})
+	if err != nil {
+		return 0, schedulerError(err)
+	}
+	if chat.PinnedMessage == nil {
+		return 0, nil
+	}
+	return chat.PinnedMessage.ID, nil
+}
+
+// schedulerError переводит ошибки Telegram в ошибки пакета scheduler
+func schedulerError(err error) error {
+	if err == nil {
+		return nil
+	}
+	var tooMany *bot.TooManyRequestsError
+	if errors.As(err, &tooMany) {
+		return &scheduler.RateLimitError{RetryAfter: time.Duration(tooMany.RetryAfter) * time.Second}
+	}
+	description := strings.ToLower(err.Error())
+	switch {
+	case strings.Contains(description, "message is not modified"):
+		return scheduler.ErrMessageNotModified
+	case strings.Contains(description, "message to edit not found"),
+		strings.Contains(description, "message to pin not found"),
+		strings.Contains(description, "message_id_invalid"):
+		return fmt.Errorf("%w: %s", scheduler.ErrMessageNotFound, err.Error())
+	}
+	return err
+}
@@ -0,0 +1,97 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+const countUpUsage = `Используйте формат:
+/countup event_name - показать режим события
+/countup event_name on - после даты события показывать, сколько времени прошло
+/countup event_name announce - то же и объявлять в чате о круглых датах: 100, 500, 1000 дней, годовщины
+/countup event_name off - вернуть обратный отсчёт
+
+Например: /set_date 2021-08-15 murmansk Переезд в Мурманск, затем /countup murmansk announce`
+
+// registerCountUpHandlers регистрирует команду режима счёта прошедшего времени
+func registerCountUpHandlers(b *bot.Bot, botName string, eventService *services.EventService, chatService *services.ChatService, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/countup"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleCountUp(ctx, b, update, eventService, chatService, permissions)
+	})
+}
+
+func handleCountUp(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, chatService *services.ChatService, permissions *services.PermissionService) {
+	chatID := update.Message.Chat.ID
+	name, rest := splitEventName(telegram.CommandArgs(update.Message.Text)[1:])
+	if name == "" || len(rest) > 1 {
+		sendMessage(ctx, b, chatID, countUpUsage)
+		return
+	}
+
+	if len(rest) == 0 {
+		event, err := eventService.GetEvent(chatID, name)
+		if err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+			return
+		}
+		sendMessage(ctx, b, chatID, describeCountUp(*event, time.Now(), chatService.Location(chatID)))
+		return
+	}
+
+	var countUp, announce bool
+	switch strings.ToLower(rest[0]) {
+	case "on":
+		countUp = true
+	case "announce":
+		countUp, announce = true, true
+	case "off":
+	default:
+		sendMessage(ctx, b, chatID, countUpUsage)
+		return
+	}
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	event, err := eventService.SetEventCountUp(chatID, name, countUp, announce)
+	switch {
+	case errors.Is(err, storage.ErrEventNotFound):
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+		return
+	case errors.Is(err, services.ErrRecurringCountUp):
+		sendMessage(ctx, b, chatID, "Для повторяющегося события счёт прошедшего времени не ведётся: после даты оно переходит к следующему повторению")
+		return
+	case err != nil:
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	sendMessage(ctx, b, chatID, describeCountUp(*event, time.Now(), chatService.Location(chatID)))
+}
+
+// describeCountUp описывает режим события и, если дата уже прошла, прошедшее время
+func describeCountUp(event models.Event, now time.Time, location *time.Location) string {
+	if !event.IsCountUp() {
+		return fmt.Sprintf("Событие '%s': обратный отсчёт до даты события", event.Title())
+	}
+	text := fmt.Sprintf("Событие '%s': после даты события показывается, сколько времени прошло", event.Title())
+	if event.AnnounceMilestones {
+		text += ", о круглых датах бот объявит в чате"
+	}
+	start, err := event.Time()
+	if err != nil || start.After(now) {
+		return text
+	}
+	elapsed := models.ElapsedSince(start, now)
+	milestone := models.NextMilestone(start, now)
+	return text + fmt.Sprintf("\nПрошло: %s (%s)\nСледующая круглая дата: %s - %s",
+		elapsed, elapsed.DescribeTotalDays(), milestone, models.FormatEventDateIn(milestone.At, location))
+}
@@ -0,0 +1,216 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+// Префиксы callback-данных подтверждения удаления: delete:yes:<event_id>:<user_id>
+const (
+	deleteConfirmPrefix = "delete:yes:"
+	deleteCancelPrefix  = "delete:no:"
+)
+
+var timePattern = regexp.MustCompile(`^\d{1,2}:\d{2}$`)
+
+// registerEditHandlers регистрирует команды изменения и удаления событий
+func registerEditHandlers(b *bot.Bot, botName string, eventService *services.EventService, permissions *services.PermissionService, menu *scheduler.CommandMenu) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/edit_date"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleEditDate(ctx, b, update, eventService, permissions, menu)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/edit_description"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleEditDescription(ctx, b, update, eventService, permissions)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/rename"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleRename(ctx, b, update, eventService, permissions, menu)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/delete"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleDelete(ctx, b, update, eventService, permissions)
+	})
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, "delete:", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleDeleteCallback(ctx, b, update, eventService, permissions, menu)
+	})
+}
+
+func handleEditDate(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService, menu *scheduler.CommandMenu) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) < 3 {
+		sendMessage(ctx, b, update.Message.Chat.ID, "Используйте формат:\n/edit_date event_name YYYY-MM-DD [HH:MM]\n/edit_date event_name DD.MM.YYYY")
+		return
+	}
+
+	name := parts[1]
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	dateStr := parts[2]
+	if len(parts) > 3 && timePattern.MatchString(parts[3]) {
+		dateStr += " " + parts[3]
+	}
+
+	parsedDate, err := models.ParseEventDate(dateStr)
+	if err != nil {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка парсинга даты: %s", err.Error()))
+		return
+	}
+	formattedDate := models.FormatEventDate(parsedDate)
+
+	if err := eventService.UpdateEventDate(update.Message.Chat.ID, name, formattedDate); err != nil {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Дата события '%s' изменена на %s", eventTitle(eventService, update.Message.Chat.ID, name), formattedDate))
+	// Меню упорядочено по дате, а прошедшее событие становится снова активным
+	menu.Refresh(ctx, update.Message.Chat.ID)
+}
+
+func handleEditDescription(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) < 2 {
+		sendMessage(ctx, b, update.Message.Chat.ID, "Используйте формат:\n/edit_description event_name [description]\nБез описания текущее описание будет удалено")
+		return
+	}
+
+	name := parts[1]
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	description := strings.Join(parts[2:], " ")
+	if err := eventService.UpdateEventDescription(update.Message.Chat.ID, name, description); err != nil {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	if description == "" {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Описание события '%s' удалено", eventTitle(eventService, update.Message.Chat.ID, name)))
+		return
+	}
+	sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Описание события '%s' обновлено", eventTitle(eventService, update.Message.Chat.ID, name)))
+}
+
+func handleRename(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService, menu *scheduler.CommandMenu) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) < 3 {
+		sendMessage(ctx, b, update.Message.Chat.ID, "Используйте формат:\n/rename old_name new_name\nНовое имя может быть на русском и из нескольких слов: /rename party Вечеринка у бабушки")
+		return
+	}
+
+	name, newName := parts[1], strings.Join(parts[2:], " ")
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+	title := eventTitle(eventService, update.Message.Chat.ID, name)
+	event, err := eventService.RenameEvent(update.Message.Chat.ID, name, trimQuotes(newName))
+	if err != nil {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Событие '%s' переименовано в '%s'. Используйте /%s для информации.", title, event.Title(), event.Name))
+	menu.Refresh(ctx, update.Message.Chat.ID)
+}
+
+func handleDelete(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) != 2 {
+		sendMessage(ctx, b, update.Message.Chat.ID, "Используйте формат:\n/delete event_name")
+		return
+	}
+
+	event, err := eventService.GetEvent(update.Message.Chat.ID, parts[1])
+	if err != nil {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Событие '%s' не найдено", parts[1]))
+		return
+	}
+	if !checkPermission(ctx, b, update.Message.Chat.ID, permissions.CanEdit(ctx, update.Message.Chat.ID, senderID(update.Message), event.EventID)) {
+		return
+	}
+
+	// Подтвердить удаление может только пользователь, запросивший его
+	var userID int64
+	if update.Message.From != nil {
+		userID = update.Message.From.ID
+	}
+	suffix := fmt.Sprintf("%s:%d", event.EventID, userID)
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID: update.Message.Chat.ID,
+		Text:   fmt.Sprintf("Удалить событие '%s' (%s)?", event.Title(), event.Date),
+		ReplyMarkup: &tgmodels.InlineKeyboardMarkup{
+			InlineKeyboard: [][]tgmodels.InlineKeyboardButton{{
+				{Text: "Удалить", CallbackData: deleteConfirmPrefix + suffix},
+				{Text: "Отмена", CallbackData: deleteCancelPrefix + suffix},
+			}},
+		},
+	})
+}
+
+func handleDeleteCallback(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService, menu *scheduler.CommandMenu) {
+	query := update.CallbackQuery
+	if query == nil || query.Message.Message == nil {
+		return
+	}
+	message := query.Message.Message
+
+	confirmed := strings.HasPrefix(query.Data, deleteConfirmPrefix)
+	payload := strings.TrimPrefix(strings.TrimPrefix(query.Data, deleteConfirmPrefix), deleteCancelPrefix)
+	eventID, userIDStr, ok := strings.Cut(payload, ":")
+	requesterID, err := strconv.ParseInt(userIDStr, 10, 64)
+	if !ok || err != nil {
+		logger.Warn("Некорректные данные подтверждения удаления", zap.String("data", query.Data))
+		answerCallback(ctx, b, query.ID, "")
+		return
+	}
+	if requesterID != 0 && requesterID != query.From.ID {
+		answerCallback(ctx, b, query.ID, "Подтвердить удаление может только автор запроса")
+		return
+	}
+
+	text := "Удаление отменено"
+	deleted := false
+	if confirmed {
+		// Политика чата могла измениться после запроса удаления
+		if err := permissions.CanEdit(ctx, message.Chat.ID, query.From.ID, eventID); err != nil {
+			answerCallback(ctx, b, query.ID, "Недостаточно прав для удаления события")
+			return
+		}
+		text = "Событие удалено"
+		if err := eventService.DeleteEvent(message.Chat.ID, eventID); err != nil {
+			text = fmt.Sprintf("Ошибка: %s", err.Error())
+		} else {
+			deleted = true
+		}
+	}
+	answerCallback(ctx, b, query.ID, "")
+	b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    message.Chat.ID,
+		MessageID: message.ID,
+		Text:      text,
+	})
+	if deleted {
+		menu.Refresh(ctx, message.Chat.ID)
+	}
+}
+
+// eventTitle возвращает отображаемое имя события чата или name, если событие не найдено
+func eventTitle(eventService *services.EventService, chatID int64, name string) string {
+	if event, err := eventService.GetEvent(chatID, name); err == nil {
+		return event.Title()
+	}
+	return name
+}
+
+func answerCallback(ctx context.Context, b *bot.Bot, queryID, text string) {
+	b.AnswerCallbackQuery(ctx, &bot.AnswerCallbackQueryParams{
+		CallbackQueryID: queryID,
+		Text:            text,
+	})
+}
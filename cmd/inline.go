@@ -0,0 +1,123 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+// inlineCacheSeconds - сколько Telegram хранит ответ на inline-запрос пользователя
+const inlineCacheSeconds = 30
+
+// registerInlineHandlers регистрирует inline-режим: @bot_username имя_события
+func registerInlineHandlers(b *bot.Bot, inlineService *services.InlineService, chatService *services.ChatService) {
+	b.RegisterHandlerMatchFunc(func(update *tgmodels.Update) bool {
+		return update.InlineQuery != nil
+	}, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleInlineQuery(ctx, b, update, inlineService, chatService)
+	})
+}
+
+func handleInlineQuery(ctx context.Context, b *bot.Bot, update *tgmodels.Update, inlineService *services.InlineService, chatService *services.ChatService) {
+	query := update.InlineQuery
+	if query.From == nil {
+		return
+	}
+
+	now := time.Now()
+	events, err := inlineService.Search(ctx, query.From.ID, query.Query, now)
+	if err != nil {
+		logger.Error("Ошибка inline-поиска", zap.Int64("user_id", query.From.ID), zap.Error(err))
+		return
+	}
+
+	results := make([]tgmodels.InlineQueryResult, 0, len(events))
+	for _, event := range events {
+		// Ответ уходит в произвольный чат, поэтому даты показываются в часовом поясе чата события
+		location := chatService.Location(event.ChatID)
+		text, err := eventInfoText(event, now, location)
+		if err != nil {
+			continue
+		}
+		results = append(results, &tgmodels.InlineQueryResultArticle{
+			ID:                  event.EventID,
+			Title:               inlineTitle(event, now),
+			Description:         inlineDescription(event, location),
+			InputMessageContent: &tgmodels.InputTextMessageContent{MessageText: text},
+		})
+	}
+
+	_, err = b.AnswerInlineQuery(ctx, &bot.AnswerInlineQueryParams{
+		InlineQueryID: query.ID,
+		Results:       results,
+		CacheTime:     inlineCacheSeconds,
+		IsPersonal:    true,
+	})
+	if err != nil {
+		logger.Error("Ошибка ответа на inline-запрос", zap.Error(err))
+	}
+}
+
+// inlineTitle - заголовок результата: имя события и время до ближайшей даты
+func inlineTitle(event models.Event, now time.Time) string {
+	next, upcoming, err := event.NextOccurrence(now)
+	if err == nil && !upcoming && event.IsCountUp() {
+		return fmt.Sprintf("%s - прошло %s", event.Title(), models.ElapsedSince(next, now).DescribeTotalDays())
+	}
+	if err != nil || !upcoming {
+		return fmt.Sprintf("%s - прошло", event.Title())
+	}
+	if remaining := next.Sub(now); remaining >= time.Minute {
+		return fmt.Sprintf("%s - %s", event.Title(), models.DescribeTimeUntil(remaining))
+	}
+	return fmt.Sprintf("%s - наступает", event.Title())
+}
+
+// inlineDescription - подпись результата: дата и описание события
+func inlineDescription(event models.Event, location *time.Location) string {
+	description := event.Date
+	if start, err := event.Time(); err == nil {
+		description = models.FormatEventDateIn(start, location)
+	}
+	if event.Description != "" {
+		description += " · " + event.Description
+	}
+	return description
+}
+
+// rememberMembers запоминает авторов сообщений как участников чатов, чтобы inline-режим
+// знал, события каких чатов показывать пользователю
+func rememberMembers(userService *services.UserService) bot.Middleware {
+	return func(next bot.HandlerFunc) bot.HandlerFunc {
+		return func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+			if message := update.Message; message != nil && message.From != nil && !message.From.IsBot {
+				userService.RememberMember(message.Chat.ID, message.From.ID)
+			}
+			next(ctx, b, update)
+		}
+	}
+}
+
+// chatMemberChecker проверяет через getChatMember, что пользователь всё ещё состоит в группе
+func chatMemberChecker(b *bot.Bot) services.MemberCheckFunc {
+	return func(ctx context.Context, chatID, userID int64) (bool, error) {
+		member, err := b.GetChatMember(ctx, &bot.GetChatMemberParams{ChatID: chatID, UserID: userID})
+		if err != nil {
+			return false, err
+		}
+		switch member.Type {
+		case tgmodels.ChatMemberTypeOwner, tgmodels.ChatMemberTypeAdministrator, tgmodels.ChatMemberTypeMember:
+			return true, nil
+		case tgmodels.ChatMemberTypeRestricted:
+			return member.Restricted != nil && member.Restricted.IsMember, nil
+		default:
+			return false, nil
+		}
+	}
+}
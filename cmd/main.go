@@ -2,239 +2,451 @@ package main
 
 import (
 	"context"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
 	"os"
-	"regexp"
+	"os/signal"
 	"strings"
+	"syscall"
 	"time"
 
 	"github.com/TheReshkin/tg-bot-family/internal/config"
+	"github.com/TheReshkin/tg-bot-family/internal/lifecycle"
 	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
 	"github.com/TheReshkin/tg-bot-family/internal/services"
 	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
 	"github.com/go-telegram/bot"
 	tgmodels "github.com/go-telegram/bot/models"
-	"github.com/joho/godotenv"
 	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 var logger *zap.Logger
 
 func main() {
-	// Инициализация логгера
-	var err error
-	logger, err = zap.NewProduction()
+	importPath := flag.String("import-json", "", "перенести данные из events.json в хранилище STORAGE_BACKEND и завершить работу")
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML-файл конфигурации (переменные окружения имеют приоритет)")
+	flag.Parse()
+
+	// Конфигурация загружается один раз и дальше передаётся явно
+	cfg, err := config.Load(*configPath)
+	if err != nil {
+		log.Fatalf("Некорректная конфигурация:\n%v", err)
+	}
+
+	// Инициализация логгера; сервисы и хранилище используют его через zap.L()
+	logger, err = newLogger(cfg.LogLevel)
 	if err != nil {
 		log.Fatalf("Не удалось инициализировать логгер: %v", err)
 	}
 	defer logger.Sync()
+	zap.ReplaceGlobals(logger)
+
+	if err := models.SetDefaultTimeZone(cfg.DefaultTimeZone); err != nil {
+		logger.Fatal("Некорректный часовой пояс по умолчанию", zap.Error(err))
+	}
 
-	err = godotenv.Load()
+	// Инициализация storage
+	store, err := newStorage(cfg.Storage)
 	if err != nil {
-		logger.Error("Ошибка при загрузке .env файла", zap.Error(err))
+		logger.Fatal("Не удалось инициализировать хранилище", zap.Error(err))
 	}
 
-	telegramToken := os.Getenv("TELEGRAM_TOKEN")
-	if telegramToken == "" {
-		logger.Fatal("TELEGRAM_TOKEN не задан")
+	if *importPath != "" {
+		err := importJSON(store, *importPath)
+		closeStorage(store)
+		if err != nil {
+			logger.Fatal("Ошибка импорта events.json", zap.Error(err))
+		}
+		return
 	}
 
+	// Корневой контекст отменяется по SIGINT/SIGTERM (docker stop, обновление Watchtower)
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	// handlers учитывает выполняющиеся обработчики обновлений, background - фоновые циклы
+	var handlers, background lifecycle.Tracker
+
+	// Участники чатов запоминаются для inline-режима ещё до обработчиков команд
+	userService := services.NewUserService(store)
+
 	// Инициализация бота
-	b, err := bot.New(telegramToken)
+	b, err := bot.New(cfg.TelegramToken, bot.WithMiddlewares(trackHandlers(&handlers), rememberMembers(userService)))
 	if err != nil {
 		log.Fatal(err)
 	}
 
 	// Получение имени бота
-	me, err := b.GetMe(context.Background())
+	me, err := b.GetMe(ctx)
 	if err != nil {
 		logger.Fatal("Не удалось получить информацию о боте", zap.Error(err))
 	}
 	botName := me.Username
 	logger.Info("Бот инициализирован", zap.String("bot_name", botName))
 
-	// Инициализация storage и сервисов
-	store := storage.NewJSONStorage()
+	// Инициализация сервисов
 	eventService := services.NewEventService(store)
-	userService := services.NewUserService(store)
+	catalogService := services.NewCatalogService(store)
+	reminderService := services.NewReminderService(store)
+	chatService := services.NewChatService(store)
+	conversationService := services.NewConversationService(store, cfg.ConversationTimeout)
+	permissionService := services.NewPermissionService(store, services.PermissionOptions{
+		SuperAdmins:   cfg.AdminIDs,
+		ChatAdmins:    chatAdministrators(b),
+		AdminCacheTTL: cfg.AdminCacheTTL,
+	})
+	// Меню "/" каждого чата: системные команды и активные события
+	commandMenu := scheduler.NewCommandMenu(eventService, chatService, commandMenuClient{b: b},
+		scheduler.SystemClock{}, baseCommands)
+	wizard := &wizardHandler{
+		events:        eventService,
+		users:         userService,
+		chats:         chatService,
+		conversations: conversationService,
+		permissions:   permissionService,
+		menu:          commandMenu,
+	}
+
+	// Прежний общий тестовый чат превращается в каталог, на который подписаны известные чаты
+	if cfg.TestChatID != 0 {
+		if err := catalogService.MigrateTestChat(cfg.TestChatID, cfg.TestChatCatalog); err != nil {
+			logger.Error("Ошибка переноса тестового чата в каталог", zap.Error(err))
+		}
+	}
 
-	// Загрузка существующих команд
-	loadExistingCommands(b, eventService)
+	// Фоновое обновление статусов, чтобы /active и /outdated не зависели от запросов к событиям
+	background.Go(func() {
+		eventService.RunStatusSweeper(ctx, cfg.StatusSweepInterval)
+	})
 
-	// Регистрация команд
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/set_date", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-		handleSetDate(ctx, b, update, eventService, userService)
+	// Планировщик напоминаний о приближающихся событиях
+	reminderScheduler := scheduler.NewReminderScheduler(eventService, reminderService,
+		func(ctx context.Context, chatID int64, text string) error {
+			_, err := b.SendMessage(ctx, &bot.SendMessageParams{ChatID: chatID, Text: text})
+			return err
+		},
+		scheduler.SystemClock{},
+		scheduler.ReminderOptions{Defaults: cfg.DefaultReminders(), ChatLocation: chatService.Location})
+	background.Go(func() {
+		reminderScheduler.Run(ctx, cfg.Reminders.CheckInterval)
 	})
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/list", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-		handleList(ctx, b, update, eventService)
+
+	// Объявления о круглых датах с событий в режиме счёта прошедшего времени
+	milestoneScheduler := scheduler.NewMilestoneScheduler(eventService, reminderService,
+		func(ctx context.Context, chatID int64, text string) error {
+			_, err := b.SendMessage(ctx, &bot.SendMessageParams{ChatID: chatID, Text: text})
+			return err
+		},
+		scheduler.SystemClock{},
+		scheduler.MilestoneOptions{ChatLocation: chatService.Location})
+	background.Go(func() {
+		milestoneScheduler.Run(ctx, cfg.Reminders.CheckInterval)
 	})
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/all", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-		handleAll(ctx, b, update, eventService)
+
+	// Закреплённые сообщения с обратным отсчётом до ближайших событий
+	countdownScheduler := scheduler.NewCountdownScheduler(eventService, services.NewCountdownService(store),
+		countdownClient{b: b}, scheduler.SystemClock{},
+		scheduler.CountdownOptions{ChatLocation: chatService.Location})
+	background.Go(func() {
+		countdownScheduler.Run(ctx, cfg.CountdownInterval)
 	})
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/active", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-		handleActive(ctx, b, update, eventService)
+
+	// Незавершённые диалоги создания событий закрываются по таймауту
+	background.Go(func() {
+		conversationService.RunExpirer(ctx, time.Minute, func(conversation models.Conversation) {
+			wizard.expireConversation(ctx, b, conversation)
+		})
+	})
+
+	// Общее меню команд; меню чатов с событиями синхронизируются при запуске и затем периодически,
+	// чтобы прошедшие события из них исчезали
+	loadExistingCommands(b)
+	background.Go(func() {
+		commandMenu.Run(ctx, cfg.CommandMenuInterval)
+	})
+
+	// Регистрация команд; пошаговое создание регистрируется раньше /set_date,
+	// чтобы перехватить /set_date без аргументов
+	registerWizardHandlers(b, botName, wizard)
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/set_date"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleSetDate(ctx, b, update, eventService, userService, chatService, permissionService, commandMenu)
 	})
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/outdated", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-		handleOutdated(ctx, b, update, eventService)
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/list"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleList(ctx, b, update, eventService, chatService)
 	})
-	b.RegisterHandler(bot.HandlerTypeMessageText, "/help", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/all"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleAll(ctx, b, update, eventService, chatService)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/active"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleActive(ctx, b, update, eventService, chatService)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/outdated"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleOutdated(ctx, b, update, eventService, chatService)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/help"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
 		handleHelp(ctx, b, update)
 	})
 
+	registerEditHandlers(b, botName, eventService, permissionService, commandMenu)
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/remind"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleRemind(ctx, b, update, eventService, permissionService, cfg.DefaultReminders())
+	})
+	registerTimeZoneHandlers(b, botName, eventService, chatService, permissionService)
+	registerCatalogHandlers(b, botName, eventService, catalogService, permissionService)
+	registerVisibilityHandlers(b, botName, eventService, permissionService)
+	registerAliasHandlers(b, botName, eventService, permissionService)
+	registerCountUpHandlers(b, botName, eventService, chatService, permissionService)
+	registerSuggestionHandlers(b, eventService, chatService)
+	registerPolicyHandlers(b, botName, chatService, permissionService)
+	registerCountdownHandlers(b, botName, countdownScheduler, permissionService)
+	registerInlineHandlers(b, services.NewInlineService(eventService, userService,
+		services.InlineOptions{IsMember: chatMemberChecker(b)}), chatService)
+
 	// Обработчик для динамических команд - регистрируем последним
 	b.RegisterHandler(bot.HandlerTypeMessageText, "/", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-		handleDynamicOrUnknown(ctx, b, update, eventService)
+		handleDynamicOrUnknown(ctx, b, update, botName, eventService, chatService)
 	})
 
-	// Запуск бота
-	logger.Info("Бот запущен")
-	b.Start(context.Background())
+	// Запуск бота до получения сигнала остановки
+	logger.Info("Бот запущен", zap.String("mode", cfg.Mode))
+	switch cfg.Mode {
+	case config.ModeWebhook:
+		if err := runWebhook(ctx, b, cfg.Webhook); err != nil {
+			logger.Fatal("Ошибка работы в режиме webhook", zap.Error(err))
+		}
+	case config.ModePolling:
+		// getUpdates не работает, пока зарегистрирован webhook (например, после смены режима)
+		if _, err := b.DeleteWebhook(ctx, &bot.DeleteWebhookParams{}); err != nil {
+			logger.Warn("Не удалось снять webhook", zap.Error(err))
+		}
+		b.Start(ctx)
+	}
+
+	shutdown(store, &handlers, &background, cfg.ShutdownTimeout)
 }
 
-// normalizeCommand удаляет суффикс @bot_username из команды
-func normalizeCommand(text string) string {
-	if strings.Contains(text, "@") {
-		parts := strings.Split(text, "@")
-		return parts[0]
+// jsonImporter реализуется SQL-хранилищами, поддерживающими перенос events.json
+type jsonImporter interface {
+	ImportJSON(path string) (storage.ImportResult, error)
+}
+
+// importJSON однократно переносит events.json в выбранное SQL-хранилище
+func importJSON(store storage.Storage, path string) error {
+	importer, ok := store.(jsonImporter)
+	if !ok {
+		return errors.New("импорт поддерживается только для бэкендов postgres и sqlite")
 	}
-	return text
+	result, err := importer.ImportJSON(path)
+	if err != nil {
+		return err
+	}
+	logger.Info("Импорт events.json завершён",
+		zap.String("path", path),
+		zap.Int("chats", result.Chats),
+		zap.Int("events", result.Events),
+		zap.Int("users", result.Users),
+		zap.Int("catalogs", result.Catalogs),
+		zap.Int("skipped", result.Skipped))
+	return nil
 }
 
-func handleSetDate(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, userService *services.UserService) {
-	if update.Message == nil {
-		return
+// newLogger создаёт production-логгер с заданным уровнем (debug, info, warn, error)
+func newLogger(level string) (*zap.Logger, error) {
+	parsed, err := zapcore.ParseLevel(level)
+	if err != nil {
+		return nil, err
+	}
+	loggerConfig := zap.NewProductionConfig()
+	loggerConfig.Level = zap.NewAtomicLevelAt(parsed)
+	return loggerConfig.Build()
+}
+
+// newStorage создаёт хранилище согласно настройкам
+func newStorage(cfg config.StorageConfig) (storage.Storage, error) {
+	logger.Info("Инициализация хранилища", zap.String("backend", cfg.Backend))
+
+	switch cfg.Backend {
+	case config.StorageJSON:
+		return storage.NewJSONStorage(storage.JSONOptions{
+			Path:           cfg.JSONPath,
+			BackupCount:    cfg.BackupCount,
+			BackupInterval: cfg.BackupInterval,
+			FlushDelay:     cfg.FlushDelay,
+		}), nil
+	case config.StoragePostgres:
+		return storage.NewPostgresStorage(cfg.DatabaseURL)
+	case config.StorageSQLite:
+		return storage.NewSQLiteStorage(cfg.SQLitePath)
+	default:
+		return nil, fmt.Errorf("неизвестный бэкенд хранилища: %s", cfg.Backend)
+	}
+}
+
+// splitEventName отделяет имя события от остальных аргументов. Имя из нескольких слов
+// берётся в кавычки: "День рождения мамы" или «День рождения мамы».
+func splitEventName(args []string) (string, []string) {
+	if len(args) == 0 {
+		return "", nil
+	}
+	var closing string
+	switch {
+	case strings.HasPrefix(args[0], `"`):
+		closing = `"`
+	case strings.HasPrefix(args[0], "«"):
+		closing = "»"
+	default:
+		return args[0], args[1:]
+	}
+	for i, arg := range args {
+		if strings.HasSuffix(arg, closing) && (i > 0 || len(arg) > len(closing)) {
+			return trimQuotes(strings.Join(args[:i+1], " ")), args[i+1:]
+		}
 	}
+	return args[0], args[1:]
+}
 
-	// Нормализуем команду
-	command := normalizeCommand(update.Message.Text)
-	if !strings.HasPrefix(command, "/set_date") {
-		return // Не наша команда
+// trimQuotes убирает кавычки вокруг имени события
+func trimQuotes(name string) string {
+	name = strings.TrimSpace(name)
+	for _, pair := range [][2]string{{`"`, `"`}, {"«", "»"}} {
+		if len(name) > len(pair[0])+len(pair[1]) && strings.HasPrefix(name, pair[0]) && strings.HasSuffix(name, pair[1]) {
+			return strings.TrimSpace(name[len(pair[0]) : len(name)-len(pair[1])])
+		}
 	}
+	return name
+}
+
+const setDateUsage = `Используйте формат:
+/set_date <дата> имя [описание]
+
+Имя можно писать по-русски, имя из нескольких слов - в кавычках:
+/set_date 12 мая "День рождения мамы"
+Команда события получится транслитерацией: /den_rozhdeniya_mamy
 
-	parts := strings.Fields(command)
+Дату можно указать так:
+2025-12-31 14:30, 2025-12-31, 31.12.2025
+завтра в 18:00, послезавтра, через 3 недели, через 2 часа
+31 декабря, 12 марта 2026 в 9 утра, в следующую пятницу в 19:30
+
+Пример: /set_date завтра в 18:00 party Вечеринка
+Или отправьте /new, чтобы создать событие по шагам`
+
+func handleSetDate(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, userService *services.UserService, chatService *services.ChatService, permissions *services.PermissionService, menu *scheduler.CommandMenu) {
+	parts := telegram.CommandArgs(update.Message.Text)
 	if len(parts) < 3 {
-		sendMessage(ctx, b, update.Message.Chat.ID, "Используйте формат:\n/set_date YYYY-MM-DD HH:MM event_name [description]\n/set_date YYYY-MM-DD event_name [description]\n/set_date DD.MM.YYYY event_name [description]")
+		sendMessage(ctx, b, update.Message.Chat.ID, setDateUsage)
+		return
+	}
+	if !checkPermission(ctx, b, update.Message.Chat.ID, permissions.CanCreate(ctx, update.Message.Chat.ID, senderID(update.Message))) {
 		return
 	}
 
-	var dateStr, name, description string
-
-	// Проверяем, является ли вторая часть времени (HH:MM)
-	if len(parts) >= 4 && regexp.MustCompile(`^\d{1,2}:\d{2}$`).MatchString(parts[2]) {
-		// Формат: /set_date YYYY-MM-DD HH:MM name [desc]
-		dateStr = parts[1] + " " + parts[2]
-		name = parts[3]
-		if len(parts) > 4 {
-			description = strings.Join(parts[4:], " ")
-		}
-	} else {
-		// Формат: /set_date YYYY-MM-DD name [desc] или /set_date DD.MM.YYYY name [desc]
-		dateStr = parts[1]
-		name = parts[2]
-		if len(parts) > 3 {
-			description = strings.Join(parts[3:], " ")
+	// Дата может занимать несколько слов: "завтра в 18:00", "12 марта 2026 в 9 утра"
+	location := chatService.Location(update.Message.Chat.ID)
+	parsed, err := models.DefaultDateParser.ParseDate(parts[1:], time.Now(), location)
+	if err != nil {
+		var ambiguous *models.AmbiguousDateError
+		switch {
+		case errors.As(err, &ambiguous):
+			sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Не удалось однозначно понять дату «%s». %s", ambiguous.Input, ambiguous.Suggestion))
+		case errors.Is(err, models.ErrDateNotRecognized):
+			sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Не удалось распознать дату.\n\n%s", setDateUsage))
+		default:
+			sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка парсинга даты: %s", err.Error()))
 		}
+		return
 	}
 
-	// Парсинг и валидация даты
-	parsedDate, err := models.ParseEventDate(dateStr)
-	if err != nil {
-		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка парсинга даты: %s", err.Error()))
+	rest := parts[1+parsed.Consumed:]
+	if len(rest) == 0 {
+		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Не указано имя события.\n\n%s", setDateUsage))
 		return
 	}
+	name, rest := splitEventName(rest)
 
-	// Преобразование в новый формат для хранения
-	formattedDate := models.FormatEventDate(parsedDate)
+	// Необязательное правило повторения после имени: every year, every 2 weeks until 2030-01-01.
+	// Нераспознанное правило остаётся частью описания.
+	recurrence, consumed := models.ParseRecurrence(rest)
+	description := strings.Join(rest[consumed:], " ")
 
-	err = eventService.CreateEvent(update.Message.Chat.ID, name, formattedDate, description)
+	event, err := eventService.AddEvent(update.Message.Chat.ID, models.Event{
+		Name:        name,
+		Date:        models.FormatEventDate(parsed.Time),
+		Description: description,
+		Recurrence:  recurrence,
+	})
 	if err != nil {
 		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Ошибка: %s", err.Error()))
 		return
 	}
 
-	// Регистрация динамической команды
-	registerDynamicCommand(b, eventService, name)
-
 	// Добавление события к пользователю
-	event, _ := eventService.GetEvent(update.Message.Chat.ID, name)
-	if event != nil {
-		userService.AddEventToUser(update.Message.Chat.ID, update.Message.From.ID, *event)
-	}
+	userService.AddEventToUser(update.Message.Chat.ID, update.Message.From.ID, *event)
 
-	sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Событие '%s' добавлено! Используйте /%s для информации.", name, name))
+	reply := fmt.Sprintf("Событие '%s' добавлено! Используйте /%s для информации.\nДата: %s", event.Title(), event.Name, parsed.Echo)
+	if recurrence != nil {
+		reply += fmt.Sprintf("\nПовторяется: %s", recurrence)
+	}
+	sendMessage(ctx, b, update.Message.Chat.ID, reply)
+	menu.Refresh(ctx, update.Message.Chat.ID)
 }
 
-func handleList(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService) {
-	if update.Message == nil {
-		return
+// eventDateLabel возвращает дату события для списков в часовом поясе чата location.
+// Для повторяющегося события показывается ближайшее повторение и правило.
+func eventDateLabel(event models.Event, now time.Time, location *time.Location) string {
+	start, err := event.Time()
+	if err != nil {
+		return event.Date
 	}
-
-	// Нормализуем команду
-	command := normalizeCommand(update.Message.Text)
-	if command != "/list" {
-		return // Не наша команда
+	if event.Recurrence == nil {
+		return models.FormatEventDateIn(start, location)
 	}
+	next, upcoming, err := event.NextOccurrence(now)
+	if err != nil || !upcoming {
+		next = start
+	}
+	return fmt.Sprintf("%s, %s", models.FormatEventDateIn(next, location), event.Recurrence)
+}
 
-	// Получаем события из текущего чата
-	events, err := eventService.ListEvents(update.Message.Chat.ID)
+func handleList(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, chatService *services.ChatService) {
+	// Получаем события из текущего чата вместе с событиями каталогов из подписок
+	events, err := eventService.ListVisibleEvents(update.Message.Chat.ID)
 	if err != nil {
 		sendMessage(ctx, b, update.Message.Chat.ID, "Ошибка при получении событий")
 		return
 	}
 
-	// Если это не тестовый чат, добавляем события из тестового чата
-	testChatID := config.LoadTestChatID()
-	if update.Message.Chat.ID != testChatID {
-		testEvents, err := eventService.ListEvents(testChatID)
-		if err == nil {
-			events = append(events, testEvents...)
-		}
-	}
-
 	if len(events) == 0 {
 		sendMessage(ctx, b, update.Message.Chat.ID, "Нет событий")
 		return
 	}
 
+	now := time.Now()
+	location := chatService.Location(update.Message.Chat.ID)
 	message := "События:\n"
 	for _, event := range events {
-		message += fmt.Sprintf("- %s: %s (команда /%s)\n", event.Name, event.Date, event.Name)
+		message += fmt.Sprintf("- %s: %s (команда /%s)\n", event.Title(), eventDateLabel(event, now, location), event.Name)
 	}
 	sendMessage(ctx, b, update.Message.Chat.ID, message)
 }
 
-func handleAll(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService) {
-	handleList(ctx, b, update, eventService) // Показывает все события из текущего и тестового чатов
+func handleAll(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, chatService *services.ChatService) {
+	handleList(ctx, b, update, eventService, chatService) // Показывает события текущего чата и подписок
 }
 
-func handleActive(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService) {
-	if update.Message == nil {
-		return
-	}
-
-	// Нормализуем команду
-	command := normalizeCommand(update.Message.Text)
-	if command != "/active" {
-		return // Не наша команда
-	}
-
-	// Получаем события из текущего чата
-	events, err := eventService.ListEvents(update.Message.Chat.ID)
+func handleActive(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, chatService *services.ChatService) {
+	// Получаем события из текущего чата вместе с событиями каталогов из подписок
+	events, err := eventService.ListVisibleEvents(update.Message.Chat.ID)
 	if err != nil {
 		sendMessage(ctx, b, update.Message.Chat.ID, "Ошибка при получении событий")
 		return
 	}
 
-	// Если это не тестовый чат, добавляем события из тестового чата
-	testChatID := config.LoadTestChatID()
-	if update.Message.Chat.ID != testChatID {
-		testEvents, err := eventService.ListEvents(testChatID)
-		if err == nil {
-			events = append(events, testEvents...)
-		}
-	}
-
 	activeEvents := []models.Event{}
 	for _, event := range events {
 		if event.Status == models.StatusActive {
@@ -247,40 +459,23 @@ func handleActive(ctx context.Context, b *bot.Bot, update *tgmodels.Update, even
 		return
 	}
 
+	now := time.Now()
+	location := chatService.Location(update.Message.Chat.ID)
 	message := "Активные события:\n"
 	for _, event := range activeEvents {
-		message += fmt.Sprintf("- %s: %s\n", event.Name, event.Date)
+		message += fmt.Sprintf("- %s: %s\n", event.Title(), eventDateLabel(event, now, location))
 	}
 	sendMessage(ctx, b, update.Message.Chat.ID, message)
 }
 
-func handleOutdated(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService) {
-	if update.Message == nil {
-		return
-	}
-
-	// Нормализуем команду
-	command := normalizeCommand(update.Message.Text)
-	if command != "/outdated" {
-		return // Не наша команда
-	}
-
-	// Получаем события из текущего чата
-	events, err := eventService.ListEvents(update.Message.Chat.ID)
+func handleOutdated(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, chatService *services.ChatService) {
+	// Получаем события из текущего чата вместе с событиями каталогов из подписок
+	events, err := eventService.ListVisibleEvents(update.Message.Chat.ID)
 	if err != nil {
 		sendMessage(ctx, b, update.Message.Chat.ID, "Ошибка при получении событий")
 		return
 	}
 
-	// Если это не тестовый чат, добавляем события из тестового чата
-	testChatID := config.LoadTestChatID()
-	if update.Message.Chat.ID != testChatID {
-		testEvents, err := eventService.ListEvents(testChatID)
-		if err == nil {
-			events = append(events, testEvents...)
-		}
-	}
-
 	outdatedEvents := []models.Event{}
 	for _, event := range events {
 		if event.Status == models.StatusOutdated {
@@ -293,28 +488,41 @@ func handleOutdated(ctx context.Context, b *bot.Bot, update *tgmodels.Update, ev
 		return
 	}
 
+	now := time.Now()
+	location := chatService.Location(update.Message.Chat.ID)
 	message := "Устаревшие события:\n"
 	for _, event := range outdatedEvents {
-		message += fmt.Sprintf("- %s: %s\n", event.Name, event.Date)
+		message += fmt.Sprintf("- %s: %s\n", event.Title(), eventDateLabel(event, now, location))
 	}
 	sendMessage(ctx, b, update.Message.Chat.ID, message)
 }
 
 func handleHelp(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
-	if update.Message == nil {
-		return
-	}
-
-	// Нормализуем команду
-	command := normalizeCommand(update.Message.Text)
-	if command != "/help" {
-		return // Не наша команда
-	}
-
 	helpText := `Команды:
+/new - создать событие по шагам: имя, дата в календаре, время, описание
+/cancel - отменить создание события по шагам
 /set_date YYYY-MM-DD HH:MM event_name [description] - добавить событие с временем
+/set_date завтра в 18:00 event_name [description] - дату можно написать словами: через 3 недели, 31 декабря, в следующую пятницу, 12 марта 2026 в 9 утра
 /set_date YYYY-MM-DD event_name [description] - добавить событие (время 00:00)
 /set_date DD.MM.YYYY event_name [description] - добавить событие (старый формат)
+/set_date YYYY-MM-DD event_name every year [description] - повторяющееся событие (every day/week/month/year, every 2 weeks, until YYYY-MM-DD)
+/edit_date event_name YYYY-MM-DD [HH:MM] - изменить дату события
+/edit_description event_name [description] - изменить описание события
+/rename old_name new_name - переименовать событие
+/delete event_name - удалить событие (с подтверждением)
+/alias event_name [alias] - показать псевдонимы события или добавить ещё одну команду события
+/unalias alias - удалить псевдоним события
+/countup event_name [on|announce|off] - после даты показывать, сколько времени прошло, и объявлять о круглых датах
+/remind event_name [30d 7d 1d 1h 0|off|default] - напоминания о событии
+/timezone [Europe/Moscow|default] - часовой пояс чата
+/event_timezone event_name [Asia/Yekaterinburg|default] - часовой пояс события
+/publish catalog_name event_name - опубликовать событие в общем каталоге
+/unpublish catalog_name event_name - убрать событие из каталога
+/subscribe [catalog_name] - подписать чат на каталог или показать подписки
+/unsubscribe catalog_name - отписать чат от каталога
+/visibility event_name [private|shared chat_id...|public] - кому событие видно в других чатах
+/policy [create everyone|admins] [edit everyone|owners|admins] - кто может создавать и изменять события
+/pin_countdown [n|off] - закрепить сообщение с обратным отсчётом до n ближайших событий
 /list - список событий
 /all - все события
 /active - активные события
@@ -324,7 +532,7 @@ func handleHelp(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
 	sendMessage(ctx, b, update.Message.Chat.ID, helpText)
 }
 
-func handleDynamicOrUnknown(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService) {
+func handleDynamicOrUnknown(ctx context.Context, b *bot.Bot, update *tgmodels.Update, botName string, eventService *services.EventService, chatService *services.ChatService) {
 	if update.Message == nil {
 		logger.Debug("Получено обновление без сообщения")
 		return
@@ -334,88 +542,133 @@ func handleDynamicOrUnknown(ctx context.Context, b *bot.Bot, update *tgmodels.Up
 		logger.Debug("Сообщение не является командой")
 		return
 	}
-	command := strings.TrimPrefix(update.Message.Text, "/")
-	if strings.Contains(command, "@") {
-		parts := strings.Split(command, "@")
-		command = parts[0]
+	command, mention, _ := strings.Cut(strings.TrimPrefix(strings.Fields(update.Message.Text)[0], "/"), "@")
+	if command == "" {
+		return
+	}
+	if mention != "" && !strings.EqualFold(mention, botName) {
+		logger.Debug("Команда адресована другому боту, пропускаем", zap.String("command", command), zap.String("bot", mention))
+		return
 	}
 
 	// Проверяем, является ли команда системной
-	systemCommands := []string{"set_date", "list", "all", "active", "outdated", "help", "start"}
-	for _, sysCmd := range systemCommands {
-		if command == sysCmd {
-			logger.Debug("Системная команда, пропускаем", zap.String("command", command))
-			return
-		}
+	if models.IsSystemCommand(command) {
+		logger.Debug("Системная команда, пропускаем", zap.String("command", command))
+		return
 	}
 
 	logger.Info("Обработка динамической команды", zap.String("command", command))
-	handleDynamicCommand(ctx, b, update, command, eventService)
+	handleDynamicCommand(ctx, b, update, command, eventService, chatService)
 }
 
-func handleDynamicCommand(ctx context.Context, b *bot.Bot, update *tgmodels.Update, name string, eventService *services.EventService) {
+func handleDynamicCommand(ctx context.Context, b *bot.Bot, update *tgmodels.Update, name string, eventService *services.EventService, chatService *services.ChatService) {
 	if update.Message == nil {
 		return
 	}
 
+	chatID := update.Message.Chat.ID
+	event, err := lookupEvent(eventService, chatID, name)
+	if err != nil {
+		logger.Warn("Событие не найдено",
+			zap.String("event_name", name),
+			zap.Error(err))
+		replyEventNotFound(ctx, b, update.Message, name, eventService)
+		return
+	}
+
+	message, err := eventInfo(eventService, chatService, chatID, event)
+	if err != nil {
+		sendMessage(ctx, b, chatID, "Ошибка при расчете времени")
+		return
+	}
+	sendMessage(ctx, b, chatID, message)
+}
+
+// lookupEvent ищет событие, которое чат открывает командой /name: сначала среди событий чата,
+// затем в каталогах, на которые подписан чат, и последними - публичные события других чатов
+// и события, открытые для этого чата
+func lookupEvent(eventService *services.EventService, chatID int64, name string) (*models.Event, error) {
 	logger.Info("Поиск события",
 		zap.String("event_name", name),
-		zap.Int64("chat_id", update.Message.Chat.ID))
+		zap.Int64("chat_id", chatID))
 
-	// Сначала ищем в текущем чате
-	event, err := eventService.GetEvent(update.Message.Chat.ID, name)
+	event, err := eventService.GetEvent(chatID, name)
 	if err != nil {
-		// Если не найдено в текущем чате, ищем в других чатах
-		logger.Info("Событие не найдено в текущем чате, ищем в других чатах",
+		logger.Info("Событие не найдено в текущем чате, ищем в подписках",
 			zap.String("event_name", name))
-		var foundChatID int64
-		event, foundChatID, err = eventService.FindEventAcrossChats(name, update.Message.Chat.ID)
-		if err == nil {
-			logger.Info("Событие найдено в другом чате",
-				zap.String("event_name", name),
-				zap.Int64("found_in_chat_id", foundChatID))
-		}
+		event, err = eventService.FindSubscribedEvent(chatID, eventCommand(name))
 	}
-
 	if err != nil {
-		logger.Warn("Событие не найдено",
-			zap.String("event_name", name),
-			zap.Error(err))
-		sendMessage(ctx, b, update.Message.Chat.ID, fmt.Sprintf("Событие '%s' не найдено", name))
-		return
+		event, err = eventService.FindEventAcrossChats(eventCommand(name), chatID)
+	}
+	if err != nil {
+		return nil, err
 	}
-
 	logger.Info("Найдено событие",
 		zap.String("event_name", event.Name),
 		zap.String("date", event.Date))
+	return event, nil
+}
 
-	// Обновление статуса события в его чате
-	eventService.UpdateEventStatus(event.ChatID, name)
-
-	// Расчёт времени до события
-	parsedDate, err := models.ParseEventDate(event.Date)
+// eventInfo обновляет статус события в его чате и описывает событие для чата chatID:
+// даты показываются в часовом поясе чата, где спросили о событии
+func eventInfo(eventService *services.EventService, chatService *services.ChatService, chatID int64, event *models.Event) (string, error) {
+	eventService.UpdateEventStatus(event.ChatID, event.Name)
+	message, err := eventInfoText(*event, time.Now(), chatService.Location(chatID))
 	if err != nil {
 		logger.Error("Ошибка парсинга даты события", zap.Error(err))
-		sendMessage(ctx, b, update.Message.Chat.ID, "Ошибка при расчете времени")
-		return
 	}
+	return message, err
+}
 
-	duration := time.Until(parsedDate)
+// eventCommand возвращает команду события по набранному имени: /день_рождения ищет den_rozhdeniya
+func eventCommand(name string) string {
+	if models.IsValidEventName(name) {
+		return name
+	}
+	return models.EventSlug(name)
+}
+
+// eventInfoText описывает событие с обратным отсчётом на момент now; даты показываются
+// в часовом поясе location. Используется командой /<имя_события> и inline-режимом.
+func eventInfoText(event models.Event, now time.Time, location *time.Location) (string, error) {
+	// Расчёт времени до события (для повторяющихся - до ближайшего повторения)
+	next, upcoming, err := event.NextOccurrence(now)
+	if err != nil {
+		return "", err
+	}
+
+	duration := next.Sub(now)
 	days := int(duration.Hours() / 24)
 	hours := int(duration.Hours()) % 24
 	minutes := int(duration.Minutes()) % 60
 
-	message := fmt.Sprintf("Событие: %s\nДата: %s\n", event.Name, event.Date)
+	start, _ := event.Time()
+	message := fmt.Sprintf("Событие: %s\nДата: %s\n", event.Title(), models.FormatEventDateIn(start, location))
+	if eventLocation, err := event.Location(); err == nil && eventLocation.String() != location.String() {
+		message += fmt.Sprintf("Часовой пояс события: %s (%s)\n", eventLocation, models.FormatEventDate(start))
+	}
+	if event.Recurrence != nil {
+		message += fmt.Sprintf("Повторяется: %s\n", event.Recurrence)
+		if upcoming {
+			message += fmt.Sprintf("Ближайшая дата: %s\n", models.FormatEventDateIn(next, location))
+		}
+	}
 	if event.Description != "" {
 		message += fmt.Sprintf("Описание: %s\n", event.Description)
 	}
-	if duration > 0 {
+	switch {
+	case upcoming && duration > 0:
 		message += fmt.Sprintf("Осталось: %d дней, %d часов, %d минут", days, hours, minutes)
-	} else {
+	case event.IsCountUp() && !event.IsRecurring():
+		elapsed := models.ElapsedSince(start, now)
+		message += fmt.Sprintf("Прошло: %s (%s)", elapsed, elapsed.DescribeTotalDays())
+		milestone := models.NextMilestone(start, now)
+		message += fmt.Sprintf("\nСледующая круглая дата: %s - %s", milestone, models.FormatEventDateIn(milestone.At, location))
+	default:
 		message += "Событие прошло"
 	}
-
-	sendMessage(ctx, b, update.Message.Chat.ID, message)
+	return message, nil
 }
 
 func sendMessage(ctx context.Context, b *bot.Bot, chatID int64, text string) {
@@ -425,19 +678,11 @@ func sendMessage(ctx context.Context, b *bot.Bot, chatID int64, text string) {
 	})
 }
 
-func loadExistingCommands(b *bot.Bot, eventService *services.EventService) {
-	commands := []tgmodels.BotCommand{
-		{Command: "set_date", Description: "Добавить событие (/set_date DD.MM.YYYY name)"},
-		{Command: "list", Description: "Список событий"},
-		{Command: "all", Description: "Все события"},
-		{Command: "active", Description: "Активные события"},
-		{Command: "outdated", Description: "Устаревшие события"},
-		{Command: "help", Description: "Справка"},
-	}
-
-	logger.Info("Устанавливаем базовые команды", zap.Int("count", len(commands)))
+// loadExistingCommands устанавливает общее меню бота; его видят чаты без собственного меню
+func loadExistingCommands(b *bot.Bot) {
+	logger.Info("Устанавливаем базовые команды", zap.Int("count", len(baseCommands)))
 	_, err := b.SetMyCommands(context.Background(), &bot.SetMyCommandsParams{
-		Commands: commands,
+		Commands: botCommands(baseCommands),
 	})
 	if err != nil {
 		logger.Error("Ошибка при установке команд", zap.Error(err))
@@ -446,8 +691,22 @@ func loadExistingCommands(b *bot.Bot, eventService *services.EventService) {
 	}
 }
 
-func registerDynamicCommand(b *bot.Bot, eventService *services.EventService, name string) {
-	logger.Info("Динамическая команда зарегистрирована локально",
-		zap.String("event_name", name))
-	// Больше не регистрируем глобальные команды - обрабатываем динамически
+// chatAdministrators возвращает загрузчик администраторов группы через getChatAdministrators
+func chatAdministrators(b *bot.Bot) services.ChatAdminsFunc {
+	return func(ctx context.Context, chatID int64) ([]int64, error) {
+		members, err := b.GetChatAdministrators(ctx, &bot.GetChatAdministratorsParams{ChatID: chatID})
+		if err != nil {
+			return nil, err
+		}
+		ids := make([]int64, 0, len(members))
+		for _, member := range members {
+			switch {
+			case member.Owner != nil && member.Owner.User != nil:
+				ids = append(ids, member.Owner.User.ID)
+			case member.Administrator != nil:
+				ids = append(ids, member.Administrator.User.ID)
+			}
+		}
+		return ids, nil
+	}
 }
@@ -0,0 +1,106 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+const policyUsage = `Используйте формат:
+/policy - показать, кто может создавать и изменять события
+/policy create everyone|admins - кто может создавать события
+/policy edit everyone|owners|admins - кто может изменять и удалять события (owners - создатель события)
+
+Менять политики могут только администраторы чата`
+
+// registerPolicyHandlers регистрирует команду настройки прав в чате
+func registerPolicyHandlers(b *bot.Bot, botName string, chatService *services.ChatService, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/policy"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handlePolicy(ctx, b, update, chatService, permissions)
+	})
+}
+
+func handlePolicy(ctx context.Context, b *bot.Bot, update *tgmodels.Update, chatService *services.ChatService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	chatID := update.Message.Chat.ID
+	if len(parts) == 3 {
+		policy, err := models.ParsePermissionPolicy(parts[2])
+		if err != nil {
+			sendMessage(ctx, b, chatID, policyUsage)
+			return
+		}
+		if !checkPermission(ctx, b, chatID, permissions.CanManageChat(ctx, chatID, senderID(update.Message))) {
+			return
+		}
+
+		switch parts[1] {
+		case "create":
+			err = chatService.SetCreatePolicy(chatID, policy)
+		case "edit":
+			err = chatService.SetEditPolicy(chatID, policy)
+		default:
+			sendMessage(ctx, b, chatID, policyUsage)
+			return
+		}
+		if errors.Is(err, services.ErrInvalidCreatePolicy) {
+			sendMessage(ctx, b, chatID, "Для создания событий доступны только политики everyone и admins")
+			return
+		}
+		if err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+			return
+		}
+	} else if len(parts) != 1 {
+		sendMessage(ctx, b, chatID, policyUsage)
+		return
+	}
+
+	settings, err := chatService.GetSettings(chatID)
+	if err != nil {
+		sendMessage(ctx, b, chatID, "Ошибка при получении настроек чата")
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Создавать события: %s\nИзменять и удалять события: %s\n\n%s",
+		models.DescribePolicy(settings.EffectiveCreatePolicy()),
+		models.DescribePolicy(settings.EffectiveEditPolicy()),
+		policyUsage))
+}
+
+// senderID возвращает ID автора сообщения; у сообщений от имени канала автора нет
+func senderID(message *tgmodels.Message) int64 {
+	if message.From == nil {
+		return 0
+	}
+	return message.From.ID
+}
+
+// allowEdit проверяет право изменить событие чата. Если событие не найдено, решение
+// остаётся за обработчиком, который сообщит об этом сам.
+func allowEdit(ctx context.Context, b *bot.Bot, message *tgmodels.Message, eventService *services.EventService, permissions *services.PermissionService, name string) bool {
+	event, err := eventService.GetEvent(message.Chat.ID, name)
+	if err != nil {
+		return true
+	}
+	return checkPermission(ctx, b, message.Chat.ID, permissions.CanEdit(ctx, message.Chat.ID, senderID(message), event.EventID))
+}
+
+// checkPermission сообщает пользователю об отказе в доступе и возвращает true, если действие разрешено
+func checkPermission(ctx context.Context, b *bot.Bot, chatID int64, err error) bool {
+	switch {
+	case err == nil:
+		return true
+	case errors.Is(err, services.ErrPermissionDenied):
+		sendMessage(ctx, b, chatID, "Недостаточно прав: политика чата не разрешает это действие. Текущие настройки - /policy")
+	default:
+		logger.Error("Ошибка проверки прав", zap.Int64("chat_id", chatID), zap.Error(err))
+		sendMessage(ctx, b, chatID, "Не удалось проверить права, попробуйте позже")
+	}
+	return false
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+const remindUsage = `Используйте формат:
+/remind event_name - показать напоминания события
+/remind event_name 30d 7d 1d 1h 0 - задать напоминания (m - минуты, h - часы, d - дни, w - недели, 0 - в момент события)
+/remind event_name off - отключить напоминания
+/remind event_name default - вернуть напоминания по умолчанию`
+
+func handleRemind(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService, defaults []string) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) < 2 {
+		sendMessage(ctx, b, update.Message.Chat.ID, remindUsage)
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	name := parts[1]
+	if len(parts) == 2 {
+		event, err := eventService.GetEvent(chatID, name)
+		if err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+			return
+		}
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Напоминания для '%s': %s", event.Title(), describeReminders(*event, defaults)))
+		return
+	}
+
+	if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+		return
+	}
+
+	var reminders []string
+	switch strings.ToLower(parts[2]) {
+	case "default":
+		reminders = nil
+	case "off":
+		reminders = []string{}
+	default:
+		reminders = parts[2:]
+	}
+
+	if err := eventService.SetEventReminders(chatID, name, reminders); err != nil {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s\n\n%s", err.Error(), remindUsage))
+		return
+	}
+	event, err := eventService.GetEvent(chatID, name)
+	if err != nil {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Напоминания для '%s' обновлены: %s", event.Title(), describeReminders(*event, defaults)))
+}
+
+// describeReminders перечисляет напоминания события по-русски
+func describeReminders(event models.Event, defaults []string) string {
+	offsets, err := event.ReminderOffsets(defaults)
+	if err != nil {
+		return "некорректная настройка"
+	}
+	if len(offsets) == 0 {
+		return "отключены"
+	}
+	descriptions := make([]string, len(offsets))
+	for i, offset := range offsets {
+		descriptions[i] = models.DescribeReminderOffset(offset)
+	}
+	text := strings.Join(descriptions, ", ")
+	if event.Reminders == nil {
+		text += " (по умолчанию)"
+	}
+	return text
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/lifecycle"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+// trackHandlers учитывает обработчики обновлений, чтобы при остановке дождаться их завершения.
+// Обработчик получает контекст без отмены: начатый ответ пользователю и запись в хранилище
+// доводятся до конца, а время на это ограничивает SHUTDOWN_TIMEOUT.
+func trackHandlers(handlers *lifecycle.Tracker) bot.Middleware {
+	return func(next bot.HandlerFunc) bot.HandlerFunc {
+		return func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+			if !handlers.Enter() {
+				logger.Warn("Обновление пропущено: бот останавливается", zap.Int64("update_id", update.ID))
+				return
+			}
+			defer handlers.Leave()
+			next(context.WithoutCancel(ctx), b, update)
+		}
+	}
+}
+
+// shutdown дожидается выполняющихся обработчиков и фоновых задач и закрывает хранилище.
+// Всё вместе ограничено timeout (SHUTDOWN_TIMEOUT).
+func shutdown(store storage.Storage, handlers, background *lifecycle.Tracker, timeout time.Duration) {
+	logger.Info("Остановка бота", zap.Duration("timeout", timeout))
+	started := time.Now()
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+
+	if err := handlers.Wait(ctx); err != nil {
+		logger.Warn("Не все обработчики обновлений завершились до истечения таймаута", zap.Error(err))
+	}
+	if err := background.Wait(ctx); err != nil {
+		logger.Warn("Не все фоновые задачи завершились до истечения таймаута", zap.Error(err))
+	}
+	closeStorage(store)
+
+	logger.Info("Бот остановлен", zap.Duration("elapsed", time.Since(started)))
+}
+
+// closeStorage закрывает хранилище: JSONStorage дожидается текущей записи файла,
+// SQL-хранилища закрывают соединения
+func closeStorage(store storage.Storage) {
+	closer, ok := store.(io.Closer)
+	if !ok {
+		return
+	}
+	if err := closer.Close(); err != nil {
+		logger.Error("Ошибка закрытия хранилища", zap.Error(err))
+	}
+}
@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+// suggestionPrefix - префикс callback-данных кнопки подсказки: suggest:<команда события>
+const suggestionPrefix = "suggest:"
+
+// maxCallbackData - ограничение Telegram на длину callback-данных кнопки в байтах
+const maxCallbackData = 64
+
+// registerSuggestionHandlers регистрирует кнопки подсказок для ненайденных событий
+func registerSuggestionHandlers(b *bot.Bot, eventService *services.EventService, chatService *services.ChatService) {
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, suggestionPrefix, bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleSuggestionCallback(ctx, b, update, eventService, chatService)
+	})
+}
+
+// replyEventNotFound отвечает на команду ненайденного события кнопками с похожими событиями чата.
+// В группах команду без похожих событий, скорее всего, набрали для другого бота, поэтому
+// бот промолчит; в личном чате он сообщит, что событие не найдено.
+func replyEventNotFound(ctx context.Context, b *bot.Bot, message *tgmodels.Message, name string, eventService *services.EventService) {
+	chatID := message.Chat.ID
+	suggestions, err := eventService.SuggestEvents(chatID, name, services.MaxSuggestions)
+	if err != nil {
+		suggestions = nil
+	}
+
+	var rows [][]tgmodels.InlineKeyboardButton
+	for _, suggestion := range suggestions {
+		data := suggestionPrefix + suggestion.Command
+		if len(data) > maxCallbackData {
+			continue
+		}
+		text := "/" + suggestion.Command
+		if title := suggestion.Event.Title(); title != suggestion.Command {
+			text += " · " + title
+		}
+		rows = append(rows, []tgmodels.InlineKeyboardButton{{Text: text, CallbackData: data}})
+	}
+
+	if len(rows) == 0 {
+		if message.Chat.Type != tgmodels.ChatTypePrivate {
+			logger.Debug("Неизвестная команда в группе, пропускаем",
+				zap.Int64("chat_id", chatID),
+				zap.String("command", name))
+			return
+		}
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+		return
+	}
+	b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:      chatID,
+		Text:        fmt.Sprintf("Событие '%s' не найдено. Возможно, вы имели в виду:", name),
+		ReplyMarkup: &tgmodels.InlineKeyboardMarkup{InlineKeyboard: rows},
+	})
+}
+
+// handleSuggestionCallback показывает выбранное событие вместо сообщения с подсказками
+func handleSuggestionCallback(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, chatService *services.ChatService) {
+	query := update.CallbackQuery
+	if query == nil || query.Message.Message == nil {
+		return
+	}
+	message := query.Message.Message
+	name := strings.TrimPrefix(query.Data, suggestionPrefix)
+
+	event, err := lookupEvent(eventService, message.Chat.ID, name)
+	if err != nil {
+		answerCallback(ctx, b, query.ID, fmt.Sprintf("Событие '%s' больше не найдено", name))
+		return
+	}
+	text, err := eventInfo(eventService, chatService, message.Chat.ID, event)
+	if err != nil {
+		answerCallback(ctx, b, query.ID, "Ошибка при расчете времени")
+		return
+	}
+	answerCallback(ctx, b, query.ID, "")
+	b.EditMessageText(ctx, &bot.EditMessageTextParams{
+		ChatID:    message.Chat.ID,
+		MessageID: message.ID,
+		Text:      text,
+	})
+}
@@ -0,0 +1,116 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+const timeZoneUsage = `Используйте формат:
+/timezone - показать часовой пояс чата
+/timezone Asia/Yekaterinburg - задать часовой пояс чата (название из базы IANA)
+/timezone default - вернуть часовой пояс по умолчанию (%s)`
+
+const eventTimeZoneUsage = `Используйте формат:
+/event_timezone event_name - показать часовой пояс события
+/event_timezone event_name Asia/Yekaterinburg - дата события указана по этому часовому поясу
+/event_timezone event_name default - вернуть часовой пояс по умолчанию (%s)`
+
+// registerTimeZoneHandlers регистрирует команды настройки часовых поясов
+func registerTimeZoneHandlers(b *bot.Bot, botName string, eventService *services.EventService, chatService *services.ChatService, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/timezone"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleTimeZone(ctx, b, update, chatService, permissions)
+	})
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/event_timezone"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleEventTimeZone(ctx, b, update, eventService, permissions)
+	})
+}
+
+func handleTimeZone(ctx context.Context, b *bot.Bot, update *tgmodels.Update, chatService *services.ChatService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+
+	chatID := update.Message.Chat.ID
+	if len(parts) == 1 {
+		settings, err := chatService.GetSettings(chatID)
+		if err != nil {
+			sendMessage(ctx, b, chatID, "Ошибка при получении настроек чата")
+			return
+		}
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Часовой пояс чата: %s\n\n%s", describeTimeZone(settings.TimeZone), timeZoneUsageText()))
+		return
+	}
+	if len(parts) != 2 {
+		sendMessage(ctx, b, chatID, timeZoneUsageText())
+		return
+	}
+
+	// Часовой пояс меняет все даты в чате, поэтому это настройка чата, как /policy
+	if !checkPermission(ctx, b, chatID, permissions.CanManageChat(ctx, chatID, senderID(update.Message))) {
+		return
+	}
+	timeZone := parts[1]
+	if strings.EqualFold(timeZone, "default") {
+		timeZone = ""
+	}
+	timeZone, err := chatService.SetTimeZone(chatID, timeZone)
+	if err != nil {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s\n\n%s", err.Error(), timeZoneUsageText()))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Часовой пояс чата: %s. Даты новых событий будут указываться по нему, а все даты - показываться в нём.", describeTimeZone(timeZone)))
+}
+
+func handleEventTimeZone(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService) {
+	parts := telegram.CommandArgs(update.Message.Text)
+	if len(parts) < 2 || len(parts) > 3 {
+		sendMessage(ctx, b, update.Message.Chat.ID, eventTimeZoneUsageText())
+		return
+	}
+
+	chatID := update.Message.Chat.ID
+	name := parts[1]
+	if len(parts) == 3 {
+		if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+			return
+		}
+		timeZone := parts[2]
+		if strings.EqualFold(timeZone, "default") {
+			timeZone = ""
+		}
+		if err := eventService.SetEventTimeZone(chatID, name, timeZone); err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s\n\n%s", err.Error(), eventTimeZoneUsageText()))
+			return
+		}
+	}
+
+	event, err := eventService.GetEvent(chatID, name)
+	if err != nil {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s': %s по часовому поясу %s", event.Title(), event.Date, describeTimeZone(event.TimeZone)))
+}
+
+// describeTimeZone возвращает название часового пояса, отмечая пояс по умолчанию
+func describeTimeZone(timeZone string) string {
+	if timeZone == "" {
+		return models.DefaultTimeZoneName() + " (по умолчанию)"
+	}
+	return timeZone
+}
+
+// timeZoneUsageText возвращает подсказку /timezone с текущим часовым поясом по умолчанию
+func timeZoneUsageText() string {
+	return fmt.Sprintf(timeZoneUsage, models.DefaultTimeZoneName())
+}
+
+// eventTimeZoneUsageText возвращает подсказку /event_timezone с текущим часовым поясом по умолчанию
+func eventTimeZoneUsageText() string {
+	return fmt.Sprintf(eventTimeZoneUsage, models.DefaultTimeZoneName())
+}
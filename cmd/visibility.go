@@ -0,0 +1,81 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+const visibilityUsage = `Используйте формат:
+/visibility event_name - показать, кому видно событие
+/visibility event_name private - событие видно только в этом чате
+/visibility event_name shared chat_id [chat_id...] - событие видно в этом чате и в перечисленных
+/visibility event_name public - событие можно открыть по имени в любом чате
+
+Менять видимость могут те, кому политика чата разрешает изменять событие (/policy). ID этого чата: %d`
+
+// registerVisibilityHandlers регистрирует команду настройки видимости событий
+func registerVisibilityHandlers(b *bot.Bot, botName string, eventService *services.EventService, permissions *services.PermissionService) {
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/visibility"), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handleVisibility(ctx, b, update, eventService, permissions)
+	})
+}
+
+func handleVisibility(ctx context.Context, b *bot.Bot, update *tgmodels.Update, eventService *services.EventService, permissions *services.PermissionService) {
+	chatID := update.Message.Chat.ID
+	usage := fmt.Sprintf(visibilityUsage, chatID)
+	name, rest := splitEventName(telegram.CommandArgs(update.Message.Text)[1:])
+	if name == "" {
+		sendMessage(ctx, b, chatID, usage)
+		return
+	}
+
+	if len(rest) > 0 {
+		visibility, err := models.ParseVisibility(rest[0])
+		if err != nil {
+			sendMessage(ctx, b, chatID, usage)
+			return
+		}
+		var sharedWith []int64
+		for _, arg := range rest[1:] {
+			id, err := strconv.ParseInt(arg, 10, 64)
+			if err != nil {
+				sendMessage(ctx, b, chatID, fmt.Sprintf("Некорректный ID чата: %s\n\n%s", arg, usage))
+				return
+			}
+			sharedWith = append(sharedWith, id)
+		}
+		if (visibility == models.VisibilityShared) != (len(sharedWith) > 0) {
+			sendMessage(ctx, b, chatID, usage)
+			return
+		}
+
+		if !allowEdit(ctx, b, update.Message, eventService, permissions, name) {
+			return
+		}
+		err = eventService.SetEventVisibility(chatID, name, visibility, sharedWith)
+		switch {
+		case errors.Is(err, storage.ErrEventNotFound):
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+			return
+		case err != nil:
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+			return
+		}
+	}
+
+	event, err := eventService.GetEvent(chatID, name)
+	if err != nil {
+		sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s' не найдено", name))
+		return
+	}
+	sendMessage(ctx, b, chatID, fmt.Sprintf("Событие '%s': %s", event.Title(), event.DescribeVisibility()))
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/config"
+	"github.com/TheReshkin/tg-bot-family/internal/webhook"
+	"github.com/go-telegram/bot"
+	"go.uber.org/zap"
+)
+
+// webhookStopTimeout ограничивает снятие webhook и остановку HTTP-сервера при завершении
+const webhookStopTimeout = 10 * time.Second
+
+// webhookOptions переводит настройки webhook в параметры сервера. Настройки уже
+// проверены config.Validate; если секрет не задан, генерируется случайный.
+func webhookOptions(cfg config.WebhookConfig) webhook.Options {
+	options := webhook.Options{
+		ListenAddr:  cfg.ListenAddr,
+		Path:        cfg.Path,
+		PublicURL:   cfg.URL,
+		SecretToken: cfg.SecretToken,
+		CertFile:    cfg.TLSCert,
+		KeyFile:     cfg.TLSKey,
+		SelfSigned:  cfg.SelfSigned,
+	}
+	if options.SecretToken == "" {
+		// Секрет всё равно передаётся в setWebhook при каждом запуске, поэтому случайного достаточно
+		options.SecretToken = webhook.GenerateSecretToken()
+		logger.Warn("WEBHOOK_SECRET_TOKEN не задан, используется случайный секрет")
+	}
+	return options
+}
+
+// runWebhook принимает обновления через webhook, пока не отменён ctx.
+// При запуске webhook регистрируется в Telegram, при остановке - снимается.
+func runWebhook(ctx context.Context, b *bot.Bot, cfg config.WebhookConfig) error {
+	options := webhookOptions(cfg)
+
+	server := webhook.NewServer(options, b.WebhookHandler())
+	if err := server.Listen(); err != nil {
+		return err
+	}
+	serveErr := make(chan error, 1)
+	go func() {
+		serveErr <- server.Serve()
+	}()
+	go b.StartWebhook(ctx)
+
+	if err := webhook.Register(ctx, b, options); err != nil {
+		server.Shutdown(context.Background())
+		return err
+	}
+	logger.Info("Webhook зарегистрирован", zap.String("url", options.WebhookURL()))
+
+	var err error
+	select {
+	case <-ctx.Done():
+	case err = <-serveErr:
+		logger.Error("Webhook-сервер остановился", zap.Error(err))
+	}
+
+	stopCtx, cancel := context.WithTimeout(context.Background(), webhookStopTimeout)
+	defer cancel()
+	if err := webhook.Unregister(stopCtx, b); err != nil {
+		logger.Error("Ошибка снятия webhook", zap.Error(err))
+	} else {
+		logger.Info("Webhook снят")
+	}
+	if err := server.Shutdown(stopCtx); err != nil {
+		logger.Error("Ошибка остановки webhook-сервера", zap.Error(err))
+	}
+	return err
+}
@@ -0,0 +1,442 @@
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+// Callback-данные кнопок пошагового создания события
+const (
+	wizardPrefix     = "wiz:"
+	wizardCalendar   = "wiz:cal:"  // wiz:cal:2026-10 - показать месяц
+	wizardDay        = "wiz:day:"  // wiz:day:2026-10-21 - выбрать дату
+	wizardTime       = "wiz:time:" // wiz:time:18:00 - выбрать время
+	wizardCustomTime = "wiz:time:custom"
+	wizardNoDesc     = "wiz:nodesc"
+	wizardConfirm    = "wiz:ok"
+	wizardCancel     = "wiz:cancel"
+	wizardNoop       = "wiz:noop"
+)
+
+const (
+	wizardExpiredText   = "Время на создание события истекло. Начните заново: /new"
+	wizardCancelledText = "Создание события отменено"
+)
+
+// wizardTimes - варианты времени на шаге выбора времени
+var wizardTimes = []string{"09:00", "12:00", "15:00", "18:00", "19:00", "20:00"}
+
+var monthNames = [...]string{"", "Январь", "Февраль", "Март", "Апрель", "Май", "Июнь",
+	"Июль", "Август", "Сентябрь", "Октябрь", "Ноябрь", "Декабрь"}
+
+// wizardHandler объединяет зависимости пошагового создания события
+type wizardHandler struct {
+	events        *services.EventService
+	users         *services.UserService
+	chats         *services.ChatService
+	conversations *services.ConversationService
+	permissions   *services.PermissionService
+	menu          *scheduler.CommandMenu
+}
+
+// registerWizardHandlers регистрирует пошаговое создание события: /new (или /set_date без аргументов)
+func registerWizardHandlers(b *bot.Bot, botName string, w *wizardHandler) {
+	b.RegisterHandlerMatchFunc(func(update *tgmodels.Update) bool {
+		if !telegram.CommandMatcher(botName, "/new", "/set_date")(update) {
+			return false
+		}
+		parts := telegram.CommandArgs(update.Message.Text)
+		return parts[0] == "/new" || len(parts) == 1
+	}, w.handleStart)
+	b.RegisterHandlerMatchFunc(telegram.CommandMatcher(botName, "/cancel"), w.handleCancel)
+	b.RegisterHandler(bot.HandlerTypeCallbackQueryData, wizardPrefix, bot.MatchTypePrefix, w.handleCallback)
+	// Ответы на шаги диалога - обычные сообщения без команды
+	b.RegisterHandlerMatchFunc(func(update *tgmodels.Update) bool {
+		return update.Message != nil && update.Message.From != nil &&
+			update.Message.Text != "" && !strings.HasPrefix(update.Message.Text, "/")
+	}, w.handleText)
+}
+
+func (w *wizardHandler) handleStart(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+	if update.Message.From == nil {
+		return
+	}
+	chatID := update.Message.Chat.ID
+	if !checkPermission(ctx, b, chatID, w.permissions.CanCreate(ctx, chatID, update.Message.From.ID)) {
+		return
+	}
+	conversation, err := w.conversations.Start(chatID, update.Message.From.ID, time.Now())
+	if err != nil {
+		sendMessage(ctx, b, chatID, "Ошибка при создании диалога")
+		return
+	}
+
+	message, err := b.SendMessage(ctx, &bot.SendMessageParams{
+		ChatID:          chatID,
+		Text:            "Шаг 1/4. Как назовём событие? Ответьте на это сообщение именем события, например «День рождения мамы» или mom_birthday.\nОтменить: /cancel",
+		ReplyParameters: &tgmodels.ReplyParameters{MessageID: update.Message.ID},
+		ReplyMarkup: &tgmodels.ForceReply{
+			ForceReply:            true,
+			Selective:             true,
+			InputFieldPlaceholder: "mom_birthday",
+		},
+	})
+	if err == nil {
+		w.conversations.SetMessageID(conversation, message.ID, time.Now())
+	}
+}
+
+func (w *wizardHandler) handleCancel(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+	if update.Message.From == nil {
+		return
+	}
+	chatID := update.Message.Chat.ID
+	conversation, err := w.conversations.Active(chatID, update.Message.From.ID, time.Now())
+	if err != nil {
+		sendMessage(ctx, b, chatID, "Нечего отменять")
+		return
+	}
+	w.conversations.Finish(chatID, conversation.UserID)
+	w.clearKeyboard(ctx, b, *conversation, wizardCancelledText)
+	sendMessage(ctx, b, chatID, wizardCancelledText)
+}
+
+// handleText принимает текстовые ответы на шагах имени, даты, времени и описания
+func (w *wizardHandler) handleText(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+	chatID := update.Message.Chat.ID
+	now := time.Now()
+	conversation, err := w.conversations.Active(chatID, update.Message.From.ID, now)
+	if errors.Is(err, services.ErrConversationExpired) {
+		sendMessage(ctx, b, chatID, wizardExpiredText)
+		return
+	}
+	if err != nil {
+		return // Сообщение не относится к диалогу
+	}
+
+	text := strings.TrimSpace(update.Message.Text)
+	location := w.chats.Location(chatID)
+	switch conversation.Step {
+	case models.StepName:
+		if err := w.conversations.SetName(conversation, text, now); err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s. Имя должно быть не длиннее 64 символов и в одну строку, а имя латиницей - уникальным в чате. Попробуйте ещё раз или /cancel", err.Error()))
+			return
+		}
+	case models.StepDate:
+		// Дату можно не выбирать в календаре, а написать: "31 декабря 2026", "завтра в 18:00"
+		parsed, err := models.DefaultDateParser.ParseDate(strings.Fields(text), now, location)
+		if err != nil || parsed.Consumed != len(strings.Fields(text)) {
+			var ambiguous *models.AmbiguousDateError
+			if errors.As(err, &ambiguous) {
+				sendMessage(ctx, b, chatID, ambiguous.Suggestion)
+			} else {
+				sendMessage(ctx, b, chatID, "Не удалось распознать дату. Выберите её в календаре или напишите, например, «31 декабря 2026».")
+			}
+			return
+		}
+		if err := w.conversations.SetDate(conversation, parsed.Time.Format("2006-01-02"), now); err != nil {
+			sendMessage(ctx, b, chatID, fmt.Sprintf("Ошибка: %s", err.Error()))
+			return
+		}
+		// Если во фразе было время, шаг выбора времени пропускается
+		if clock := parsed.Time.Format("15:04"); clock != "00:00" {
+			w.conversations.SetTime(conversation, clock, now)
+		}
+	case models.StepTime:
+		if err := w.conversations.SetTime(conversation, text, now); err != nil {
+			sendMessage(ctx, b, chatID, "Не удалось распознать время. Напишите его в формате ЧЧ:ММ, например 18:30.")
+			return
+		}
+	case models.StepDescription:
+		w.conversations.SetDescription(conversation, text, now)
+	default:
+		return
+	}
+
+	// Кнопки предыдущего шага больше не нужны: следующий шаг отправляется новым сообщением
+	w.clearKeyboard(ctx, b, *conversation, "")
+	w.sendStep(ctx, b, conversation, location)
+}
+
+func (w *wizardHandler) handleCallback(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+	query := update.CallbackQuery
+	if query.Message.Message == nil {
+		return
+	}
+	message := query.Message.Message
+	chatID := message.Chat.ID
+	now := time.Now()
+
+	if query.Data == wizardNoop {
+		answerCallback(ctx, b, query.ID, "")
+		return
+	}
+
+	conversation, err := w.conversations.Active(chatID, query.From.ID, now)
+	if errors.Is(err, services.ErrConversationExpired) {
+		answerCallback(ctx, b, query.ID, "")
+		w.editStep(ctx, b, chatID, message.ID, wizardExpiredText, nil)
+		return
+	}
+	// Кнопками пользуется только тот, кто начал диалог, и только на актуальном шаге
+	if err != nil || conversation.MessageID != message.ID {
+		answerCallback(ctx, b, query.ID, "Эти кнопки относятся к чужому или завершённому диалогу")
+		return
+	}
+
+	location := w.chats.Location(chatID)
+	switch {
+	case query.Data == wizardCancel:
+		w.conversations.Finish(chatID, conversation.UserID)
+		answerCallback(ctx, b, query.ID, "")
+		w.editStep(ctx, b, chatID, message.ID, wizardCancelledText, nil)
+		return
+
+	case strings.HasPrefix(query.Data, wizardCalendar) && conversation.Step == models.StepDate:
+		month, err := time.ParseInLocation("2006-01", strings.TrimPrefix(query.Data, wizardCalendar), location)
+		if err != nil {
+			answerCallback(ctx, b, query.ID, "")
+			return
+		}
+		answerCallback(ctx, b, query.ID, "")
+		text, _ := w.renderStep(conversation, location)
+		w.editStep(ctx, b, chatID, message.ID, text, calendarKeyboard(month, now.In(location)))
+		return
+
+	case strings.HasPrefix(query.Data, wizardDay) && conversation.Step == models.StepDate:
+		err = w.conversations.SetDate(conversation, strings.TrimPrefix(query.Data, wizardDay), now)
+
+	case query.Data == wizardCustomTime && conversation.Step == models.StepTime:
+		answerCallback(ctx, b, query.ID, "")
+		w.editStep(ctx, b, chatID, message.ID,
+			fmt.Sprintf("Шаг 3/4. Дата: %s.\nОтветьте на это сообщение временем в формате ЧЧ:ММ, например 18:30.", describeDay(conversation.Date)),
+			cancelKeyboard())
+		return
+
+	case strings.HasPrefix(query.Data, wizardTime) && conversation.Step == models.StepTime:
+		err = w.conversations.SetTime(conversation, strings.TrimPrefix(query.Data, wizardTime), now)
+
+	case query.Data == wizardNoDesc && conversation.Step == models.StepDescription:
+		err = w.conversations.SetDescription(conversation, "", now)
+
+	case query.Data == wizardConfirm && conversation.Step == models.StepConfirm:
+		answerCallback(ctx, b, query.ID, "")
+		w.finish(ctx, b, conversation, message.ID)
+		return
+
+	default:
+		answerCallback(ctx, b, query.ID, "Эта кнопка уже неактуальна")
+		return
+	}
+
+	if err != nil {
+		answerCallback(ctx, b, query.ID, fmt.Sprintf("Ошибка: %s", err.Error()))
+		return
+	}
+	answerCallback(ctx, b, query.ID, "")
+	text, keyboard := w.renderStep(conversation, location)
+	w.editStep(ctx, b, chatID, message.ID, text, keyboard)
+}
+
+// finish создаёт событие из черновика и завершает диалог
+func (w *wizardHandler) finish(ctx context.Context, b *bot.Bot, conversation *models.Conversation, messageID int) {
+	chatID := conversation.ChatID
+	w.conversations.Finish(chatID, conversation.UserID)
+
+	// Политика чата могла измениться, пока шёл диалог
+	if err := w.permissions.CanCreate(ctx, chatID, conversation.UserID); err != nil {
+		w.editStep(ctx, b, chatID, messageID, "Недостаточно прав: политика чата не разрешает создавать события. Текущие настройки - /policy", nil)
+		return
+	}
+
+	event, err := w.events.AddEvent(chatID, models.Event{
+		Name:        conversation.Name,
+		Date:        conversation.EventDate(),
+		Description: conversation.Description,
+	})
+	if err != nil {
+		w.editStep(ctx, b, chatID, messageID, fmt.Sprintf("Ошибка: %s\nНачните заново: /new", err.Error()), nil)
+		return
+	}
+	w.users.AddEventToUser(chatID, conversation.UserID, *event)
+	w.menu.Refresh(ctx, chatID)
+
+	logger.Info("Событие создано через диалог",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_name", event.Name))
+	w.editStep(ctx, b, chatID, messageID,
+		fmt.Sprintf("Событие '%s' добавлено! Используйте /%s для информации.", event.Title(), event.Name), nil)
+}
+
+// sendStep отправляет сообщение текущего шага и запоминает его для проверки кнопок
+func (w *wizardHandler) sendStep(ctx context.Context, b *bot.Bot, conversation *models.Conversation, location *time.Location) {
+	text, keyboard := w.renderStep(conversation, location)
+	params := &bot.SendMessageParams{ChatID: conversation.ChatID, Text: text}
+	if keyboard != nil {
+		params.ReplyMarkup = keyboard
+	}
+	message, err := b.SendMessage(ctx, params)
+	if err != nil {
+		logger.Error("Ошибка отправки шага диалога", zap.Error(err))
+		return
+	}
+	w.conversations.SetMessageID(conversation, message.ID, time.Now())
+}
+
+// renderStep возвращает текст и кнопки текущего шага
+func (w *wizardHandler) renderStep(conversation *models.Conversation, location *time.Location) (string, *tgmodels.InlineKeyboardMarkup) {
+	now := time.Now().In(location)
+	switch conversation.Step {
+	case models.StepDate:
+		return fmt.Sprintf("Шаг 2/4. Событие '%s'. Выберите дату в календаре или напишите её ответом на это сообщение (например, «31 декабря 2026»).", conversation.Name),
+			calendarKeyboard(now, now)
+	case models.StepTime:
+		return fmt.Sprintf("Шаг 3/4. Дата: %s. Выберите время:", describeDay(conversation.Date)), timeKeyboard()
+	case models.StepDescription:
+		return "Шаг 4/4. Ответьте на это сообщение описанием события или нажмите «Без описания».",
+			&tgmodels.InlineKeyboardMarkup{InlineKeyboard: [][]tgmodels.InlineKeyboardButton{{
+				{Text: "Без описания", CallbackData: wizardNoDesc},
+				{Text: "Отмена", CallbackData: wizardCancel},
+			}}}
+	case models.StepConfirm:
+		text := fmt.Sprintf("Проверьте событие:\nИмя: %s\nДата: %s", conversation.Name, conversation.EventDate())
+		if parsed, err := models.ParseEventDateIn(conversation.EventDate(), location); err == nil {
+			text = fmt.Sprintf("Проверьте событие:\nИмя: %s\nДата: %s", conversation.Name, models.DescribeDate(parsed))
+		}
+		if conversation.Description != "" {
+			text += fmt.Sprintf("\nОписание: %s", conversation.Description)
+		}
+		return text, &tgmodels.InlineKeyboardMarkup{InlineKeyboard: [][]tgmodels.InlineKeyboardButton{{
+			{Text: "Создать", CallbackData: wizardConfirm},
+			{Text: "Отмена", CallbackData: wizardCancel},
+		}}}
+	}
+	return "", nil
+}
+
+// editStep заменяет текст и кнопки сообщения диалога; keyboard == nil убирает кнопки
+func (w *wizardHandler) editStep(ctx context.Context, b *bot.Bot, chatID int64, messageID int, text string, keyboard *tgmodels.InlineKeyboardMarkup) {
+	params := &bot.EditMessageTextParams{ChatID: chatID, MessageID: messageID, Text: text}
+	if keyboard != nil {
+		params.ReplyMarkup = keyboard
+	}
+	if _, err := b.EditMessageText(ctx, params); err != nil {
+		logger.Debug("Не удалось изменить сообщение диалога", zap.Error(err))
+	}
+}
+
+// clearKeyboard убирает кнопки из сообщения шага; text заменяет текст, если не пустой
+func (w *wizardHandler) clearKeyboard(ctx context.Context, b *bot.Bot, conversation models.Conversation, text string) {
+	if conversation.MessageID == 0 {
+		return
+	}
+	if text != "" {
+		w.editStep(ctx, b, conversation.ChatID, conversation.MessageID, text, nil)
+		return
+	}
+	b.EditMessageReplyMarkup(ctx, &bot.EditMessageReplyMarkupParams{
+		ChatID:    conversation.ChatID,
+		MessageID: conversation.MessageID,
+	})
+}
+
+// expireConversation сообщает в чат, что диалог истёк, убирая кнопки шага
+func (w *wizardHandler) expireConversation(ctx context.Context, b *bot.Bot, conversation models.Conversation) {
+	if conversation.MessageID == 0 {
+		return
+	}
+	w.editStep(ctx, b, conversation.ChatID, conversation.MessageID, wizardExpiredText, nil)
+}
+
+// calendarKeyboard строит календарь месяца month (неделя с понедельника); today отмечается точкой
+func calendarKeyboard(month, today time.Time) *tgmodels.InlineKeyboardMarkup {
+	first := time.Date(month.Year(), month.Month(), 1, 0, 0, 0, 0, month.Location())
+	noop := func(text string) tgmodels.InlineKeyboardButton {
+		return tgmodels.InlineKeyboardButton{Text: text, CallbackData: wizardNoop}
+	}
+	navigate := func(text string, target time.Time) tgmodels.InlineKeyboardButton {
+		return tgmodels.InlineKeyboardButton{Text: text, CallbackData: wizardCalendar + target.Format("2006-01")}
+	}
+
+	rows := [][]tgmodels.InlineKeyboardButton{
+		{
+			navigate("«", first.AddDate(-1, 0, 0)),
+			navigate("‹", first.AddDate(0, -1, 0)),
+			noop(fmt.Sprintf("%s %d", monthNames[first.Month()], first.Year())),
+			navigate("›", first.AddDate(0, 1, 0)),
+			navigate("»", first.AddDate(1, 0, 0)),
+		},
+		{noop("Пн"), noop("Вт"), noop("Ср"), noop("Чт"), noop("Пт"), noop("Сб"), noop("Вс")},
+	}
+
+	// Пустые клетки перед первым числом: понедельник - первый день недели
+	week := make([]tgmodels.InlineKeyboardButton, 0, 7)
+	for i := 0; i < (int(first.Weekday())+6)%7; i++ {
+		week = append(week, noop(" "))
+	}
+	for day := first; day.Month() == first.Month(); day = day.AddDate(0, 0, 1) {
+		label := fmt.Sprintf("%d", day.Day())
+		if day.Year() == today.Year() && day.YearDay() == today.YearDay() {
+			label = "•" + label
+		}
+		week = append(week, tgmodels.InlineKeyboardButton{Text: label, CallbackData: wizardDay + day.Format("2006-01-02")})
+		if len(week) == 7 {
+			rows = append(rows, week)
+			week = make([]tgmodels.InlineKeyboardButton, 0, 7)
+		}
+	}
+	if len(week) > 0 {
+		for len(week) < 7 {
+			week = append(week, noop(" "))
+		}
+		rows = append(rows, week)
+	}
+
+	rows = append(rows, []tgmodels.InlineKeyboardButton{{Text: "Отмена", CallbackData: wizardCancel}})
+	return &tgmodels.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+// timeKeyboard предлагает типичное время события
+func timeKeyboard() *tgmodels.InlineKeyboardMarkup {
+	rows := [][]tgmodels.InlineKeyboardButton{{{Text: "Весь день (00:00)", CallbackData: wizardTime + "00:00"}}}
+	var row []tgmodels.InlineKeyboardButton
+	for _, value := range wizardTimes {
+		row = append(row, tgmodels.InlineKeyboardButton{Text: value, CallbackData: wizardTime + value})
+		if len(row) == 3 {
+			rows = append(rows, row)
+			row = nil
+		}
+	}
+	rows = append(rows, []tgmodels.InlineKeyboardButton{
+		{Text: "Другое время", CallbackData: wizardCustomTime},
+		{Text: "Отмена", CallbackData: wizardCancel},
+	})
+	return &tgmodels.InlineKeyboardMarkup{InlineKeyboard: rows}
+}
+
+func cancelKeyboard() *tgmodels.InlineKeyboardMarkup {
+	return &tgmodels.InlineKeyboardMarkup{InlineKeyboard: [][]tgmodels.InlineKeyboardButton{{
+		{Text: "Отмена", CallbackData: wizardCancel},
+	}}}
+}
+
+// describeDay возвращает дату черновика (YYYY-MM-DD) словами, без времени
+func describeDay(date string) string {
+	parsed, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return date
+	}
+	description := models.DescribeDate(parsed)
+	return strings.TrimSuffix(description, ", 00:00")
+}
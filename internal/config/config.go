@@ -1,19 +1,401 @@
 package config
 
 import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/url"
 	"os"
-	"strconv"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/joho/godotenv"
+	"go.uber.org/zap/zapcore"
+	"gopkg.in/yaml.v3"
+)
+
+// TestChatID is the former shared test chat whose events were visible in every chat;
+// on startup they are migrated into the TestChatCatalog catalog
+const TestChatID int64 = 332288278
+
+// DefaultTestChatCatalog is the catalog the test chat events are migrated into
+const DefaultTestChatCatalog = "test_chat"
+
+// Поддерживаемые бэкенды хранилища
+const (
+	StorageJSON     = "json"
+	StoragePostgres = "postgres"
+	StorageSQLite   = "sqlite"
+)
+
+// Режимы получения обновлений от Telegram
+const (
+	ModePolling = "polling"
+	ModeWebhook = "webhook"
 )
 
-// TestChatID is the hardcoded chat ID used for testing and fallback searches
-var TestChatID int64 = 332288278
+// DefaultDataDir is the directory for the json and sqlite data files
+const DefaultDataDir = "./data"
+
+// DefaultWebhookListenAddr is the webhook server address when neither WEBHOOK_LISTEN_ADDR nor PORT is set
+const DefaultWebhookListenAddr = ":8080"
+
+// DefaultWebhookPath is the path Telegram posts updates to
+const DefaultWebhookPath = "/telegram/webhook"
+
+// DefaultStatusSweepInterval is how often event statuses are refreshed in the background
+const DefaultStatusSweepInterval = time.Minute
+
+// DefaultCountdownInterval is how often pinned countdown messages are recalculated
+const DefaultCountdownInterval = time.Minute
+
+// DefaultCommandMenuInterval is how often per-chat command menus are checked for expired events
+const DefaultCommandMenuInterval = time.Minute
+
+// DefaultReminderCheckInterval is how often the reminder scheduler looks for due reminders
+const DefaultReminderCheckInterval = time.Minute
+
+// DefaultConversationTimeout is how long an unfinished event-creation dialog is kept
+const DefaultConversationTimeout = 30 * time.Minute
+
+// DefaultAdminCacheTTL is how long the list of chat administrators is cached
+const DefaultAdminCacheTTL = 5 * time.Minute
+
+// DefaultShutdownTimeout is how long the bot waits for in-flight work on shutdown;
+// it is below the 10s grace period of `docker stop`
+const DefaultShutdownTimeout = 8 * time.Second
+
+// DefaultJSONBackupCount is how many rotated backups of events.json are kept
+const DefaultJSONBackupCount = 10
+
+// DefaultJSONBackupInterval is the minimal time between two backups of events.json
+const DefaultJSONBackupInterval = time.Hour
+
+// Config - настройки приложения. Загружается один раз при запуске функцией Load
+// и передаётся в хранилище и сервисы явно.
+type Config struct {
+	// TelegramToken - токен бота (TELEGRAM_BOT_TOKEN)
+	TelegramToken string `yaml:"telegram_token"`
+	// Mode - способ получения обновлений: polling или webhook (BOT_MODE)
+	Mode string `yaml:"mode"`
+	// DataDir - каталог файлов данных json и sqlite по умолчанию (DATA_DIR)
+	DataDir string `yaml:"data_dir"`
+	// LogLevel - уровень логирования: debug, info, warn, error (LOG_LEVEL)
+	LogLevel string `yaml:"log_level"`
+	// DefaultTimeZone - часовой пояс чатов и событий, для которых он не задан (DEFAULT_TIME_ZONE)
+	DefaultTimeZone string `yaml:"default_time_zone"`
+	// AdminIDs - Telegram ID администраторов бота (ADMIN_IDS, через запятую)
+	AdminIDs []int64 `yaml:"admin_ids"`
+	// TestChatID - прежний общий чат (TEST_CHAT_ID). При запуске его события переносятся
+	// в каталог TestChatCatalog, а известные чаты подписываются на него; 0 отключает перенос.
+	TestChatID int64 `yaml:"test_chat_id"`
+	// TestChatCatalog - имя каталога для событий TestChatID (TEST_CHAT_CATALOG)
+	TestChatCatalog string `yaml:"test_chat_catalog"`
+
+	Storage   StorageConfig   `yaml:"storage"`
+	Webhook   WebhookConfig   `yaml:"webhook"`
+	Reminders RemindersConfig `yaml:"reminders"`
+
+	// StatusSweepInterval - период фонового обновления статусов (STATUS_SWEEP_INTERVAL)
+	StatusSweepInterval time.Duration `yaml:"status_sweep_interval"`
+	// CountdownInterval - период пересчёта закреплённых отсчётов /pin_countdown (COUNTDOWN_INTERVAL)
+	CountdownInterval time.Duration `yaml:"countdown_interval"`
+	// CommandMenuInterval - период пересчёта меню команд чатов (COMMAND_MENU_INTERVAL)
+	CommandMenuInterval time.Duration `yaml:"command_menu_interval"`
+	// ConversationTimeout - время жизни незавершённого диалога создания события (CONVERSATION_TIMEOUT)
+	ConversationTimeout time.Duration `yaml:"conversation_timeout"`
+	// AdminCacheTTL - сколько хранится список администраторов чата из Telegram (ADMIN_CACHE_TTL)
+	AdminCacheTTL time.Duration `yaml:"admin_cache_ttl"`
+	// ShutdownTimeout - сколько ждать завершения текущей работы при остановке (SHUTDOWN_TIMEOUT)
+	ShutdownTimeout time.Duration `yaml:"shutdown_timeout"`
+}
+
+// StorageConfig - настройки хранилища
+type StorageConfig struct {
+	// Backend - json, postgres или sqlite (STORAGE_BACKEND)
+	Backend string `yaml:"backend"`
+	// DatabaseURL - строка подключения PostgreSQL (DATABASE_URL)
+	DatabaseURL string `yaml:"database_url"`
+	// SQLitePath - файл базы SQLite (SQLITE_PATH), по умолчанию DataDir/bot.db
+	SQLitePath string `yaml:"sqlite_path"`
+	// JSONPath - файл JSON-хранилища (JSON_STORAGE_PATH), по умолчанию DataDir/events.json
+	JSONPath string `yaml:"json_path"`
+	// BackupCount - сколько резервных копий events.json хранить, 0 отключает копии (JSON_BACKUP_COUNT)
+	BackupCount int `yaml:"backup_count"`
+	// BackupInterval - минимальный интервал между копиями, 0 - при каждой записи (JSON_BACKUP_INTERVAL)
+	BackupInterval time.Duration `yaml:"backup_interval"`
+	// FlushDelay - на сколько можно отложить запись events.json, 0 - сразу (JSON_FLUSH_DELAY)
+	FlushDelay time.Duration `yaml:"flush_delay"`
+}
+
+// WebhookConfig - настройки режима webhook
+type WebhookConfig struct {
+	// URL - внешний адрес бота, например https://bot.example.com (WEBHOOK_URL)
+	URL string `yaml:"url"`
+	// ListenAddr - адрес HTTP-сервера (WEBHOOK_LISTEN_ADDR или ":$PORT")
+	ListenAddr string `yaml:"listen_addr"`
+	// Path - путь, на который Telegram отправляет обновления (WEBHOOK_PATH)
+	Path string `yaml:"path"`
+	// SecretToken - секрет, который Telegram передаёт с каждым обновлением (WEBHOOK_SECRET_TOKEN)
+	SecretToken string `yaml:"secret_token"`
+	// TLSCert и TLSKey задаются, если TLS завершается на самом боте (WEBHOOK_TLS_CERT, WEBHOOK_TLS_KEY)
+	TLSCert string `yaml:"tls_cert"`
+	TLSKey  string `yaml:"tls_key"`
+	// SelfSigned - загрузить сертификат в Telegram (WEBHOOK_SELF_SIGNED)
+	SelfSigned bool `yaml:"self_signed"`
+}
+
+// RemindersConfig - настройки напоминаний
+type RemindersConfig struct {
+	// Offsets - напоминания по умолчанию (REMINDER_OFFSETS, например "30d,7d,1d,1h,0"; "off" отключает).
+	// nil означает models.DefaultReminders, пустой список отключает напоминания.
+	Offsets []string `yaml:"offsets"`
+	// CheckInterval - период проверки напоминаний (REMINDER_CHECK_INTERVAL)
+	CheckInterval time.Duration `yaml:"check_interval"`
+}
+
+// Default возвращает конфигурацию со значениями по умолчанию
+func Default() *Config {
+	return &Config{
+		Mode:            ModePolling,
+		DataDir:         DefaultDataDir,
+		LogLevel:        "info",
+		DefaultTimeZone: models.DefaultTimeZone,
+		TestChatID:      TestChatID,
+		TestChatCatalog: DefaultTestChatCatalog,
+		Storage: StorageConfig{
+			Backend:        StorageJSON,
+			BackupCount:    DefaultJSONBackupCount,
+			BackupInterval: DefaultJSONBackupInterval,
+		},
+		Webhook: WebhookConfig{
+			ListenAddr: DefaultWebhookListenAddr,
+			Path:       DefaultWebhookPath,
+		},
+		Reminders: RemindersConfig{
+			CheckInterval: DefaultReminderCheckInterval,
+		},
+		StatusSweepInterval: DefaultStatusSweepInterval,
+		CountdownInterval:   DefaultCountdownInterval,
+		CommandMenuInterval: DefaultCommandMenuInterval,
+		ConversationTimeout: DefaultConversationTimeout,
+		AdminCacheTTL:       DefaultAdminCacheTTL,
+		ShutdownTimeout:     DefaultShutdownTimeout,
+	}
+}
+
+// Load собирает конфигурацию: значения по умолчанию, затем YAML-файл yamlPath (если задан),
+// затем переменные окружения, включая файл .env в рабочем каталоге. Переменные окружения
+// имеют приоритет над YAML. Результат проверяется Validate.
+func Load(yamlPath string) (*Config, error) {
+	if err := godotenv.Load(); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, fmt.Errorf("load .env: %w", err)
+	}
+
+	cfg := Default()
+	if yamlPath != "" {
+		if err := cfg.loadYAML(yamlPath); err != nil {
+			return nil, err
+		}
+	}
+	if err := cfg.loadEnv(); err != nil {
+		return nil, err
+	}
+	if err := cfg.Validate(); err != nil {
+		return nil, err
+	}
+	return cfg, nil
+}
+
+func (c *Config) loadYAML(path string) error {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("read config file: %w", err)
+	}
+	decoder := yaml.NewDecoder(bytes.NewReader(raw))
+	// Опечатка в имени параметра - ошибка, а не молча проигнорированная настройка
+	decoder.KnownFields(true)
+	// Пустой файл - не ошибка: декодер возвращает для него io.EOF
+	if err := decoder.Decode(c); err != nil && !errors.Is(err, io.EOF) {
+		return fmt.Errorf("parse config file %s: %w", path, err)
+	}
+	return nil
+}
+
+func (c *Config) loadEnv() error {
+	env := envReader{}
+
+	// TELEGRAM_TOKEN - прежнее имя переменной, оставлено для совместимости
+	env.string("TELEGRAM_TOKEN", &c.TelegramToken)
+	env.string("TELEGRAM_BOT_TOKEN", &c.TelegramToken)
+	env.lowerString("BOT_MODE", &c.Mode)
+	env.string("DATA_DIR", &c.DataDir)
+	env.lowerString("LOG_LEVEL", &c.LogLevel)
+	env.string("DEFAULT_TIME_ZONE", &c.DefaultTimeZone)
+	env.int64List("ADMIN_IDS", &c.AdminIDs)
+	env.int64("TEST_CHAT_ID", &c.TestChatID)
+	env.string("TEST_CHAT_CATALOG", &c.TestChatCatalog)
+
+	env.lowerString("STORAGE_BACKEND", &c.Storage.Backend)
+	env.string("DATABASE_URL", &c.Storage.DatabaseURL)
+	env.string("SQLITE_PATH", &c.Storage.SQLitePath)
+	env.string("JSON_STORAGE_PATH", &c.Storage.JSONPath)
+	env.int("JSON_BACKUP_COUNT", &c.Storage.BackupCount)
+	env.duration("JSON_BACKUP_INTERVAL", &c.Storage.BackupInterval)
+	env.duration("JSON_FLUSH_DELAY", &c.Storage.FlushDelay)
+
+	env.string("WEBHOOK_URL", &c.Webhook.URL)
+	if port := os.Getenv("PORT"); port != "" {
+		c.Webhook.ListenAddr = ":" + port
+	}
+	env.string("WEBHOOK_LISTEN_ADDR", &c.Webhook.ListenAddr)
+	env.string("WEBHOOK_PATH", &c.Webhook.Path)
+	env.string("WEBHOOK_SECRET_TOKEN", &c.Webhook.SecretToken)
+	env.string("WEBHOOK_TLS_CERT", &c.Webhook.TLSCert)
+	env.string("WEBHOOK_TLS_KEY", &c.Webhook.TLSKey)
+	env.bool("WEBHOOK_SELF_SIGNED", &c.Webhook.SelfSigned)
+
+	if value, ok := env.lookup("REMINDER_OFFSETS"); ok {
+		if value == "off" {
+			c.Reminders.Offsets = []string{}
+		} else {
+			c.Reminders.Offsets = []string{value}
+		}
+	}
+	env.duration("REMINDER_CHECK_INTERVAL", &c.Reminders.CheckInterval)
+
+	env.duration("STATUS_SWEEP_INTERVAL", &c.StatusSweepInterval)
+	env.duration("COUNTDOWN_INTERVAL", &c.CountdownInterval)
+	env.duration("COMMAND_MENU_INTERVAL", &c.CommandMenuInterval)
+	env.duration("CONVERSATION_TIMEOUT", &c.ConversationTimeout)
+	env.duration("ADMIN_CACHE_TTL", &c.AdminCacheTTL)
+	env.duration("SHUTDOWN_TIMEOUT", &c.ShutdownTimeout)
+
+	return errors.Join(env.errs...)
+}
+
+// Validate проверяет конфигурацию, приводит значения к каноническому виду и заполняет
+// пути, зависящие от DataDir. Возвращает все найденные ошибки сразу.
+func (c *Config) Validate() error {
+	var errs []error
+	fail := func(format string, args ...any) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+
+	if c.TelegramToken == "" {
+		fail("TELEGRAM_BOT_TOKEN is not set")
+	}
+	if c.Mode != ModePolling && c.Mode != ModeWebhook {
+		fail("BOT_MODE: unknown mode %q (expected %s or %s)", c.Mode, ModePolling, ModeWebhook)
+	}
+	if c.DataDir == "" {
+		fail("DATA_DIR must not be empty")
+	}
+	if _, err := zapcore.ParseLevel(c.LogLevel); err != nil {
+		fail("LOG_LEVEL: unknown level %q (expected debug, info, warn or error)", c.LogLevel)
+	}
+	if timeZone, err := models.NormalizeTimeZone(c.DefaultTimeZone); err != nil {
+		fail("DEFAULT_TIME_ZONE: %v", err)
+	} else {
+		c.DefaultTimeZone = timeZone
+	}
+	for _, id := range c.AdminIDs {
+		if id == 0 {
+			fail("ADMIN_IDS: user ID must not be 0")
+		}
+	}
+
+	if c.TestChatID != 0 && !models.IsValidCatalogName(c.TestChatCatalog) {
+		fail("TEST_CHAT_CATALOG: invalid catalog name %q (letters, digits and _ only)", c.TestChatCatalog)
+	}
+
+	switch c.Storage.Backend {
+	case StorageJSON, StorageSQLite:
+	case StoragePostgres:
+		if c.Storage.DatabaseURL == "" {
+			fail("DATABASE_URL is required for the %s storage backend", StoragePostgres)
+		}
+	default:
+		fail("STORAGE_BACKEND: unknown backend %q (expected %s, %s or %s)", c.Storage.Backend, StorageJSON, StoragePostgres, StorageSQLite)
+	}
+	if c.Storage.JSONPath == "" {
+		c.Storage.JSONPath = filepath.Join(c.DataDir, "events.json")
+	}
+	if c.Storage.SQLitePath == "" {
+		c.Storage.SQLitePath = filepath.Join(c.DataDir, "bot.db")
+	}
+	if c.Storage.BackupCount < 0 {
+		fail("JSON_BACKUP_COUNT must not be negative")
+	}
+	if c.Storage.BackupInterval < 0 {
+		fail("JSON_BACKUP_INTERVAL must not be negative")
+	}
+	if c.Storage.FlushDelay < 0 {
+		fail("JSON_FLUSH_DELAY must not be negative")
+	}
+
+	if !strings.HasPrefix(c.Webhook.Path, "/") {
+		c.Webhook.Path = "/" + c.Webhook.Path
+	}
+	if c.Mode == ModeWebhook {
+		if c.Webhook.URL == "" {
+			fail("WEBHOOK_URL is required in %s mode", ModeWebhook)
+		} else if u, err := url.Parse(c.Webhook.URL); err != nil || u.Scheme != "https" || u.Host == "" {
+			fail("WEBHOOK_URL must be an https:// URL, got %q", c.Webhook.URL)
+		}
+	}
+	if (c.Webhook.TLSCert == "") != (c.Webhook.TLSKey == "") {
+		fail("WEBHOOK_TLS_CERT and WEBHOOK_TLS_KEY must be set together")
+	}
+
+	if c.Reminders.Offsets != nil {
+		offsets, err := models.NormalizeReminders(c.Reminders.Offsets)
+		if err != nil {
+			fail("REMINDER_OFFSETS: %v", err)
+		} else {
+			c.Reminders.Offsets = offsets
+		}
+	}
+
+	positive := []struct {
+		name  string
+		value time.Duration
+	}{
+		{"REMINDER_CHECK_INTERVAL", c.Reminders.CheckInterval},
+		{"STATUS_SWEEP_INTERVAL", c.StatusSweepInterval},
+		{"COUNTDOWN_INTERVAL", c.CountdownInterval},
+		{"COMMAND_MENU_INTERVAL", c.CommandMenuInterval},
+		{"CONVERSATION_TIMEOUT", c.ConversationTimeout},
+		{"ADMIN_CACHE_TTL", c.AdminCacheTTL},
+		{"SHUTDOWN_TIMEOUT", c.ShutdownTimeout},
+	}
+	for _, setting := range positive {
+		if setting.value <= 0 {
+			fail("%s must be positive, got %s", setting.name, setting.value)
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// DefaultReminders возвращает напоминания для событий без собственных настроек
+func (c *Config) DefaultReminders() []string {
+	if c.Reminders.Offsets == nil {
+		return models.DefaultReminders
+	}
+	return c.Reminders.Offsets
+}
 
-// LoadTestChatID loads the test chat ID from environment variable or uses default
-func LoadTestChatID() int64 {
-	if envValue := os.Getenv("TEST_CHAT_ID"); envValue != "" {
-		if parsed, err := strconv.ParseInt(envValue, 10, 64); err == nil {
-			return parsed
+// IsAdmin сообщает, входит ли пользователь в ADMIN_IDS
+func (c *Config) IsAdmin(userID int64) bool {
+	for _, id := range c.AdminIDs {
+		if id == userID {
+			return true
 		}
 	}
-	return TestChatID
+	return false
 }
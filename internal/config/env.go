@@ -0,0 +1,102 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// envReader переносит заданные переменные окружения в поля конфигурации и копит
+// ошибки разбора, чтобы сообщить обо всех некорректных значениях сразу
+type envReader struct {
+	errs []error
+}
+
+// lookup возвращает значение переменной без пробелов по краям; пустое значение считается незаданным
+func (r *envReader) lookup(name string) (string, bool) {
+	value := strings.TrimSpace(os.Getenv(name))
+	return value, value != ""
+}
+
+func (r *envReader) fail(name, value, expected string) {
+	r.errs = append(r.errs, fmt.Errorf("%s: invalid value %q, expected %s", name, value, expected))
+}
+
+func (r *envReader) string(name string, target *string) {
+	if value, ok := r.lookup(name); ok {
+		*target = value
+	}
+}
+
+func (r *envReader) lowerString(name string, target *string) {
+	if value, ok := r.lookup(name); ok {
+		*target = strings.ToLower(value)
+	}
+}
+
+func (r *envReader) int(name string, target *int) {
+	if value, ok := r.lookup(name); ok {
+		parsed, err := strconv.Atoi(value)
+		if err != nil {
+			r.fail(name, value, "an integer")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (r *envReader) int64(name string, target *int64) {
+	if value, ok := r.lookup(name); ok {
+		parsed, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			r.fail(name, value, "an integer")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (r *envReader) int64List(name string, target *[]int64) {
+	value, ok := r.lookup(name)
+	if !ok {
+		return
+	}
+	var list []int64
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		parsed, err := strconv.ParseInt(part, 10, 64)
+		if err != nil {
+			r.fail(name, value, "comma-separated integers")
+			return
+		}
+		list = append(list, parsed)
+	}
+	*target = list
+}
+
+func (r *envReader) bool(name string, target *bool) {
+	if value, ok := r.lookup(name); ok {
+		parsed, err := strconv.ParseBool(value)
+		if err != nil {
+			r.fail(name, value, "true or false")
+			return
+		}
+		*target = parsed
+	}
+}
+
+func (r *envReader) duration(name string, target *time.Duration) {
+	if value, ok := r.lookup(name); ok {
+		parsed, err := time.ParseDuration(value)
+		if err != nil {
+			r.fail(name, value, `a duration such as "30s" or "5m"`)
+			return
+		}
+		*target = parsed
+	}
+}
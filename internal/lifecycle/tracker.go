@@ -0,0 +1,62 @@
+package lifecycle
+
+import (
+	"context"
+	"sync"
+)
+
+// Tracker учитывает выполняющиеся задачи (обработчики обновлений, фоновые циклы),
+// чтобы при остановке бота дождаться их завершения. После начала ожидания новые задачи не принимаются.
+type Tracker struct {
+	mu      sync.Mutex
+	closed  bool
+	running sync.WaitGroup
+}
+
+// Enter регистрирует начало задачи; false означает, что идёт остановка и задачу запускать не нужно.
+// Каждый успешный Enter должен завершаться вызовом Leave.
+func (t *Tracker) Enter() bool {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return false
+	}
+	t.running.Add(1)
+	return true
+}
+
+// Leave отмечает завершение задачи
+func (t *Tracker) Leave() {
+	t.running.Done()
+}
+
+// Go запускает fn в отдельной горутине как учитываемую задачу
+func (t *Tracker) Go(fn func()) bool {
+	if !t.Enter() {
+		return false
+	}
+	go func() {
+		defer t.Leave()
+		fn()
+	}()
+	return true
+}
+
+// Wait запрещает новые задачи и ждёт завершения текущих, но не дольше, чем живёт ctx
+func (t *Tracker) Wait(ctx context.Context) error {
+	t.mu.Lock()
+	t.closed = true
+	t.mu.Unlock()
+
+	done := make(chan struct{})
+	go func() {
+		t.running.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,29 @@
+package models
+
+import "regexp"
+
+var catalogNamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]{1,64}$`)
+
+// Catalog - именованный общий каталог событий. Чат-владелец публикует в него
+// свои события, другие чаты подписываются на каталог и видят эти события у себя.
+type Catalog struct {
+	Name        string `json:"name"`
+	OwnerChatID int64  `json:"owner_chat_id"`
+	// EventIDs - опубликованные события чата-владельца в порядке публикации
+	EventIDs []string `json:"event_ids"`
+}
+
+// HasEvent сообщает, опубликовано ли событие в каталоге
+func (c Catalog) HasEvent(eventID string) bool {
+	for _, id := range c.EventIDs {
+		if id == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidCatalogName проверяет имя каталога: латинские буквы, цифры и подчёркивание
+func IsValidCatalogName(name string) bool {
+	return catalogNamePattern.MatchString(name)
+}
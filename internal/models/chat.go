@@ -0,0 +1,69 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// PermissionPolicy определяет, кто в групповом чате может выполнять действие с событиями.
+// Администраторы чата и бота могут всё независимо от политики.
+type PermissionPolicy string
+
+const (
+	// PolicyEveryone - любой участник чата
+	PolicyEveryone PermissionPolicy = "everyone"
+	// PolicyOwners - создатель события и администраторы
+	PolicyOwners PermissionPolicy = "owners"
+	// PolicyAdmins - только администраторы
+	PolicyAdmins PermissionPolicy = "admins"
+)
+
+// ChatSettings - настройки чата
+type ChatSettings struct {
+	// TimeZone - часовой пояс чата; пустая строка - часовой пояс по умолчанию
+	TimeZone string `json:"time_zone,omitempty"`
+	// CreatePolicy - кто может создавать события; пустая строка - PolicyEveryone
+	CreatePolicy PermissionPolicy `json:"create_policy,omitempty"`
+	// EditPolicy - кто может изменять и удалять события; пустая строка - PolicyOwners
+	EditPolicy PermissionPolicy `json:"edit_policy,omitempty"`
+}
+
+// EffectiveCreatePolicy возвращает политику создания событий с учётом значения по умолчанию
+func (s ChatSettings) EffectiveCreatePolicy() PermissionPolicy {
+	if s.CreatePolicy == "" {
+		return PolicyEveryone
+	}
+	return s.CreatePolicy
+}
+
+// EffectiveEditPolicy возвращает политику изменения событий с учётом значения по умолчанию
+func (s ChatSettings) EffectiveEditPolicy() PermissionPolicy {
+	if s.EditPolicy == "" {
+		return PolicyOwners
+	}
+	return s.EditPolicy
+}
+
+// ParsePermissionPolicy разбирает название политики; регистр не важен
+func ParsePermissionPolicy(value string) (PermissionPolicy, error) {
+	switch policy := PermissionPolicy(strings.ToLower(value)); policy {
+	case PolicyEveryone, PolicyOwners, PolicyAdmins:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("unknown policy: %s", value)
+	}
+}
+
+// DescribePolicy описывает политику по-русски
+func DescribePolicy(policy PermissionPolicy) string {
+	switch policy {
+	case PolicyEveryone:
+		return "все участники"
+	case PolicyOwners:
+		return "создатель события и администраторы"
+	case PolicyAdmins:
+		return "только администраторы"
+	default:
+		return string(policy)
+	}
+}
@@ -0,0 +1,44 @@
+package models
+
+import "time"
+
+// ConversationStep - шаг пошагового создания события
+type ConversationStep string
+
+const (
+	StepName        ConversationStep = "name"
+	StepDate        ConversationStep = "date"
+	StepTime        ConversationStep = "time"
+	StepDescription ConversationStep = "description"
+	StepConfirm     ConversationStep = "confirm"
+)
+
+// Conversation - состояние диалога создания события одного пользователя в чате.
+// Хранится в storage, поэтому диалог переживает перезапуск бота.
+type Conversation struct {
+	ChatID int64            `json:"chat_id"`
+	UserID int64            `json:"user_id"`
+	Step   ConversationStep `json:"step"`
+	// Черновик события: дата в формате YYYY-MM-DD, время - HH:MM
+	Name        string `json:"name"`
+	Date        string `json:"date"`
+	Time        string `json:"time"`
+	Description string `json:"description"`
+	// MessageID - сообщение бота с кнопками текущего шага
+	MessageID int       `json:"message_id"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Expired сообщает, что диалог не продолжался дольше timeout
+func (c Conversation) Expired(now time.Time, timeout time.Duration) bool {
+	return now.Sub(c.UpdatedAt) > timeout
+}
+
+// EventDate возвращает дату черновика в формате FormatEventDate
+func (c Conversation) EventDate() string {
+	eventTime := c.Time
+	if eventTime == "" {
+		eventTime = "00:00"
+	}
+	return c.Date + " " + eventTime
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+// DefaultCountdownSize - сколько ближайших событий показывает закреплённый отсчёт по умолчанию
+const DefaultCountdownSize = 5
+
+// MaxCountdownSize ограничивает длину закреплённого сообщения
+const MaxCountdownSize = 20
+
+// PinnedCountdown - закреплённое сообщение чата с обратным отсчётом до ближайших событий.
+// Бот периодически редактирует его; Text - последний отправленный текст, чтобы не
+// редактировать сообщение без изменений.
+type PinnedCountdown struct {
+	ChatID    int64     `json:"chat_id"`
+	MessageID int       `json:"message_id"`
+	Size      int       `json:"size"`
+	Text      string    `json:"text"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
@@ -0,0 +1,190 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// EventMode определяет, как бот показывает время события; пустое значение - обратный отсчёт
+type EventMode string
+
+// ModeCountUp - после наступления событие показывает, сколько времени прошло с его даты:
+// "дней с переезда в Мурманск", "дней без сигарет"
+const ModeCountUp EventMode = "countup"
+
+// IsCountUp сообщает, что для события ведётся счёт прошедшего времени
+func (e Event) IsCountUp() bool {
+	return e.Mode == ModeCountUp
+}
+
+// Elapsed - время, прошедшее с даты события, в календарных единицах
+type Elapsed struct {
+	Years  int
+	Months int
+	Days   int
+	Hours  int
+	// TotalDays - число полных суток с даты события
+	TotalDays int
+}
+
+// ElapsedSince возвращает время от start до now в часовом поясе start. Месяцы считаются
+// по календарю: с 31 января до 28 февраля прошёл месяц. Если now раньше start, результат нулевой.
+func ElapsedSince(start, now time.Time) Elapsed {
+	now = now.In(start.Location())
+	if !now.After(start) {
+		return Elapsed{}
+	}
+
+	months := (now.Year()-start.Year())*12 + int(now.Month()) - int(start.Month())
+	for months > 0 && addMonths(start, months).After(now) {
+		months--
+	}
+	anchor := addMonths(start, months)
+	days := 0
+	for !anchor.AddDate(0, 0, days+1).After(now) {
+		days++
+	}
+	hours := int(now.Sub(anchor.AddDate(0, 0, days)) / time.Hour)
+
+	return Elapsed{
+		Years:     months / 12,
+		Months:    months % 12,
+		Days:      days,
+		Hours:     hours,
+		TotalDays: elapsedDays(start, now),
+	}
+}
+
+// elapsedDays считает полные календарные сутки от start до now
+func elapsedDays(start, now time.Time) int {
+	days := int(now.Sub(start) / (24 * time.Hour))
+	// Переход на летнее время сдвигает сутки на час в ту или другую сторону
+	for days > 0 && start.AddDate(0, 0, days).After(now) {
+		days--
+	}
+	for !start.AddDate(0, 0, days+1).After(now) {
+		days++
+	}
+	return days
+}
+
+// String возвращает прошедшее время по-русски: "1 год 2 месяца 3 дня 4 часа"
+func (e Elapsed) String() string {
+	var parts []string
+	for _, part := range []struct {
+		n              int
+		one, few, many string
+	}{
+		{e.Years, "год", "года", "лет"},
+		{e.Months, "месяц", "месяца", "месяцев"},
+		{e.Days, "день", "дня", "дней"},
+		{e.Hours, "час", "часа", "часов"},
+	} {
+		if part.n > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", part.n, pluralRu(part.n, part.one, part.few, part.many)))
+		}
+	}
+	if len(parts) == 0 {
+		return "меньше часа"
+	}
+	return strings.Join(parts, " ")
+}
+
+// DescribeTotalDays возвращает число полных суток по-русски: "428 дней"
+func (e Elapsed) DescribeTotalDays() string {
+	return fmt.Sprintf("%d %s", e.TotalDays, pluralRu(e.TotalDays, "день", "дня", "дней"))
+}
+
+// Milestone - круглая дата с события: 100, 500, 1000 дней и каждые следующие 1000 дней,
+// первая годовщина и каждые 5 лет
+type Milestone struct {
+	// Days или Years - размер вехи; второе поле нулевое
+	Days  int
+	Years int
+	// At - момент наступления вехи
+	At time.Time
+}
+
+// Key - постоянный ключ вехи для журнала объявлений: "1000d", "5y"
+func (m Milestone) Key() string {
+	if m.Years > 0 {
+		return fmt.Sprintf("%dy", m.Years)
+	}
+	return fmt.Sprintf("%dd", m.Days)
+}
+
+// String возвращает веху по-русски: "1000 дней", "5 лет"
+func (m Milestone) String() string {
+	if m.Years > 0 {
+		return fmt.Sprintf("%d %s", m.Years, pluralRu(m.Years, "год", "года", "лет"))
+	}
+	return fmt.Sprintf("%d %s", m.Days, pluralRu(m.Days, "день", "дня", "дней"))
+}
+
+// dayMilestone и yearMilestone возвращают i-ю веху в днях и в годах
+func dayMilestone(i int) int {
+	switch i {
+	case 0:
+		return 100
+	case 1:
+		return 500
+	default:
+		return (i - 1) * 1000
+	}
+}
+
+func yearMilestone(i int) int {
+	if i == 0 {
+		return 1
+	}
+	return 5 * i
+}
+
+// forEachMilestone перебирает вехи события с датой start в порядке наступления, пока visit
+// возвращает true. Годовщины отсчитываются по календарю: с 29 февраля - 28 февраля.
+func forEachMilestone(start time.Time, visit func(milestone Milestone) bool) {
+	dayIndex, yearIndex := 0, 0
+	for {
+		days := Milestone{Days: dayMilestone(dayIndex)}
+		days.At = start.AddDate(0, 0, days.Days)
+		years := Milestone{Years: yearMilestone(yearIndex)}
+		years.At = addMonths(start, 12*years.Years)
+
+		next := days
+		if years.At.Before(days.At) {
+			next = years
+			yearIndex++
+		} else {
+			dayIndex++
+		}
+		if !visit(next) {
+			return
+		}
+	}
+}
+
+// MilestonesBetween возвращает вехи события с датой start, наступившие после from и не позже to
+func MilestonesBetween(start, from, to time.Time) []Milestone {
+	var milestones []Milestone
+	forEachMilestone(start, func(milestone Milestone) bool {
+		if milestone.At.After(to) {
+			return false
+		}
+		if milestone.At.After(from) {
+			milestones = append(milestones, milestone)
+		}
+		return true
+	})
+	return milestones
+}
+
+// NextMilestone возвращает первую веху события с датой start, наступающую после now
+func NextMilestone(start, now time.Time) Milestone {
+	var next Milestone
+	forEachMilestone(start, func(milestone Milestone) bool {
+		next = milestone
+		return !milestone.At.After(now)
+	})
+	return next
+}
@@ -0,0 +1,417 @@
+package models
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ErrDateNotRecognized означает, что парсер не узнал дату в начале текста
+// и её стоит попробовать разобрать следующим парсером цепочки
+var ErrDateNotRecognized = errors.New("date not recognized")
+
+// AmbiguousDateError - дата узнана, но допускает несколько толкований
+type AmbiguousDateError struct {
+	Input string
+	// Suggestion - подсказка пользователю, как записать дату однозначно
+	Suggestion string
+}
+
+func (e *AmbiguousDateError) Error() string {
+	return fmt.Sprintf("ambiguous date: %s", e.Input)
+}
+
+// ParsedDate - результат разбора даты
+type ParsedDate struct {
+	Time time.Time
+	// Consumed - сколько слов в начале текста занимает дата
+	Consumed int
+	// Echo - дата словами, чтобы пользователь мог проверить, как его поняли
+	Echo string
+}
+
+// DateParser разбирает дату в начале списка слов. now и location задают
+// момент и часовой пояс, относительно которых понимаются "завтра" и "в 18:00".
+type DateParser interface {
+	ParseDate(words []string, now time.Time, location *time.Location) (ParsedDate, error)
+}
+
+// DateParsers - цепочка парсеров: используется первый, узнавший дату
+type DateParsers []DateParser
+
+// DefaultDateParser понимает числовые форматы дат и фразы на русском языке
+var DefaultDateParser = DateParsers{NumericDateParser{}, RussianDateParser{}}
+
+func (parsers DateParsers) ParseDate(words []string, now time.Time, location *time.Location) (ParsedDate, error) {
+	for _, parser := range parsers {
+		parsed, err := parser.ParseDate(words, now, location)
+		if errors.Is(err, ErrDateNotRecognized) {
+			continue
+		}
+		return parsed, err
+	}
+	return ParsedDate{}, ErrDateNotRecognized
+}
+
+var clockPattern = regexp.MustCompile(`^(\d{1,2}):(\d{2})$`)
+
+// hourPattern - час без минут: "в 9 утра", "в 18"
+var hourPattern = regexp.MustCompile(`^\d{1,2}$`)
+
+// numberPattern - число без знака: strconv.Atoi принимает и "+5", и "-5"
+var numberPattern = regexp.MustCompile(`^\d+$`)
+
+// NumericDateParser понимает форматы ParseEventDate с необязательным временем HH:MM следующим словом
+type NumericDateParser struct{}
+
+func (NumericDateParser) ParseDate(words []string, now time.Time, location *time.Location) (ParsedDate, error) {
+	if len(words) == 0 || !IsValidDate(words[0]) {
+		return ParsedDate{}, ErrDateNotRecognized
+	}
+	parsed, err := ParseEventDateIn(words[0], location)
+	if err != nil {
+		return ParsedDate{}, err
+	}
+	consumed := 1
+	if len(words) > 1 && clockPattern.MatchString(words[1]) {
+		hour, minute, err := parseClock(words[1])
+		if err != nil {
+			return ParsedDate{}, err
+		}
+		parsed = time.Date(parsed.Year(), parsed.Month(), parsed.Day(), hour, minute, 0, 0, location)
+		consumed = 2
+	}
+	return ParsedDate{Time: parsed, Consumed: consumed, Echo: DescribeDate(parsed)}, nil
+}
+
+// RussianDateParser понимает фразы вида "завтра в 18:00", "через 3 недели", "31 декабря",
+// "в следующую пятницу", "12 марта 2026 в 9 утра". Дата без времени означает 00:00.
+type RussianDateParser struct{}
+
+var monthsGenitive = map[string]time.Month{
+	"января": time.January, "февраля": time.February, "марта": time.March,
+	"апреля": time.April, "мая": time.May, "июня": time.June,
+	"июля": time.July, "августа": time.August, "сентября": time.September,
+	"октября": time.October, "ноября": time.November, "декабря": time.December,
+}
+
+var weekdayWords = map[string]time.Weekday{
+	"понедельник": time.Monday,
+	"вторник":     time.Tuesday,
+	"среду":       time.Wednesday, "среда": time.Wednesday,
+	"четверг": time.Thursday,
+	"пятницу": time.Friday, "пятница": time.Friday,
+	"субботу": time.Saturday, "суббота": time.Saturday,
+	"воскресенье": time.Sunday,
+}
+
+var weekdayModifiers = map[string]bool{
+	"следующий": true, "следующую": true, "следующее": true,
+	"ближайший": true, "ближайшую": true, "ближайшее": true,
+	"этот": true, "эту": true, "это": true,
+}
+
+var dayWords = map[string]int{"сегодня": 0, "завтра": 1, "послезавтра": 2}
+
+type relativeUnit struct {
+	days     int
+	months   int
+	duration time.Duration
+}
+
+var relativeUnits = map[string]relativeUnit{
+	"минуту": {duration: time.Minute}, "минуты": {duration: time.Minute}, "минут": {duration: time.Minute},
+	"час": {duration: time.Hour}, "часа": {duration: time.Hour}, "часов": {duration: time.Hour},
+	"день": {days: 1}, "дня": {days: 1}, "дней": {days: 1},
+	"неделю": {days: 7}, "недели": {days: 7}, "недель": {days: 7},
+	"месяц": {months: 1}, "месяца": {months: 1}, "месяцев": {months: 1},
+	"год": {months: 12}, "года": {months: 12}, "лет": {months: 12},
+}
+
+// dayPeriods переводит час с уточнением ("9 вечера") в 24-часовой формат.
+// "12 ночи" - полночь в конце дня: час 24, который time.Date переносит на 00:00 следующего дня.
+var dayPeriods = map[string]func(hour int) (int, bool){
+	"утра": func(hour int) (int, bool) { return hour, hour >= 1 && hour <= 11 },
+	"дня": func(hour int) (int, bool) {
+		if hour == 12 {
+			return 12, true
+		}
+		return hour + 12, hour >= 1 && hour <= 5
+	},
+	"вечера": func(hour int) (int, bool) { return hour + 12, hour >= 5 && hour <= 11 },
+	"ночи": func(hour int) (int, bool) {
+		if hour == 12 {
+			return 24, true
+		}
+		return hour, hour >= 1 && hour <= 4
+	},
+}
+
+// russianPhrase - разбираемые слова и позиция текущего слова
+type russianPhrase struct {
+	words []string
+	pos   int
+}
+
+func (p *russianPhrase) peek(offset int) string {
+	if p.pos+offset >= len(p.words) {
+		return ""
+	}
+	return p.words[p.pos+offset]
+}
+
+func (RussianDateParser) ParseDate(words []string, now time.Time, location *time.Location) (ParsedDate, error) {
+	now = now.In(location)
+	lowered := make([]string, len(words))
+	for i, word := range words {
+		lowered[i] = strings.TrimRight(strings.ToLower(word), ",.")
+	}
+	p := &russianPhrase{words: lowered}
+
+	day, exact, found, err := p.parseDay(now, location)
+	if err != nil {
+		return ParsedDate{}, err
+	}
+
+	result := day
+	if !exact {
+		hour, minute, hasTime, err := p.parseTime(found)
+		if err != nil {
+			return ParsedDate{}, err
+		}
+		if !found && !hasTime {
+			return ParsedDate{}, ErrDateNotRecognized
+		}
+		if !found {
+			// Только время: сегодня, если оно ещё не прошло
+			day = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+			result = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, location)
+			if result.Before(now) {
+				phrase := strings.Join(words[:p.pos], " ")
+				return ParsedDate{}, &AmbiguousDateError{
+					Input:      phrase,
+					Suggestion: fmt.Sprintf("Это время сегодня уже прошло. Уточните день: «завтра %s» или «сегодня %s».", phrase, phrase),
+				}
+			}
+		} else if hasTime {
+			result = time.Date(day.Year(), day.Month(), day.Day(), hour, minute, 0, 0, location)
+		}
+	}
+
+	return ParsedDate{Time: result, Consumed: p.pos, Echo: DescribeDate(result)}, nil
+}
+
+// parseDay разбирает день: "завтра", "через 3 недели", "31 декабря [2026]", "в следующую пятницу".
+// exact означает, что время уже определено (например, "через 2 часа").
+func (p *russianPhrase) parseDay(now time.Time, location *time.Location) (day time.Time, exact, found bool, err error) {
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, location)
+	word := p.peek(0)
+
+	if offset, ok := dayWords[word]; ok {
+		p.pos++
+		return today.AddDate(0, 0, offset), false, true, nil
+	}
+
+	if word == "через" {
+		return p.parseRelative(now, today)
+	}
+
+	if dayNumber, err := strconv.Atoi(word); err == nil {
+		month, ok := monthsGenitive[p.peek(1)]
+		if !ok {
+			return time.Time{}, false, false, nil
+		}
+		if !numberPattern.MatchString(word) {
+			return time.Time{}, false, false, fmt.Errorf("invalid date: %s %s", word, p.peek(1))
+		}
+		return p.parseDayOfMonth(dayNumber, month, today, location)
+	}
+
+	// "в пятницу", "во вторник", "в следующую пятницу", "пятница"
+	offset := 0
+	if word == "в" || word == "во" {
+		offset = 1
+	}
+	next := false
+	if weekdayModifiers[p.peek(offset)] {
+		next = strings.HasPrefix(p.peek(offset), "следующ")
+		offset++
+	}
+	weekday, ok := weekdayWords[p.peek(offset)]
+	if !ok {
+		return time.Time{}, false, false, nil
+	}
+	phrase := strings.Join(p.words[p.pos:p.pos+offset+1], " ")
+	p.pos += offset + 1
+
+	if next {
+		// "в следующую пятницу" - пятница следующей недели, даже если до ближайшей пятницы один день
+		return today.AddDate(0, 0, 7-mondayIndex(today.Weekday())+mondayIndex(weekday)), false, true, nil
+	}
+	days := (int(weekday) - int(today.Weekday()) + 7) % 7
+	if days == 0 {
+		return time.Time{}, false, false, &AmbiguousDateError{
+			Input:      phrase,
+			Suggestion: "Этот день недели - сегодня. Напишите «сегодня» или «в следующую " + weekdayAccusative[weekday] + "».",
+		}
+	}
+	return today.AddDate(0, 0, days), false, true, nil
+}
+
+// mondayIndex - номер дня в неделе, начинающейся с понедельника (понедельник - 0)
+func mondayIndex(weekday time.Weekday) int {
+	return (int(weekday) + 6) % 7
+}
+
+var weekdayAccusative = map[time.Weekday]string{
+	time.Monday: "понедельник", time.Tuesday: "вторник", time.Wednesday: "среду",
+	time.Thursday: "четверг", time.Friday: "пятницу", time.Saturday: "субботу", time.Sunday: "воскресенье",
+}
+
+// parseRelative разбирает "через [N] <единица>"
+func (p *russianPhrase) parseRelative(now, today time.Time) (time.Time, bool, bool, error) {
+	n := 1
+	offset := 1
+	if value, err := strconv.Atoi(p.peek(1)); err == nil {
+		if value < 1 || !numberPattern.MatchString(p.peek(1)) {
+			return time.Time{}, false, false, fmt.Errorf("invalid relative date: через %s", p.peek(1))
+		}
+		n = value
+		offset = 2
+	}
+	unit, ok := relativeUnits[p.peek(offset)]
+	if !ok {
+		return time.Time{}, false, false, fmt.Errorf("unknown relative date unit: %s", p.peek(offset))
+	}
+	p.pos += offset + 1
+
+	if unit.duration > 0 {
+		return now.Add(time.Duration(n) * unit.duration).Truncate(time.Minute), true, true, nil
+	}
+	return today.AddDate(0, n*unit.months, n*unit.days), false, true, nil
+}
+
+// parseDayOfMonth разбирает "31 декабря [2026 [года]]". Без года выбирается ближайшая такая дата.
+func (p *russianPhrase) parseDayOfMonth(dayNumber int, month time.Month, today time.Time, location *time.Location) (time.Time, bool, bool, error) {
+	p.pos += 2
+	year := today.Year()
+	explicitYear := false
+	if value, err := strconv.Atoi(p.peek(0)); err == nil && len(p.peek(0)) == 4 {
+		year = value
+		explicitYear = true
+		p.pos++
+		if word := p.peek(0); word == "года" || word == "г" {
+			p.pos++
+		}
+	}
+
+	if dayNumber < 1 || dayNumber > daysIn(year, month, location) {
+		// 29 февраля без года - ближайший високосный год
+		if !explicitYear && month == time.February && dayNumber == 29 {
+			for !isLeap(year) {
+				year++
+			}
+			return time.Date(year, month, dayNumber, 0, 0, 0, 0, location), false, true, nil
+		}
+		return time.Time{}, false, false, fmt.Errorf("invalid date: %d %s %d", dayNumber, month, year)
+	}
+	date := time.Date(year, month, dayNumber, 0, 0, 0, 0, location)
+	if !explicitYear && date.Before(today) {
+		date = date.AddDate(1, 0, 0)
+	}
+	return date, false, true, nil
+}
+
+func isLeap(year int) bool {
+	return year%4 == 0 && (year%100 != 0 || year%400 == 0)
+}
+
+// parseTime разбирает время: "в 18:00", "в 9 утра", "в полдень"; после дня предлог "в" можно опустить.
+// Час без уточнения от 1 до 11 ("в 9") неоднозначен.
+func (p *russianPhrase) parseTime(afterDay bool) (hour, minute int, found bool, err error) {
+	offset := 0
+	if word := p.peek(0); word == "в" || word == "во" {
+		offset = 1
+	}
+	word := p.peek(offset)
+
+	switch word {
+	case "полдень":
+		p.pos += offset + 1
+		return 12, 0, true, nil
+	case "полночь":
+		p.pos += offset + 1
+		return 0, 0, true, nil
+	}
+
+	explicitClock := clockPattern.MatchString(word)
+	if explicitClock {
+		if offset == 0 && !afterDay {
+			return 0, 0, false, nil
+		}
+		hour, minute, err = parseClock(word)
+		if err != nil {
+			return 0, 0, false, err
+		}
+	} else {
+		value, convErr := strconv.Atoi(word)
+		if convErr != nil || offset == 0 {
+			return 0, 0, false, nil
+		}
+		// Atoi принимает и знак: "в -5" не время, а опечатка
+		if !hourPattern.MatchString(word) {
+			return 0, 0, false, fmt.Errorf("invalid time: %s", strings.Join(p.words[p.pos:p.pos+offset+1], " "))
+		}
+		hour = value
+	}
+
+	phrase := strings.Join(p.words[p.pos:p.pos+offset+1], " ")
+	p.pos += offset + 1
+
+	if period, ok := dayPeriods[p.peek(0)]; ok {
+		converted, valid := period(hour)
+		if !valid {
+			return 0, 0, false, fmt.Errorf("invalid time: %s %s", phrase, p.peek(0))
+		}
+		p.pos++
+		return converted, minute, true, nil
+	}
+
+	if !explicitClock && hour >= 1 && hour <= 11 {
+		return 0, 0, false, &AmbiguousDateError{
+			Input: phrase,
+			Suggestion: fmt.Sprintf("Уточните время: «в %d утра», «в %d вечера» или «в %02d:00».",
+				hour, hour, hour+12),
+		}
+	}
+	if hour > 23 {
+		return 0, 0, false, fmt.Errorf("invalid time: %s", phrase)
+	}
+	return hour, minute, true, nil
+}
+
+func parseClock(value string) (int, int, error) {
+	match := clockPattern.FindStringSubmatch(value)
+	if match == nil {
+		return 0, 0, fmt.Errorf("invalid time: %s", value)
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	if hour > 23 || minute > 59 {
+		return 0, 0, fmt.Errorf("invalid time: %s", value)
+	}
+	return hour, minute, nil
+}
+
+var weekdayNames = [...]string{"воскресенье", "понедельник", "вторник", "среда", "четверг", "пятница", "суббота"}
+
+var monthNamesGenitive = [...]string{"", "января", "февраля", "марта", "апреля", "мая", "июня",
+	"июля", "августа", "сентября", "октября", "ноября", "декабря"}
+
+// DescribeDate возвращает дату словами: "пятница, 31 декабря 2026, 18:00"
+func DescribeDate(t time.Time) string {
+	return fmt.Sprintf("%s, %d %s %d, %s", weekdayNames[t.Weekday()], t.Day(), monthNamesGenitive[t.Month()], t.Year(), t.Format("15:04"))
+}
@@ -17,16 +17,83 @@ const (
 )
 
 type Event struct {
-	EventID     string      `json:"event_id"`
-	Name        string      `json:"name"`
+	EventID string `json:"event_id"`
+	// Name - команда события (/name): латиница, цифры и "_"; по ней событие ищется в чате
+	Name string `json:"name"`
+	// DisplayName - имя события для показа на любом языке; пустая строка - показывается Name
+	DisplayName string      `json:"display_name,omitempty"`
 	Date        string      `json:"date"`
 	Description string      `json:"description"`
 	Status      EventStatus `json:"status"`
 	ChatID      int64       `json:"chat_id"`
+	Recurrence  *Recurrence `json:"recurrence,omitempty"`
+	// Reminders - смещения напоминаний (30d, 1h, 0); nil - настройки по умолчанию, пустой список - без напоминаний
+	Reminders []string `json:"reminders"`
+	// TimeZone - часовой пояс, в котором записана Date; пустая строка - часовой пояс по умолчанию
+	TimeZone string `json:"time_zone,omitempty"`
+	// Visibility - кому событие видно в других чатах; пустая строка - только своему чату
+	Visibility EventVisibility `json:"visibility,omitempty"`
+	// SharedWith - чаты, которым видно событие с видимостью VisibilityShared
+	SharedWith []int64 `json:"shared_with,omitempty"`
+	// Aliases - дополнительные команды события; как и Name, они уникальны в пределах чата
+	Aliases []string `json:"aliases,omitempty"`
+	// Mode - как показывается время события; пустая строка - обратный отсчёт
+	Mode EventMode `json:"mode,omitempty"`
+	// AnnounceMilestones - объявлять в чате о круглых датах с события в режиме ModeCountUp
+	AnnounceMilestones bool `json:"announce_milestones,omitempty"`
 }
 
+// Location возвращает часовой пояс, в котором записана дата события
+func (e Event) Location() (*time.Location, error) {
+	return LoadTimeZone(e.TimeZone)
+}
+
+// Time возвращает дату события (для повторяющегося - первого повторения) в его часовом поясе
+func (e Event) Time() (time.Time, error) {
+	location, err := e.Location()
+	if err != nil {
+		return time.Time{}, err
+	}
+	return ParseEventDateIn(e.Date, location)
+}
+
+// IsRecurring сообщает, повторяется ли событие
+func (e Event) IsRecurring() bool {
+	return e.Recurrence != nil
+}
+
+// NextOccurrence возвращает ближайшую дату события не раньше now. Для разового события
+// это его дата; второй результат равен false, если событие (или серия повторений) уже прошло.
+func (e Event) NextOccurrence(now time.Time) (time.Time, bool, error) {
+	date, err := e.Time()
+	if err != nil {
+		return time.Time{}, false, err
+	}
+	if e.Recurrence == nil {
+		return date, !date.Before(now), nil
+	}
+	next, ok := e.Recurrence.NextOccurrence(date, now)
+	return next, ok, nil
+}
+
+// StatusAt возвращает статус события на момент now. Повторяющееся событие
+// остаётся активным, пока у него есть будущие повторения.
+func (e Event) StatusAt(now time.Time) (EventStatus, error) {
+	_, upcoming, err := e.NextOccurrence(now)
+	if err != nil {
+		return "", err
+	}
+	if upcoming {
+		return StatusActive, nil
+	}
+	return StatusOutdated, nil
+}
+
+// IsValidEventName проверяет, что имя можно использовать как команду события как есть:
+// латиница, цифры и "_", не длиннее MaxCommandLength. Более длинное имя становится
+// отображаемым, а команда получается из него обрезкой (EventSlug).
 func IsValidEventName(name string) bool {
-	if len(name) == 0 {
+	if len(name) == 0 || len(name) > MaxCommandLength {
 		return false
 	}
 	matched, _ := regexp.MatchString(`^[a-zA-Z0-9_]+$`, name)
@@ -65,12 +132,17 @@ func IsValidDate(date string) bool {
 	return err == nil
 }
 
+// ParseEventDate разбирает дату в часовом поясе по умолчанию
 func ParseEventDate(dateStr string) (time.Time, error) {
-	location, err := time.LoadLocation("Europe/Moscow")
+	location, err := LoadTimeZone("")
 	if err != nil {
 		return time.Time{}, err
 	}
+	return ParseEventDateIn(dateStr, location)
+}
 
+// ParseEventDateIn разбирает дату как местное время в часовом поясе location
+func ParseEventDateIn(dateStr string, location *time.Location) (time.Time, error) {
 	// Если формат YYYY-MM-DD HH:MM
 	if matched, _ := regexp.MatchString(`^\d{4}-\d{1,2}-\d{1,2} \d{1,2}:\d{2}$`, dateStr); matched {
 		parsed, err := time.ParseInLocation("2006-01-02 15:04", dateStr, location)
@@ -0,0 +1,139 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+)
+
+// MaxCommandLength - ограничение Telegram на длину команды
+const MaxCommandLength = 32
+
+// MaxDisplayNameLength - максимальная длина отображаемого имени события в символах
+const MaxDisplayNameLength = 64
+
+// MaxAliases - максимальное число псевдонимов одного события
+const MaxAliases = 10
+
+// defaultSlug - команда события, имя которого не удалось транслитерировать
+const defaultSlug = "event"
+
+// systemCommands - команды бота; они не ищутся как события, поэтому событие с такой
+// командой нельзя было бы открыть
+var systemCommands = []string{"new", "cancel", "set_date", "edit_date", "edit_description", "rename", "delete", "alias", "unalias", "countup", "remind", "timezone", "event_timezone", "publish", "unpublish", "subscribe", "unsubscribe", "visibility", "policy", "pin_countdown", "list", "all", "active", "outdated", "help", "start"}
+
+// IsSystemCommand сообщает, что command (без "/") - команда бота
+func IsSystemCommand(command string) bool {
+	for _, sysCmd := range systemCommands {
+		if command == sysCmd {
+			return true
+		}
+	}
+	return false
+}
+
+// translit - транслитерация русских букв в командах событий
+var translit = map[rune]string{
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "e", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya",
+}
+
+// Title возвращает имя события для показа пользователям
+func (e Event) Title() string {
+	if e.DisplayName != "" {
+		return e.DisplayName
+	}
+	return e.Name
+}
+
+// HasCommand сообщает, вызывается ли событие командой name: его именем или псевдонимом
+func (e Event) HasCommand(name string) bool {
+	return e.Name == name || e.HasAlias(name)
+}
+
+// HasCommandFold - HasCommand без учёта регистра: меню "/" публикует команды строчными,
+// и /newyear открывает событие NewYear
+func (e Event) HasCommandFold(name string) bool {
+	if strings.EqualFold(e.Name, name) {
+		return true
+	}
+	for _, alias := range e.Aliases {
+		if strings.EqualFold(alias, name) {
+			return true
+		}
+	}
+	return false
+}
+
+// HasAlias сообщает, есть ли у события псевдоним name
+func (e Event) HasAlias(name string) bool {
+	for _, alias := range e.Aliases {
+		if alias == name {
+			return true
+		}
+	}
+	return false
+}
+
+// IsValidDisplayName проверяет отображаемое имя события: непустое, не длиннее
+// MaxDisplayNameLength символов и без переводов строк и управляющих символов
+func IsValidDisplayName(name string) bool {
+	name = strings.TrimSpace(name)
+	if name == "" || !utf8.ValidString(name) || utf8.RuneCountInString(name) > MaxDisplayNameLength {
+		return false
+	}
+	return strings.IndexFunc(name, unicode.IsControl) < 0
+}
+
+// EventSlug получает из имени события команду Telegram: русские буквы транслитерируются,
+// латиница приводится к нижнему регистру, остальные символы становятся "_", повторяющиеся
+// "_" схлопываются, а результат обрезается до MaxCommandLength символов.
+// "День рождения мамы" превращается в "den_rozhdeniya_mamy".
+func EventSlug(name string) string {
+	var slug strings.Builder
+	separator := false
+	for _, r := range strings.ToLower(name) {
+		var part string
+		switch {
+		case r >= 'a' && r <= 'z', r >= '0' && r <= '9':
+			part = string(r)
+		default:
+			if latin, ok := translit[r]; ok {
+				part = latin
+			} else {
+				separator = true
+			}
+		}
+		if part == "" {
+			continue
+		}
+		if separator && slug.Len() > 0 {
+			slug.WriteByte('_')
+		}
+		separator = false
+		slug.WriteString(part)
+	}
+	return truncateSlug(slug.String(), MaxCommandLength)
+}
+
+// NumberedSlug возвращает вариант команды slug с номером n для разрешения совпадений
+// в чате: "party_2", "party_3". Результат не длиннее MaxCommandLength символов.
+func NumberedSlug(slug string, n int) string {
+	suffix := fmt.Sprintf("_%d", n)
+	return truncateSlug(slug, MaxCommandLength-len(suffix)) + suffix
+}
+
+func truncateSlug(slug string, limit int) string {
+	if len(slug) > limit {
+		slug = slug[:limit]
+	}
+	slug = strings.Trim(slug, "_")
+	if slug == "" {
+		return defaultSlug
+	}
+	return slug
+}
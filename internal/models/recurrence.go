@@ -0,0 +1,234 @@
+package models
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type RecurrenceFrequency string
+
+const (
+	FrequencyDaily   RecurrenceFrequency = "daily"
+	FrequencyWeekly  RecurrenceFrequency = "weekly"
+	FrequencyMonthly RecurrenceFrequency = "monthly"
+	FrequencyYearly  RecurrenceFrequency = "yearly"
+)
+
+// Recurrence описывает правило повторения события: каждые Interval единиц Frequency,
+// начиная с даты события и не позже Until (если задано)
+type Recurrence struct {
+	Frequency RecurrenceFrequency `json:"frequency"`
+	Interval  int                 `json:"interval,omitempty"`
+	Until     string              `json:"until,omitempty"`
+}
+
+// Ключевые слова правила повторения в /set_date: every 2 weeks until 2030-01-01.
+// Правило начинается только с явного every: "Каждый год собираемся у бабушки" - это описание.
+const recurrenceKeyword = "every"
+
+var (
+	recurrenceUntilKeywords = map[string]bool{"until": true, "до": true}
+	recurrenceUnits         = map[string]RecurrenceFrequency{
+		"day": FrequencyDaily, "days": FrequencyDaily, "день": FrequencyDaily, "дня": FrequencyDaily, "дней": FrequencyDaily,
+		"week": FrequencyWeekly, "weeks": FrequencyWeekly, "неделю": FrequencyWeekly, "недели": FrequencyWeekly, "недель": FrequencyWeekly,
+		"month": FrequencyMonthly, "months": FrequencyMonthly, "месяц": FrequencyMonthly, "месяца": FrequencyMonthly, "месяцев": FrequencyMonthly,
+		"year": FrequencyYearly, "years": FrequencyYearly, "год": FrequencyYearly, "года": FrequencyYearly, "лет": FrequencyYearly,
+	}
+)
+
+// ParseRecurrence разбирает правило повторения в начале args и возвращает число
+// использованных слов. Если args не начинается с корректного правила every ..., возвращает
+// nil и 0: слова остаются описанием события. Окончание серии until/до учитывается, только
+// если за ним следует дата.
+func ParseRecurrence(args []string) (*Recurrence, int) {
+	if len(args) == 0 || strings.ToLower(args[0]) != recurrenceKeyword {
+		return nil, 0
+	}
+
+	consumed := 1
+	interval := 1
+	if consumed < len(args) {
+		if n, err := strconv.Atoi(args[consumed]); err == nil {
+			if n < 1 {
+				return nil, 0
+			}
+			interval = n
+			consumed++
+		}
+	}
+	if consumed >= len(args) {
+		return nil, 0
+	}
+	frequency, ok := recurrenceUnits[strings.ToLower(args[consumed])]
+	if !ok {
+		return nil, 0
+	}
+	consumed++
+
+	rule := &Recurrence{Frequency: frequency, Interval: interval}
+	if consumed+1 < len(args) && recurrenceUntilKeywords[strings.ToLower(args[consumed])] {
+		if until, err := ParseEventDate(args[consumed+1]); err == nil {
+			rule.Until = FormatEventDate(until)
+			consumed += 2
+		}
+	}
+	return rule, consumed
+}
+
+// Validate проверяет правило относительно даты начала события
+func (r *Recurrence) Validate(start time.Time) error {
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly, FrequencyMonthly, FrequencyYearly:
+	default:
+		return fmt.Errorf("unknown recurrence frequency: %s", r.Frequency)
+	}
+	if r.Interval < 0 {
+		return fmt.Errorf("invalid recurrence interval: %d", r.Interval)
+	}
+	if r.Until != "" {
+		until, err := ParseEventDateIn(r.Until, start.Location())
+		if err != nil {
+			return err
+		}
+		if until.Before(start) {
+			return fmt.Errorf("recurrence end date is before event date")
+		}
+	}
+	return nil
+}
+
+func (r *Recurrence) interval() int {
+	if r.Interval < 1 {
+		return 1
+	}
+	return r.Interval
+}
+
+// occurrence возвращает k-е повторение после start. Для месяцев и лет день
+// ограничивается длиной месяца: 31 января -> 28/29 февраля, 29 февраля -> 28 февраля.
+func (r *Recurrence) occurrence(start time.Time, k int) time.Time {
+	step := k * r.interval()
+	switch r.Frequency {
+	case FrequencyDaily:
+		return start.AddDate(0, 0, step)
+	case FrequencyWeekly:
+		return start.AddDate(0, 0, 7*step)
+	}
+
+	months := step
+	if r.Frequency == FrequencyYearly {
+		months = 12 * step
+	}
+	return addMonths(start, months)
+}
+
+// addMonths сдвигает дату на months месяцев вперёд, ограничивая день длиной месяца
+func addMonths(start time.Time, months int) time.Time {
+	total := int(start.Month()) - 1 + months
+	year := start.Year() + total/12
+	month := time.Month(total%12 + 1)
+	day := start.Day()
+	if last := daysIn(year, month, start.Location()); day > last {
+		day = last
+	}
+	return time.Date(year, month, day, start.Hour(), start.Minute(), start.Second(), start.Nanosecond(), start.Location())
+}
+
+// NextOccurrence возвращает первое повторение не раньше now. Второй результат
+// равен false, если серия закончилась (следующее повторение позже Until).
+func (r *Recurrence) NextOccurrence(start, now time.Time) (time.Time, bool) {
+	if !start.Before(now) {
+		return start, true
+	}
+
+	// Оцениваем номер повторения, чтобы не перебирать всю историю события
+	k := 0
+	switch r.Frequency {
+	case FrequencyDaily, FrequencyWeekly:
+		period := 24 * time.Hour * time.Duration(r.interval())
+		if r.Frequency == FrequencyWeekly {
+			period *= 7
+		}
+		k = int(now.Sub(start)/period) - 1
+	case FrequencyMonthly, FrequencyYearly:
+		months := (now.Year()-start.Year())*12 + int(now.Month()) - int(start.Month())
+		period := r.interval()
+		if r.Frequency == FrequencyYearly {
+			period *= 12
+		}
+		k = months/period - 1
+	}
+	if k < 0 {
+		k = 0
+	}
+
+	next := r.occurrence(start, k)
+	for next.Before(now) {
+		k++
+		next = r.occurrence(start, k)
+	}
+
+	if r.Until != "" {
+		until, err := ParseEventDateIn(r.Until, start.Location())
+		if err == nil && next.After(until) {
+			return time.Time{}, false
+		}
+	}
+	return next, true
+}
+
+// String возвращает описание правила на русском: "каждый год", "каждые 2 недели"
+func (r *Recurrence) String() string {
+	n := r.interval()
+	var unit string
+	switch r.Frequency {
+	case FrequencyDaily:
+		unit = pluralRu(n, "день", "дня", "дней")
+	case FrequencyWeekly:
+		unit = pluralRu(n, "неделю", "недели", "недель")
+	case FrequencyMonthly:
+		unit = pluralRu(n, "месяц", "месяца", "месяцев")
+	case FrequencyYearly:
+		unit = pluralRu(n, "год", "года", "лет")
+	default:
+		return string(r.Frequency)
+	}
+
+	var text string
+	switch {
+	case n == 1 && r.Frequency == FrequencyWeekly:
+		text = "каждую неделю"
+	case n == 1:
+		text = "каждый " + unit
+	case n%10 == 1 && n%100 != 11:
+		text = fmt.Sprintf("каждый %d %s", n, unit)
+	default:
+		text = fmt.Sprintf("каждые %d %s", n, unit)
+	}
+	if r.Until != "" {
+		text += " до " + r.Until
+	}
+	return text
+}
+
+func daysIn(year int, month time.Month, location *time.Location) int {
+	return time.Date(year, month+1, 0, 0, 0, 0, 0, location).Day()
+}
+
+// pluralRu выбирает форму слова для числа n: 1 год, 2 года, 5 лет
+func pluralRu(n int, one, few, many string) string {
+	n %= 100
+	if n >= 11 && n <= 14 {
+		return many
+	}
+	switch n % 10 {
+	case 1:
+		return one
+	case 2, 3, 4:
+		return few
+	default:
+		return many
+	}
+}
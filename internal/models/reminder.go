@@ -0,0 +1,146 @@
+package models
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DefaultReminders - напоминания для событий без собственной настройки
+var DefaultReminders = []string{"30d", "7d", "1d", "1h", "0"}
+
+// ReminderDelivery фиксирует отправленное напоминание: событие, конкретное
+// повторение (дата в формате FormatEventDate) и смещение до него
+type ReminderDelivery struct {
+	EventID    string    `json:"event_id"`
+	Occurrence string    `json:"occurrence"`
+	Offset     string    `json:"offset"`
+	SentAt     time.Time `json:"sent_at"`
+}
+
+var reminderUnits = map[string]time.Duration{
+	"m": time.Minute,
+	"h": time.Hour,
+	"d": 24 * time.Hour,
+	"w": 7 * 24 * time.Hour,
+}
+
+// ParseReminderOffset разбирает смещение напоминания: 30d, 2w, 1h, 15m или 0 (в момент события)
+func ParseReminderOffset(value string) (time.Duration, error) {
+	value = strings.ToLower(strings.TrimSpace(value))
+	if value == "0" {
+		return 0, nil
+	}
+	if len(value) < 2 {
+		return 0, fmt.Errorf("invalid reminder offset: %s", value)
+	}
+	unit, ok := reminderUnits[value[len(value)-1:]]
+	if !ok {
+		return 0, fmt.Errorf("invalid reminder offset: %s", value)
+	}
+	n, err := strconv.Atoi(value[:len(value)-1])
+	if err != nil || n < 0 {
+		return 0, fmt.Errorf("invalid reminder offset: %s", value)
+	}
+	return time.Duration(n) * unit, nil
+}
+
+// FormatReminderOffset возвращает каноническую запись смещения: 7d, 1h, 0
+func FormatReminderOffset(offset time.Duration) string {
+	if offset == 0 {
+		return "0"
+	}
+	// Недели записываются днями: 14d привычнее, чем 2w
+	for _, unit := range []string{"d", "h"} {
+		if size := reminderUnits[unit]; offset%size == 0 {
+			return fmt.Sprintf("%d%s", offset/size, unit)
+		}
+	}
+	return fmt.Sprintf("%dm", offset/time.Minute)
+}
+
+// NormalizeReminders проверяет смещения и возвращает их в канонической записи,
+// без повторов, от большего к меньшему
+func NormalizeReminders(values []string) ([]string, error) {
+	seen := make(map[time.Duration]bool)
+	var offsets []time.Duration
+	for _, value := range values {
+		for _, part := range strings.Split(value, ",") {
+			if strings.TrimSpace(part) == "" {
+				continue
+			}
+			offset, err := ParseReminderOffset(part)
+			if err != nil {
+				return nil, err
+			}
+			if !seen[offset] {
+				seen[offset] = true
+				offsets = append(offsets, offset)
+			}
+		}
+	}
+	sort.Slice(offsets, func(i, j int) bool { return offsets[i] > offsets[j] })
+
+	result := make([]string, len(offsets))
+	for i, offset := range offsets {
+		result[i] = FormatReminderOffset(offset)
+	}
+	return result, nil
+}
+
+// ReminderOffsets возвращает смещения напоминаний события. Если Reminders равен nil,
+// используются defaults; пустой список означает, что напоминания отключены.
+func (e Event) ReminderOffsets(defaults []string) ([]time.Duration, error) {
+	values := e.Reminders
+	if values == nil {
+		values = defaults
+	}
+	offsets := make([]time.Duration, 0, len(values))
+	for _, value := range values {
+		offset, err := ParseReminderOffset(value)
+		if err != nil {
+			return nil, err
+		}
+		offsets = append(offsets, offset)
+	}
+	return offsets, nil
+}
+
+// DescribeReminderOffset возвращает смещение по-русски: "за 7 дней", "в момент события"
+func DescribeReminderOffset(offset time.Duration) string {
+	if offset == 0 {
+		return "в момент события"
+	}
+	// После "за" используется винительный падеж: за 1 минуту
+	return "за " + describeDuration(offset, "минуту")
+}
+
+// DescribeDuration возвращает длительность по-русски с точностью до минут: "7 дней", "1 час 30 минут"
+func DescribeDuration(d time.Duration) string {
+	return describeDuration(d, "минута")
+}
+
+// DescribeTimeUntil возвращает время до события по-русски: "через 3 дня 4 часа", "через 1 минуту"
+func DescribeTimeUntil(d time.Duration) string {
+	return "через " + describeDuration(d, "минуту")
+}
+
+func describeDuration(d time.Duration, minuteOne string) string {
+	days := int(d / (24 * time.Hour))
+	hours := int(d/time.Hour) % 24
+	minutes := int(d/time.Minute) % 60
+
+	var parts []string
+	if days > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", days, pluralRu(days, "день", "дня", "дней")))
+	}
+	if hours > 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", hours, pluralRu(hours, "час", "часа", "часов")))
+	}
+	if minutes > 0 || len(parts) == 0 {
+		parts = append(parts, fmt.Sprintf("%d %s", minutes, pluralRu(minutes, minuteOne, "минуты", "минут")))
+	}
+	return strings.Join(parts, " ")
+}
@@ -0,0 +1,99 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// DefaultTimeZone - встроенный часовой пояс по умолчанию; при запуске его можно
+// заменить через SetDefaultTimeZone
+const DefaultTimeZone = "Europe/Moscow"
+
+var (
+	locationsMu sync.Mutex
+	locations   = make(map[string]*time.Location)
+
+	defaultTimeZone atomic.Value // string
+)
+
+// DefaultTimeZoneName возвращает часовой пояс чатов и событий, для которых он не задан
+func DefaultTimeZoneName() string {
+	if name, ok := defaultTimeZone.Load().(string); ok {
+		return name
+	}
+	return DefaultTimeZone
+}
+
+// SetDefaultTimeZone задаёт часовой пояс по умолчанию для всего приложения.
+// Вызывается один раз при запуске, до обработки событий.
+func SetDefaultTimeZone(name string) error {
+	normalized, err := NormalizeTimeZone(name)
+	if err != nil {
+		return err
+	}
+	defaultTimeZone.Store(normalized)
+	return nil
+}
+
+// LoadTimeZone возвращает часовой пояс по имени из базы IANA (Asia/Yekaterinburg).
+// Пустое имя означает часовой пояс по умолчанию. Загруженные пояса кэшируются.
+func LoadTimeZone(name string) (*time.Location, error) {
+	if name == "" {
+		name = DefaultTimeZoneName()
+	}
+	locationsMu.Lock()
+	defer locationsMu.Unlock()
+	if location, ok := locations[name]; ok {
+		return location, nil
+	}
+	location, err := time.LoadLocation(name)
+	if err != nil {
+		return nil, fmt.Errorf("unknown time zone: %s", name)
+	}
+	locations[name] = location
+	return location, nil
+}
+
+// NormalizeTimeZone проверяет имя часового пояса и возвращает его каноническую запись.
+// Регистр не важен: asia/yekaterinburg превращается в Asia/Yekaterinburg.
+func NormalizeTimeZone(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.EqualFold(name, "local") {
+		return "", fmt.Errorf("unknown time zone: %s", name)
+	}
+	if strings.EqualFold(name, "utc") {
+		return "UTC", nil
+	}
+	if _, err := LoadTimeZone(name); err == nil {
+		return name, nil
+	}
+
+	parts := strings.Split(name, "/")
+	for i, part := range parts {
+		words := strings.Split(strings.ToLower(part), "_")
+		for j, word := range words {
+			if word != "" {
+				words[j] = strings.ToUpper(word[:1]) + word[1:]
+			}
+		}
+		parts[i] = strings.Join(words, "_")
+	}
+	canonical := strings.Join(parts, "/")
+	if _, err := LoadTimeZone(canonical); err != nil {
+		return "", fmt.Errorf("unknown time zone: %s", name)
+	}
+	return canonical, nil
+}
+
+// FormatEventDateIn возвращает время t в часовом поясе location. Если пояс отличается
+// от часового пояса по умолчанию, к дате добавляется его название: "2025-12-31 16:30 (Asia/Yekaterinburg)".
+func FormatEventDateIn(t time.Time, location *time.Location) string {
+	formatted := FormatEventDate(t.In(location))
+	if location.String() != DefaultTimeZoneName() {
+		formatted += fmt.Sprintf(" (%s)", location)
+	}
+	return formatted
+}
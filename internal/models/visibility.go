@@ -0,0 +1,70 @@
+package models
+
+import (
+	"fmt"
+	"strings"
+)
+
+// EventVisibility определяет, кому кроме своего чата видно событие при поиске по имени
+type EventVisibility string
+
+const (
+	// VisibilityPrivate - событие видно только в своём чате (значение по умолчанию)
+	VisibilityPrivate EventVisibility = "private"
+	// VisibilityShared - событие видно в своём чате и в чатах из Event.SharedWith
+	VisibilityShared EventVisibility = "shared"
+	// VisibilityPublic - событие находится по имени в любом чате
+	VisibilityPublic EventVisibility = "public"
+)
+
+// ParseVisibility разбирает название видимости; регистр не важен
+func ParseVisibility(value string) (EventVisibility, error) {
+	switch visibility := EventVisibility(strings.ToLower(value)); visibility {
+	case VisibilityPrivate, VisibilityShared, VisibilityPublic:
+		return visibility, nil
+	default:
+		return "", fmt.Errorf("unknown visibility: %s", value)
+	}
+}
+
+// EffectiveVisibility возвращает видимость события; пустое значение означает VisibilityPrivate
+func (e Event) EffectiveVisibility() EventVisibility {
+	if e.Visibility == "" {
+		return VisibilityPrivate
+	}
+	return e.Visibility
+}
+
+// IsVisibleTo сообщает, можно ли показать событие в чате chatID
+func (e Event) IsVisibleTo(chatID int64) bool {
+	if e.ChatID == chatID {
+		return true
+	}
+	switch e.EffectiveVisibility() {
+	case VisibilityPublic:
+		return true
+	case VisibilityShared:
+		for _, id := range e.SharedWith {
+			if id == chatID {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// DescribeVisibility описывает видимость события по-русски
+func (e Event) DescribeVisibility() string {
+	switch e.EffectiveVisibility() {
+	case VisibilityPublic:
+		return "публичное - видно во всех чатах"
+	case VisibilityShared:
+		ids := make([]string, len(e.SharedWith))
+		for i, id := range e.SharedWith {
+			ids[i] = fmt.Sprint(id)
+		}
+		return "видно в этом чате и в чатах " + strings.Join(ids, ", ")
+	default:
+		return "приватное - видно только в этом чате"
+	}
+}
@@ -0,0 +1,245 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"go.uber.org/zap"
+)
+
+// MaxChatCommands - ограничение Telegram на число команд в одном меню
+const MaxChatCommands = 100
+
+// maxCommandDescription - ограничение Telegram на длину описания команды в символах
+const maxCommandDescription = 256
+
+// commandPattern - допустимое имя команды в меню: строчные латинские буквы, цифры и "_", до 32 символов
+var commandPattern = regexp.MustCompile(`^[a-z0-9_]{1,32}$`)
+
+// Command - команда меню "/" в Telegram
+type Command struct {
+	Command     string
+	Description string
+}
+
+// CommandMenuClient публикует меню команд отдельного чата (setMyCommands с BotCommandScopeChat).
+// Реализация переводит ограничение частоты запросов в *RateLimitError.
+type CommandMenuClient interface {
+	// SetChatCommands заменяет меню команд чата
+	SetChatCommands(ctx context.Context, chatID int64, commands []Command) error
+	// DeleteChatCommands удаляет меню чата, после чего в нём показывается общее меню бота
+	DeleteChatCommands(ctx context.Context, chatID int64) error
+}
+
+// CommandMenu поддерживает в каждом чате меню "/" из системных команд и активных событий чата
+// вместе с его подписками, ближайшие события - первыми. Меню отправляется в Telegram, только
+// когда оно изменилось: при создании, переименовании и удалении событий обработчики вызывают
+// Refresh, а прошедшие события убираются при периодическом проходе Tick.
+type CommandMenu struct {
+	events *services.EventService
+	chats  *services.ChatService
+	client CommandMenuClient
+	clock  Clock
+	system []Command
+
+	// refreshMu не даёт двум обновлениям одного меню отправить его в неверном порядке
+	refreshMu sync.Mutex
+	mu        sync.Mutex
+	published map[int64]string
+	retryAt   time.Time
+
+	logger *zap.Logger
+}
+
+// NewCommandMenu создаёт меню команд; system - системные команды, они идут в меню первыми
+func NewCommandMenu(events *services.EventService, chats *services.ChatService, client CommandMenuClient, clock Clock, system []Command) *CommandMenu {
+	return &CommandMenu{
+		events:    events,
+		chats:     chats,
+		client:    client,
+		clock:     clock,
+		system:    system,
+		published: make(map[int64]string),
+		logger:    zap.L(),
+	}
+}
+
+// Run синхронизирует меню всех чатов при запуске, а затем с интервалом interval, пока не отменён ctx
+func (m *CommandMenu) Run(ctx context.Context, interval time.Duration) {
+	m.logger.Info("Запуск обновления меню команд", zap.Duration("interval", interval))
+	m.Tick(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			m.logger.Info("Обновление меню команд остановлено")
+			return
+		case <-ticker.C:
+			m.Tick(ctx)
+		}
+	}
+}
+
+// Tick проверяет меню всех известных чатов и возвращает число отправленных в Telegram меню.
+// Если Telegram ограничил частоту запросов, проход прерывается: оставшиеся чаты
+// обновятся при следующем проходе.
+func (m *CommandMenu) Tick(ctx context.Context) (int, error) {
+	chatIDs, err := m.chats.ChatIDs()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, chatID := range chatIDs {
+		if ctx.Err() != nil {
+			return updated, ctx.Err()
+		}
+		changed, err := m.refresh(ctx, chatID)
+		if changed {
+			updated++
+		}
+		var rateLimit *RateLimitError
+		if errors.As(err, &rateLimit) {
+			return updated, err
+		}
+	}
+	if updated > 0 {
+		m.logger.Info("Меню команд обновлены", zap.Int("count", updated))
+	}
+	return updated, nil
+}
+
+// Refresh пересчитывает меню чата и отправляет его, если оно изменилось
+func (m *CommandMenu) Refresh(ctx context.Context, chatID int64) error {
+	_, err := m.refresh(ctx, chatID)
+	return err
+}
+
+func (m *CommandMenu) refresh(ctx context.Context, chatID int64) (bool, error) {
+	m.refreshMu.Lock()
+	defer m.refreshMu.Unlock()
+
+	now := m.clock.Now()
+	m.mu.Lock()
+	limited := now.Before(m.retryAt)
+	m.mu.Unlock()
+	if limited {
+		// Меню не помечается отправленным, поэтому его обновит следующий проход после паузы
+		return false, nil
+	}
+
+	commands, err := m.Commands(chatID, now)
+	if err != nil {
+		return false, err
+	}
+	key := menuKey(commands)
+	m.mu.Lock()
+	published, known := m.published[chatID]
+	m.mu.Unlock()
+	if known && published == key {
+		return false, nil
+	}
+
+	if len(commands) == len(m.system) {
+		// Без событий чату достаточно общего меню бота
+		err = m.client.DeleteChatCommands(ctx, chatID)
+	} else {
+		err = m.client.SetChatCommands(ctx, chatID, commands)
+	}
+	if err != nil {
+		m.handleError(chatID, err, now)
+		return false, err
+	}
+
+	m.mu.Lock()
+	m.published[chatID] = key
+	m.mu.Unlock()
+	m.logger.Debug("Меню команд чата обновлено",
+		zap.Int64("chat_id", chatID),
+		zap.Int("events", len(commands)-len(m.system)))
+	return true, nil
+}
+
+// Commands возвращает меню чата на момент now: системные команды, затем активные события
+// в порядке наступления, всего не больше MaxChatCommands. Команды в меню Telegram только строчные,
+// поэтому событие NewYear публикуется как /newyear (команды событий не зависят от регистра).
+// События, имя которых нельзя использовать как команду меню или которое совпадает
+// с системной командой, пропускаются.
+func (m *CommandMenu) Commands(chatID int64, now time.Time) ([]Command, error) {
+	upcoming, err := m.events.UpcomingEvents(chatID, now, 0)
+	if err != nil {
+		return nil, err
+	}
+
+	commands := append([]Command(nil), m.system...)
+	used := make(map[string]bool, len(commands))
+	for _, command := range commands {
+		used[command.Command] = true
+	}
+	location := m.chats.Location(chatID)
+	for _, item := range upcoming {
+		if len(commands) >= MaxChatCommands {
+			break
+		}
+		name := strings.ToLower(item.Event.Name)
+		if used[name] || !commandPattern.MatchString(name) {
+			m.logger.Debug("Событие пропущено в меню команд",
+				zap.Int64("chat_id", chatID),
+				zap.String("event_name", item.Event.Name))
+			continue
+		}
+		used[name] = true
+		description := models.FormatEventDateIn(item.Occurrence, location)
+		if item.Event.DisplayName != "" {
+			description = item.Event.DisplayName + " · " + description
+		}
+		if item.Event.Description != "" {
+			description += " · " + item.Event.Description
+		}
+		commands = append(commands, Command{Command: name, Description: truncateRunes(description, maxCommandDescription)})
+	}
+	return commands, nil
+}
+
+// handleError откладывает обновления меню при ограничении частоты и записывает остальные ошибки в лог
+func (m *CommandMenu) handleError(chatID int64, err error, now time.Time) {
+	var rateLimit *RateLimitError
+	if errors.As(err, &rateLimit) {
+		m.logger.Warn("Telegram ограничил частоту обновления меню команд",
+			zap.Int64("chat_id", chatID),
+			zap.Duration("retry_after", rateLimit.RetryAfter))
+		m.mu.Lock()
+		m.retryAt = now.Add(rateLimit.RetryAfter)
+		m.mu.Unlock()
+		return
+	}
+	m.logger.Error("Ошибка обновления меню команд", zap.Int64("chat_id", chatID), zap.Error(err))
+}
+
+// menuKey - строковое представление меню для сравнения с отправленным
+func menuKey(commands []Command) string {
+	var key strings.Builder
+	for _, command := range commands {
+		key.WriteString(command.Command)
+		key.WriteByte(0)
+		key.WriteString(command.Description)
+		key.WriteByte(0)
+	}
+	return key.String()
+}
+
+func truncateRunes(text string, limit int) string {
+	runes := []rune(text)
+	if len(runes) <= limit {
+		return text
+	}
+	return string(runes[:limit-1]) + "…"
+}
@@ -0,0 +1,359 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"go.uber.org/zap"
+)
+
+// DefaultCountdownMinEdit - минимальный промежуток между правками закреплённого отсчёта одного
+// чата. Telegram ограничивает число сообщений и правок в группе примерно 20 в минуту.
+const DefaultCountdownMinEdit = time.Minute
+
+// DefaultCountdownPinCheck - как часто проверять, что отсчёт всё ещё закреплён
+const DefaultCountdownPinCheck = 15 * time.Minute
+
+var (
+	// ErrMessageNotFound - сообщение удалено или недоступно боту
+	ErrMessageNotFound = errors.New("message not found")
+	// ErrMessageNotModified - новый текст совпадает с текущим текстом сообщения
+	ErrMessageNotModified = errors.New("message is not modified")
+)
+
+// RateLimitError - Telegram ограничил частоту запросов и просит подождать RetryAfter
+type RateLimitError struct {
+	RetryAfter time.Duration
+}
+
+func (e *RateLimitError) Error() string {
+	return fmt.Sprintf("too many requests, retry after %s", e.RetryAfter)
+}
+
+// CountdownClient - операции Telegram, нужные закреплённому отсчёту. Реализация переводит
+// ошибки Telegram в ErrMessageNotFound, ErrMessageNotModified и *RateLimitError.
+type CountdownClient interface {
+	// Post отправляет сообщение и возвращает его ID
+	Post(ctx context.Context, chatID int64, text string) (int, error)
+	Edit(ctx context.Context, chatID int64, messageID int, text string) error
+	// Pin закрепляет сообщение без уведомления участников
+	Pin(ctx context.Context, chatID int64, messageID int) error
+	Unpin(ctx context.Context, chatID int64, messageID int) error
+	// PinnedMessageID возвращает ID последнего закреплённого сообщения; 0 - закреплённых нет
+	PinnedMessageID(ctx context.Context, chatID int64) (int, error)
+}
+
+// CountdownOptions настраивает планировщик закреплённых отсчётов
+type CountdownOptions struct {
+	// MinEdit - минимальный промежуток между правками в одном чате; 0 означает DefaultCountdownMinEdit
+	MinEdit time.Duration
+	// PinCheck - период проверки закрепления; 0 означает DefaultCountdownPinCheck
+	PinCheck time.Duration
+	// ChatLocation возвращает часовой пояс чата для дат в отсчёте;
+	// если не задан, даты показываются в часовом поясе события
+	ChatLocation func(chatID int64) *time.Location
+}
+
+// CountdownScheduler поддерживает в чатах закреплённое сообщение с ближайшими событиями:
+// периодически пересчитывает текст и редактирует сообщение, если текст изменился.
+// Удалённое сообщение отправляется и закрепляется заново, снятое с закрепления - закрепляется снова.
+type CountdownScheduler struct {
+	events     *services.EventService
+	countdowns *services.CountdownService
+	client     CountdownClient
+	clock      Clock
+	minEdit    time.Duration
+	pinCheck   time.Duration
+	location   func(chatID int64) *time.Location
+
+	// mu защищает расписание правок и проверок; сами запросы к Telegram выполняются без неё
+	mu           sync.Mutex
+	nextEdit     map[int64]time.Time
+	lastPinCheck map[int64]time.Time
+
+	logger *zap.Logger
+}
+
+func NewCountdownScheduler(events *services.EventService, countdowns *services.CountdownService, client CountdownClient, clock Clock, options CountdownOptions) *CountdownScheduler {
+	minEdit := options.MinEdit
+	if minEdit <= 0 {
+		minEdit = DefaultCountdownMinEdit
+	}
+	pinCheck := options.PinCheck
+	if pinCheck <= 0 {
+		pinCheck = DefaultCountdownPinCheck
+	}
+	return &CountdownScheduler{
+		events:       events,
+		countdowns:   countdowns,
+		client:       client,
+		clock:        clock,
+		minEdit:      minEdit,
+		pinCheck:     pinCheck,
+		location:     options.ChatLocation,
+		nextEdit:     make(map[int64]time.Time),
+		lastPinCheck: make(map[int64]time.Time),
+		logger:       zap.L(),
+	}
+}
+
+// Pin отправляет и закрепляет отсчёт size ближайших событий чата, заменяя прежний.
+// Если закрепить не удалось (например, у бота нет права закреплять сообщения), отсчёт
+// всё равно сохраняется и обновляется, а ошибка закрепления возвращается вместе с ним.
+func (s *CountdownScheduler) Pin(ctx context.Context, chatID int64, size int) (*models.PinnedCountdown, error) {
+	now := s.clock.Now()
+	text, err := s.render(chatID, size, now)
+	if err != nil {
+		return nil, err
+	}
+	if previous, err := s.countdowns.Get(chatID); err == nil {
+		if err := s.client.Unpin(ctx, chatID, previous.MessageID); err != nil {
+			s.logger.Debug("Не удалось открепить прежний отсчёт", zap.Int64("chat_id", chatID), zap.Error(err))
+		}
+	}
+
+	countdown := models.PinnedCountdown{ChatID: chatID, Size: size, Text: text, UpdatedAt: now}
+	pinErr := s.post(ctx, &countdown)
+	if countdown.MessageID == 0 {
+		return nil, pinErr
+	}
+	if err := s.countdowns.Save(countdown); err != nil {
+		return nil, err
+	}
+	s.scheduleEdit(chatID, now.Add(s.minEdit))
+	s.mu.Lock()
+	s.lastPinCheck[chatID] = now
+	s.mu.Unlock()
+	s.logger.Info("Отсчёт закреплён",
+		zap.Int64("chat_id", chatID),
+		zap.Int("message_id", countdown.MessageID),
+		zap.Int("size", size))
+	return &countdown, pinErr
+}
+
+// Unpin отключает отсчёт чата и открепляет его сообщение
+func (s *CountdownScheduler) Unpin(ctx context.Context, chatID int64) error {
+	countdown, err := s.countdowns.Get(chatID)
+	if err != nil {
+		return err
+	}
+	if err := s.countdowns.Remove(chatID); err != nil {
+		return err
+	}
+	if err := s.client.Unpin(ctx, chatID, countdown.MessageID); err != nil {
+		s.logger.Debug("Не удалось открепить отсчёт", zap.Int64("chat_id", chatID), zap.Error(err))
+	}
+	s.mu.Lock()
+	delete(s.nextEdit, chatID)
+	delete(s.lastPinCheck, chatID)
+	s.mu.Unlock()
+	return nil
+}
+
+// Run обновляет отсчёты с интервалом interval, пока не отменён ctx
+func (s *CountdownScheduler) Run(ctx context.Context, interval time.Duration) {
+	s.logger.Info("Запуск обновления закреплённых отсчётов", zap.Duration("interval", interval))
+	s.Tick(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Обновление закреплённых отсчётов остановлено")
+			return
+		case <-ticker.C:
+			s.Tick(ctx)
+		}
+	}
+}
+
+// Tick выполняет один проход по всем отсчётам и возвращает число изменённых сообщений
+func (s *CountdownScheduler) Tick(ctx context.Context) (int, error) {
+	countdowns, err := s.countdowns.All()
+	if err != nil {
+		return 0, err
+	}
+
+	updated := 0
+	for _, countdown := range countdowns {
+		if ctx.Err() != nil {
+			return updated, ctx.Err()
+		}
+		if s.refresh(ctx, countdown, s.clock.Now()) {
+			updated++
+		}
+	}
+	return updated, nil
+}
+
+// refresh обновляет текст одного отсчёта, если он изменился и правка не нарушит ограничений
+func (s *CountdownScheduler) refresh(ctx context.Context, countdown models.PinnedCountdown, now time.Time) bool {
+	if !s.editAllowed(countdown.ChatID, now) {
+		return false
+	}
+	s.mu.Lock()
+	checkPin := now.Sub(s.lastPinCheck[countdown.ChatID]) >= s.pinCheck
+	s.mu.Unlock()
+	if checkPin {
+		if s.ensurePinned(ctx, &countdown, now) {
+			return true
+		}
+		if !s.editAllowed(countdown.ChatID, now) {
+			return false
+		}
+	}
+
+	text, err := s.render(countdown.ChatID, countdown.Size, now)
+	if err != nil || text == countdown.Text {
+		return false
+	}
+
+	err = s.client.Edit(ctx, countdown.ChatID, countdown.MessageID, text)
+	switch {
+	case err == nil, errors.Is(err, ErrMessageNotModified):
+	case errors.Is(err, ErrMessageNotFound):
+		// Сообщение удалили - отправляем отсчёт заново
+		s.logger.Info("Закреплённый отсчёт удалён, отправляем заново", zap.Int64("chat_id", countdown.ChatID))
+		countdown.Text = text
+		return s.repost(ctx, countdown, now)
+	default:
+		s.handleError(countdown.ChatID, err, now)
+		return false
+	}
+
+	countdown.Text = text
+	countdown.UpdatedAt = now
+	s.scheduleEdit(countdown.ChatID, now.Add(s.minEdit))
+	return s.countdowns.Save(countdown) == nil
+}
+
+// ensurePinned закрепляет отсчёт снова, если в чате не осталось закреплённых сообщений.
+// Если закреплено другое сообщение, отсчёт не трогаем: участники могли закрепить что-то поверх него.
+// Возвращает true, если сообщение пришлось отправить заново.
+func (s *CountdownScheduler) ensurePinned(ctx context.Context, countdown *models.PinnedCountdown, now time.Time) bool {
+	pinnedID, err := s.client.PinnedMessageID(ctx, countdown.ChatID)
+	if err != nil {
+		s.handleError(countdown.ChatID, err, now)
+		return false
+	}
+	s.mu.Lock()
+	s.lastPinCheck[countdown.ChatID] = now
+	s.mu.Unlock()
+	if pinnedID != 0 {
+		return false
+	}
+
+	s.logger.Info("Отсчёт откреплён, закрепляем снова", zap.Int64("chat_id", countdown.ChatID))
+	err = s.client.Pin(ctx, countdown.ChatID, countdown.MessageID)
+	switch {
+	case err == nil:
+		return false
+	case errors.Is(err, ErrMessageNotFound):
+		if text, err := s.render(countdown.ChatID, countdown.Size, now); err == nil {
+			countdown.Text = text
+		}
+		return s.repost(ctx, *countdown, now)
+	default:
+		s.handleError(countdown.ChatID, err, now)
+		return false
+	}
+}
+
+// repost отправляет отсчёт новым сообщением и сохраняет его ID
+func (s *CountdownScheduler) repost(ctx context.Context, countdown models.PinnedCountdown, now time.Time) bool {
+	err := s.post(ctx, &countdown)
+	if countdown.MessageID == 0 || errors.Is(err, ErrMessageNotFound) {
+		s.handleError(countdown.ChatID, err, now)
+		return false
+	}
+	if err != nil {
+		s.logger.Warn("Не удалось закрепить отсчёт", zap.Int64("chat_id", countdown.ChatID), zap.Error(err))
+	}
+	countdown.UpdatedAt = now
+	s.scheduleEdit(countdown.ChatID, now.Add(s.minEdit))
+	return s.countdowns.Save(countdown) == nil
+}
+
+// post отправляет текст отсчёта и закрепляет сообщение. MessageID заполняется, если сообщение
+// отправлено, даже когда закрепить его не удалось.
+func (s *CountdownScheduler) post(ctx context.Context, countdown *models.PinnedCountdown) error {
+	messageID, err := s.client.Post(ctx, countdown.ChatID, countdown.Text)
+	if err != nil {
+		return err
+	}
+	countdown.MessageID = messageID
+	return s.client.Pin(ctx, countdown.ChatID, messageID)
+}
+
+// handleError откладывает правки чата при ограничении частоты и записывает остальные ошибки в лог
+func (s *CountdownScheduler) handleError(chatID int64, err error, now time.Time) {
+	var rateLimit *RateLimitError
+	if errors.As(err, &rateLimit) {
+		s.logger.Warn("Telegram ограничил частоту правок отсчёта",
+			zap.Int64("chat_id", chatID),
+			zap.Duration("retry_after", rateLimit.RetryAfter))
+		s.scheduleEdit(chatID, now.Add(rateLimit.RetryAfter))
+		return
+	}
+	s.logger.Error("Ошибка обновления закреплённого отсчёта", zap.Int64("chat_id", chatID), zap.Error(err))
+	s.scheduleEdit(chatID, now.Add(s.minEdit))
+}
+
+func (s *CountdownScheduler) editAllowed(chatID int64, now time.Time) bool {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return !now.Before(s.nextEdit[chatID])
+}
+
+func (s *CountdownScheduler) scheduleEdit(chatID int64, at time.Time) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.nextEdit[chatID] = at
+}
+
+func (s *CountdownScheduler) render(chatID int64, size int, now time.Time) (string, error) {
+	upcoming, err := s.events.UpcomingEvents(chatID, now, size)
+	if err != nil {
+		return "", err
+	}
+	var location *time.Location
+	if s.location != nil {
+		location = s.location(chatID)
+	}
+	return CountdownText(upcoming, now, location), nil
+}
+
+// CountdownText формирует текст закреплённого отсчёта на момент now; даты показываются
+// в часовом поясе location (nil - в часовом поясе события). Оставшееся время округляется
+// до часов, пока до события больше суток, чтобы сообщение не приходилось править каждую минуту.
+func CountdownText(upcoming []services.UpcomingEvent, now time.Time, location *time.Location) string {
+	if len(upcoming) == 0 {
+		return "Ближайших событий нет. Добавьте событие командой /new"
+	}
+	message := "Ближайшие события:"
+	for _, item := range upcoming {
+		occurrenceLocation := location
+		if occurrenceLocation == nil {
+			occurrenceLocation = item.Occurrence.Location()
+		}
+		message += fmt.Sprintf("\n- %s: %s - %s", item.Event.Title(),
+			models.FormatEventDateIn(item.Occurrence, occurrenceLocation), describeRemaining(item.Occurrence.Sub(now)))
+	}
+	return message
+}
+
+func describeRemaining(remaining time.Duration) string {
+	if remaining < time.Minute {
+		return "наступает"
+	}
+	if remaining >= 24*time.Hour {
+		remaining = remaining.Truncate(time.Hour)
+	}
+	return models.DescribeTimeUntil(remaining)
+}
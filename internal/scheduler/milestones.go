@@ -0,0 +1,161 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"go.uber.org/zap"
+)
+
+// milestoneOffsetPrefix отличает объявления о круглых датах от напоминаний в общем журнале
+// отправок: смещение напоминания не начинается с "+"
+const milestoneOffsetPrefix = "+"
+
+// MilestoneOptions настраивает объявления о круглых датах
+type MilestoneOptions struct {
+	// CatchUp - допустимое опоздание объявления; 0 означает DefaultCatchUp
+	CatchUp time.Duration
+	// ChatLocation возвращает часовой пояс чата для дат в тексте объявлений;
+	// если не задан, даты показываются в часовом поясе события
+	ChatLocation func(chatID int64) *time.Location
+}
+
+// MilestoneScheduler объявляет в чатах о круглых датах с событий в режиме models.ModeCountUp,
+// для которых включены объявления: 100, 500, 1000 дней, годовщины. Отправленные объявления
+// записываются в журнал напоминаний (ReminderService) с датой события и смещением "+<веха>",
+// поэтому после перезапуска они не повторяются, а после смены даты события отсчёт начинается заново.
+type MilestoneScheduler struct {
+	events    *services.EventService
+	reminders *services.ReminderService
+	send      SendFunc
+	clock     Clock
+	catchUp   time.Duration
+	location  func(chatID int64) *time.Location
+	logger    *zap.Logger
+}
+
+func NewMilestoneScheduler(events *services.EventService, reminders *services.ReminderService, send SendFunc, clock Clock, options MilestoneOptions) *MilestoneScheduler {
+	catchUp := options.CatchUp
+	if catchUp <= 0 {
+		catchUp = DefaultCatchUp
+	}
+	return &MilestoneScheduler{
+		events:    events,
+		reminders: reminders,
+		send:      send,
+		clock:     clock,
+		catchUp:   catchUp,
+		location:  options.ChatLocation,
+		logger:    zap.L(),
+	}
+}
+
+// Run проверяет круглые даты с интервалом interval, пока не отменён ctx
+func (s *MilestoneScheduler) Run(ctx context.Context, interval time.Duration) {
+	s.logger.Info("Запуск объявлений о круглых датах", zap.Duration("interval", interval))
+	s.Tick(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Объявления о круглых датах остановлены")
+			return
+		case <-ticker.C:
+			s.Tick(ctx)
+		}
+	}
+}
+
+// Tick выполняет один проход по всем событиям и возвращает число отправленных объявлений
+func (s *MilestoneScheduler) Tick(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	events, err := s.events.GetAllEvents()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		if !event.IsCountUp() || !event.AnnounceMilestones || event.IsRecurring() {
+			continue
+		}
+		if s.processEvent(ctx, event, now) {
+			sent++
+		}
+	}
+	return sent, nil
+}
+
+// processEvent объявляет последнюю круглую дату события, наступившую не раньше чем catchUp
+// назад. Если за время простоя наступило несколько круглых дат, объявляется только последняя.
+func (s *MilestoneScheduler) processEvent(ctx context.Context, event models.Event, now time.Time) bool {
+	start, err := event.Time()
+	if err != nil {
+		s.logger.Warn("Некорректная дата события",
+			zap.Int64("chat_id", event.ChatID),
+			zap.String("event_name", event.Name),
+			zap.Error(err))
+		return false
+	}
+	milestones := models.MilestonesBetween(start, now.Add(-s.catchUp), now)
+	if len(milestones) == 0 {
+		return false
+	}
+	milestone := milestones[len(milestones)-1]
+
+	delivered, err := s.reminders.Delivered(event.ChatID, event.EventID)
+	if err != nil {
+		return false
+	}
+	occurrence := models.FormatEventDate(start)
+	offset := milestoneOffsetPrefix + milestone.Key()
+	if delivered[services.DeliveryKey(occurrence, offset)] {
+		return false
+	}
+
+	location := start.Location()
+	if s.location != nil {
+		location = s.location(event.ChatID)
+	}
+	if err := s.send(ctx, event.ChatID, MilestoneText(event, milestone, location)); err != nil {
+		s.logger.Error("Ошибка отправки объявления о круглой дате",
+			zap.Int64("chat_id", event.ChatID),
+			zap.String("event_name", event.Name),
+			zap.Error(err))
+		return false
+	}
+	delivery := models.ReminderDelivery{
+		EventID:    event.EventID,
+		Occurrence: occurrence,
+		Offset:     offset,
+		SentAt:     now,
+	}
+	if err := s.reminders.MarkDelivered(event.ChatID, delivery); err != nil {
+		return false
+	}
+	s.logger.Info("Объявлена круглая дата",
+		zap.Int64("chat_id", event.ChatID),
+		zap.String("event_name", event.Name),
+		zap.String("milestone", milestone.Key()))
+	return true
+}
+
+// MilestoneText формирует объявление о круглой дате с события; дата события показывается
+// в часовом поясе location
+func MilestoneText(event models.Event, milestone models.Milestone, location *time.Location) string {
+	start, _ := event.Time()
+	message := fmt.Sprintf("Сегодня %s с события '%s'!\n", milestone, event.Title())
+	message += fmt.Sprintf("Дата: %s", models.FormatEventDateIn(start, location))
+	if event.Description != "" {
+		message += fmt.Sprintf("\nОписание: %s", event.Description)
+	}
+	return message
+}
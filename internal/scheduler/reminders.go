@@ -0,0 +1,230 @@
+package scheduler
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"go.uber.org/zap"
+)
+
+// DefaultCatchUp - насколько напоминание может опоздать (например, после перезапуска бота),
+// чтобы его всё ещё имело смысл отправить
+const DefaultCatchUp = 12 * time.Hour
+
+// Clock возвращает текущее время; в тестах подменяется фиксированными часами
+type Clock interface {
+	Now() time.Time
+}
+
+// SystemClock - часы реального времени
+type SystemClock struct{}
+
+func (SystemClock) Now() time.Time {
+	return time.Now()
+}
+
+// SendFunc отправляет текст напоминания в чат
+type SendFunc func(ctx context.Context, chatID int64, text string) error
+
+// ReminderOptions настраивает планировщик напоминаний
+type ReminderOptions struct {
+	// Defaults - смещения для событий без собственной настройки напоминаний
+	Defaults []string
+	// CatchUp - допустимое опоздание напоминания; 0 означает DefaultCatchUp
+	CatchUp time.Duration
+	// ChatLocation возвращает часовой пояс чата для дат в тексте напоминаний;
+	// если не задан, даты показываются в часовом поясе события
+	ChatLocation func(chatID int64) *time.Location
+}
+
+// ReminderScheduler рассылает напоминания о приближающихся событиях в их чаты.
+// Отправленные напоминания сохраняются, поэтому после перезапуска они не дублируются,
+// а пропущенные за время простоя отправляются, если ещё не истёк CatchUp.
+type ReminderScheduler struct {
+	events    *services.EventService
+	reminders *services.ReminderService
+	send      SendFunc
+	clock     Clock
+	defaults  []string
+	catchUp   time.Duration
+	location  func(chatID int64) *time.Location
+	logger    *zap.Logger
+}
+
+func NewReminderScheduler(events *services.EventService, reminders *services.ReminderService, send SendFunc, clock Clock, options ReminderOptions) *ReminderScheduler {
+	logger := zap.L()
+	catchUp := options.CatchUp
+	if catchUp <= 0 {
+		catchUp = DefaultCatchUp
+	}
+	return &ReminderScheduler{
+		events:    events,
+		reminders: reminders,
+		send:      send,
+		clock:     clock,
+		defaults:  options.Defaults,
+		catchUp:   catchUp,
+		location:  options.ChatLocation,
+		logger:    logger,
+	}
+}
+
+// Run проверяет напоминания с интервалом interval, пока не отменён ctx
+func (s *ReminderScheduler) Run(ctx context.Context, interval time.Duration) {
+	s.logger.Info("Запуск планировщика напоминаний", zap.Duration("interval", interval))
+	s.Tick(ctx)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Планировщик напоминаний остановлен")
+			return
+		case <-ticker.C:
+			s.Tick(ctx)
+		}
+	}
+}
+
+// Tick выполняет один проход по всем событиям и возвращает число отправленных напоминаний
+func (s *ReminderScheduler) Tick(ctx context.Context) (int, error) {
+	now := s.clock.Now()
+	events, err := s.events.GetAllEvents()
+	if err != nil {
+		return 0, err
+	}
+
+	sent := 0
+	for _, event := range events {
+		if ctx.Err() != nil {
+			return sent, ctx.Err()
+		}
+		sent += s.processEvent(ctx, event, now)
+	}
+	return sent, nil
+}
+
+// processEvent отправляет напоминания по каждому повторению события, время напоминаний
+// которого уже наступило. Для одного повторения отправляется только самое позднее из
+// наступивших напоминаний: после долгого простоя не нужно слать "за 7 дней" и "за 1 день" сразу.
+func (s *ReminderScheduler) processEvent(ctx context.Context, event models.Event, now time.Time) int {
+	offsets, err := event.ReminderOffsets(s.defaults)
+	if err != nil {
+		s.logger.Warn("Некорректные напоминания события",
+			zap.Int64("chat_id", event.ChatID),
+			zap.String("event_name", event.Name),
+			zap.Error(err))
+		return 0
+	}
+	if len(offsets) == 0 {
+		return 0
+	}
+	var maxOffset time.Duration
+	for _, offset := range offsets {
+		if offset > maxOffset {
+			maxOffset = offset
+		}
+	}
+
+	var delivered map[string]bool
+	sent := 0
+	from := now.Add(-s.catchUp)
+	for {
+		occurrence, ok, err := event.NextOccurrence(from)
+		if err != nil || !ok || occurrence.Add(-maxOffset).After(now) {
+			break
+		}
+
+		due, found := s.dueOffset(offsets, occurrence, now)
+		if found {
+			if delivered == nil {
+				delivered, err = s.reminders.Delivered(event.ChatID, event.EventID)
+				if err != nil {
+					return sent
+				}
+			}
+			occurrenceKey := models.FormatEventDate(occurrence)
+			offsetKey := models.FormatReminderOffset(due)
+			if !delivered[services.DeliveryKey(occurrenceKey, offsetKey)] {
+				if s.deliver(ctx, event, occurrence, due, now) {
+					delivered[services.DeliveryKey(occurrenceKey, offsetKey)] = true
+					sent++
+				}
+			}
+		}
+
+		if !event.IsRecurring() {
+			break
+		}
+		from = occurrence.Add(time.Nanosecond)
+	}
+	return sent
+}
+
+// dueOffset выбирает наименьшее смещение, время которого наступило не раньше чем catchUp назад
+func (s *ReminderScheduler) dueOffset(offsets []time.Duration, occurrence, now time.Time) (time.Duration, bool) {
+	var due time.Duration
+	found := false
+	for _, offset := range offsets {
+		fireAt := occurrence.Add(-offset)
+		if fireAt.After(now) || !fireAt.After(now.Add(-s.catchUp)) {
+			continue
+		}
+		if !found || offset < due {
+			due = offset
+			found = true
+		}
+	}
+	return due, found
+}
+
+func (s *ReminderScheduler) deliver(ctx context.Context, event models.Event, occurrence time.Time, offset time.Duration, now time.Time) bool {
+	location := occurrence.Location()
+	if s.location != nil {
+		location = s.location(event.ChatID)
+	}
+	text := ReminderText(event, occurrence, now, location)
+	if err := s.send(ctx, event.ChatID, text); err != nil {
+		s.logger.Error("Ошибка отправки напоминания",
+			zap.Int64("chat_id", event.ChatID),
+			zap.String("event_name", event.Name),
+			zap.Error(err))
+		return false
+	}
+
+	delivery := models.ReminderDelivery{
+		EventID:    event.EventID,
+		Occurrence: models.FormatEventDate(occurrence),
+		Offset:     models.FormatReminderOffset(offset),
+		SentAt:     now,
+	}
+	if err := s.reminders.MarkDelivered(event.ChatID, delivery); err != nil {
+		return false
+	}
+	s.logger.Info("Напоминание отправлено",
+		zap.Int64("chat_id", event.ChatID),
+		zap.String("event_name", event.Name),
+		zap.String("occurrence", delivery.Occurrence),
+		zap.String("offset", delivery.Offset))
+	return true
+}
+
+// ReminderText формирует текст напоминания о повторении события occurrence на момент now;
+// дата показывается в часовом поясе location
+func ReminderText(event models.Event, occurrence, now time.Time, location *time.Location) string {
+	var message string
+	if remaining := occurrence.Sub(now); remaining >= time.Minute {
+		message = fmt.Sprintf("Напоминание: до события '%s' осталось %s\n", event.Title(), models.DescribeDuration(remaining))
+	} else {
+		message = fmt.Sprintf("Событие '%s' наступило!\n", event.Title())
+	}
+	message += fmt.Sprintf("Дата: %s", models.FormatEventDateIn(occurrence, location))
+	if event.Description != "" {
+		message += fmt.Sprintf("\nОписание: %s", event.Description)
+	}
+	return message
+}
@@ -0,0 +1,208 @@
+package services
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"go.uber.org/zap"
+)
+
+var (
+	ErrInvalidCatalogName = errors.New("invalid catalog name")
+	// ErrCatalogOwnedByOtherChat возвращается при попытке изменить чужой каталог
+	ErrCatalogOwnedByOtherChat = errors.New("catalog belongs to another chat")
+	// ErrOwnCatalog возвращается при попытке подписать чат на его собственный каталог
+	ErrOwnCatalog = errors.New("chat owns this catalog")
+)
+
+// CatalogService управляет общими каталогами событий и подписками чатов на них
+type CatalogService struct {
+	store storage.Storage
+	// mu не даёт параллельным публикациям затереть изменения друг друга
+	mu     sync.Mutex
+	logger *zap.Logger
+}
+
+func NewCatalogService(store storage.Storage) *CatalogService {
+	return &CatalogService{
+		store:  store,
+		logger: zap.L(),
+	}
+}
+
+// Publish публикует событие чата в каталоге. Каталога ещё нет - он создаётся,
+// и чат становится его владельцем; публиковать в чужой каталог нельзя.
+func (s *CatalogService) Publish(chatID int64, catalogName, eventName string) error {
+	if !models.IsValidCatalogName(catalogName) {
+		return ErrInvalidCatalogName
+	}
+	event, err := findEvent(s.store, chatID, eventName)
+	if err != nil {
+		s.logger.Warn("Событие для публикации не найдено",
+			zap.Int64("chat_id", chatID),
+			zap.String("event_name", eventName),
+			zap.Error(err))
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	catalog, err := s.store.GetCatalog(catalogName)
+	switch {
+	case errors.Is(err, storage.ErrCatalogNotFound):
+		catalog = &models.Catalog{Name: catalogName, OwnerChatID: chatID}
+		s.logger.Info("Создание каталога",
+			zap.Int64("chat_id", chatID),
+			zap.String("catalog", catalogName))
+	case err != nil:
+		s.logger.Error("Ошибка получения каталога", zap.Error(err))
+		return err
+	case catalog.OwnerChatID != chatID:
+		return ErrCatalogOwnedByOtherChat
+	}
+	if catalog.HasEvent(event.EventID) {
+		return nil
+	}
+
+	catalog.EventIDs = append(catalog.EventIDs, event.EventID)
+	if err := s.store.SaveCatalog(*catalog); err != nil {
+		s.logger.Error("Ошибка сохранения каталога", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Событие опубликовано",
+		zap.Int64("chat_id", chatID),
+		zap.String("catalog", catalogName),
+		zap.String("event_name", eventName))
+	return nil
+}
+
+// Unpublish убирает событие чата из его каталога
+func (s *CatalogService) Unpublish(chatID int64, catalogName, eventName string) error {
+	event, err := findEvent(s.store, chatID, eventName)
+	if err != nil {
+		return err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	catalog, err := s.store.GetCatalog(catalogName)
+	if err != nil {
+		return err
+	}
+	if catalog.OwnerChatID != chatID {
+		return ErrCatalogOwnedByOtherChat
+	}
+	eventIDs := make([]string, 0, len(catalog.EventIDs))
+	for _, id := range catalog.EventIDs {
+		if id != event.EventID {
+			eventIDs = append(eventIDs, id)
+		}
+	}
+	if len(eventIDs) == len(catalog.EventIDs) {
+		return storage.ErrEventNotFound
+	}
+	catalog.EventIDs = eventIDs
+	if err := s.store.SaveCatalog(*catalog); err != nil {
+		s.logger.Error("Ошибка сохранения каталога", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Событие убрано из каталога",
+		zap.Int64("chat_id", chatID),
+		zap.String("catalog", catalogName),
+		zap.String("event_name", eventName))
+	return nil
+}
+
+// Subscribe подписывает чат на каталог: его события появляются в списках чата
+// и доступны как команды
+func (s *CatalogService) Subscribe(chatID int64, catalogName string) error {
+	if !models.IsValidCatalogName(catalogName) {
+		return ErrInvalidCatalogName
+	}
+	catalog, err := s.store.GetCatalog(catalogName)
+	if err != nil {
+		return err
+	}
+	if catalog.OwnerChatID == chatID {
+		return ErrOwnCatalog
+	}
+	if err := s.store.Subscribe(chatID, catalogName); err != nil {
+		s.logger.Error("Ошибка подписки на каталог", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Чат подписан на каталог",
+		zap.Int64("chat_id", chatID),
+		zap.String("catalog", catalogName))
+	return nil
+}
+
+func (s *CatalogService) Unsubscribe(chatID int64, catalogName string) error {
+	if err := s.store.Unsubscribe(chatID, catalogName); err != nil {
+		return err
+	}
+	s.logger.Info("Чат отписан от каталога",
+		zap.Int64("chat_id", chatID),
+		zap.String("catalog", catalogName))
+	return nil
+}
+
+// Subscriptions возвращает каталоги, на которые подписан чат
+func (s *CatalogService) Subscriptions(chatID int64) ([]string, error) {
+	subscriptions, err := s.store.GetSubscriptions(chatID)
+	if err != nil {
+		s.logger.Error("Ошибка получения подписок чата", zap.Int64("chat_id", chatID), zap.Error(err))
+	}
+	return subscriptions, err
+}
+
+// MigrateTestChat переносит прежний общий тестовый чат в каталог catalogName: все его
+// события публикуются в каталоге, а остальные известные чаты подписываются на него.
+// Если каталог уже существует или в тестовом чате нет событий, ничего не делает.
+func (s *CatalogService) MigrateTestChat(testChatID int64, catalogName string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, err := s.store.GetCatalog(catalogName); !errors.Is(err, storage.ErrCatalogNotFound) {
+		return err
+	}
+	events, err := s.store.GetEvents(testChatID)
+	if err != nil {
+		return err
+	}
+	if len(events) == 0 {
+		return nil
+	}
+
+	catalog := models.Catalog{Name: catalogName, OwnerChatID: testChatID}
+	for _, event := range events {
+		catalog.EventIDs = append(catalog.EventIDs, event.EventID)
+	}
+	if err := s.store.SaveCatalog(catalog); err != nil {
+		return err
+	}
+
+	chatIDs, err := s.store.GetChatIDs()
+	if err != nil {
+		return err
+	}
+	subscribed := 0
+	for _, chatID := range chatIDs {
+		if chatID == testChatID {
+			continue
+		}
+		if err := s.store.Subscribe(chatID, catalogName); err != nil {
+			return err
+		}
+		subscribed++
+	}
+	s.logger.Info("События тестового чата перенесены в каталог",
+		zap.Int64("test_chat_id", testChatID),
+		zap.String("catalog", catalogName),
+		zap.Int("events", len(catalog.EventIDs)),
+		zap.Int("subscribed_chats", subscribed))
+	return nil
+}
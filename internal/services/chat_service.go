@@ -0,0 +1,124 @@
+package services
+
+import (
+	"errors"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"go.uber.org/zap"
+)
+
+// ErrInvalidCreatePolicy возвращается при попытке ограничить создание событий их создателями
+var ErrInvalidCreatePolicy = errors.New("create policy must be everyone or admins")
+
+// ChatService управляет настройками чатов
+type ChatService struct {
+	store  storage.Storage
+	logger *zap.Logger
+}
+
+func NewChatService(store storage.Storage) *ChatService {
+	logger := zap.L()
+	return &ChatService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+func (s *ChatService) GetSettings(chatID int64) (models.ChatSettings, error) {
+	settings, err := s.store.GetChatSettings(chatID)
+	if err != nil {
+		s.logger.Error("Ошибка получения настроек чата",
+			zap.Int64("chat_id", chatID),
+			zap.Error(err))
+	}
+	return settings, err
+}
+
+// SetTimeZone задаёт часовой пояс чата; пустая строка возвращает часовой пояс по умолчанию.
+// Возвращает каноническое имя часового пояса.
+func (s *ChatService) SetTimeZone(chatID int64, timeZone string) (string, error) {
+	if timeZone != "" {
+		normalized, err := models.NormalizeTimeZone(timeZone)
+		if err != nil {
+			s.logger.Warn("Некорректный часовой пояс", zap.String("time_zone", timeZone), zap.Error(err))
+			return "", err
+		}
+		timeZone = normalized
+	}
+
+	settings, err := s.GetSettings(chatID)
+	if err != nil {
+		return "", err
+	}
+	settings.TimeZone = timeZone
+	if err := s.store.SaveChatSettings(chatID, settings); err != nil {
+		s.logger.Error("Ошибка сохранения настроек чата", zap.Error(err))
+		return "", err
+	}
+	s.logger.Info("Часовой пояс чата изменён",
+		zap.Int64("chat_id", chatID),
+		zap.String("time_zone", timeZone))
+	return timeZone, nil
+}
+
+// Location возвращает часовой пояс, в котором чату показываются даты.
+// При ошибке используется часовой пояс по умолчанию.
+func (s *ChatService) Location(chatID int64) *time.Location {
+	settings, err := s.GetSettings(chatID)
+	if err == nil {
+		if location, err := models.LoadTimeZone(settings.TimeZone); err == nil {
+			return location
+		}
+	}
+	location, err := models.LoadTimeZone("")
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
+// ChatIDs возвращает все известные боту чаты в порядке их появления
+func (s *ChatService) ChatIDs() ([]int64, error) {
+	chatIDs, err := s.store.GetChatIDs()
+	if err != nil {
+		s.logger.Error("Ошибка получения списка чатов", zap.Error(err))
+	}
+	return chatIDs, err
+}
+
+// SetCreatePolicy задаёт, кто может создавать события в чате. PolicyOwners для создания
+// не имеет смысла - у нового события ещё нет создателя.
+func (s *ChatService) SetCreatePolicy(chatID int64, policy models.PermissionPolicy) error {
+	if policy == models.PolicyOwners {
+		return ErrInvalidCreatePolicy
+	}
+	return s.updateSettings(chatID, func(settings *models.ChatSettings) {
+		settings.CreatePolicy = policy
+	})
+}
+
+// SetEditPolicy задаёт, кто может изменять и удалять события чата
+func (s *ChatService) SetEditPolicy(chatID int64, policy models.PermissionPolicy) error {
+	return s.updateSettings(chatID, func(settings *models.ChatSettings) {
+		settings.EditPolicy = policy
+	})
+}
+
+func (s *ChatService) updateSettings(chatID int64, update func(settings *models.ChatSettings)) error {
+	settings, err := s.GetSettings(chatID)
+	if err != nil {
+		return err
+	}
+	update(&settings)
+	if err := s.store.SaveChatSettings(chatID, settings); err != nil {
+		s.logger.Error("Ошибка сохранения настроек чата", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Политики чата изменены",
+		zap.Int64("chat_id", chatID),
+		zap.String("create_policy", string(settings.EffectiveCreatePolicy())),
+		zap.String("edit_policy", string(settings.EffectiveEditPolicy())))
+	return nil
+}
@@ -0,0 +1,183 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"go.uber.org/zap"
+)
+
+// ErrConversationExpired возвращается, если диалог не продолжался дольше таймаута
+var ErrConversationExpired = errors.New("conversation expired")
+
+// ConversationService ведёт пошаговое создание событий: имя → дата → время → описание → подтверждение
+type ConversationService struct {
+	store   storage.Storage
+	timeout time.Duration
+	logger  *zap.Logger
+}
+
+func NewConversationService(store storage.Storage, timeout time.Duration) *ConversationService {
+	logger := zap.L()
+	return &ConversationService{
+		store:   store,
+		timeout: timeout,
+		logger:  logger,
+	}
+}
+
+// Start начинает новый диалог пользователя в чате, заменяя незавершённый
+func (s *ConversationService) Start(chatID, userID int64, now time.Time) (*models.Conversation, error) {
+	s.logger.Info("Начало создания события",
+		zap.Int64("chat_id", chatID),
+		zap.Int64("user_id", userID))
+	conversation := &models.Conversation{
+		ChatID: chatID,
+		UserID: userID,
+		Step:   models.StepName,
+	}
+	if err := s.save(conversation, now); err != nil {
+		return nil, err
+	}
+	return conversation, nil
+}
+
+// Active возвращает незавершённый диалог пользователя. Просроченный диалог удаляется,
+// и возвращается ErrConversationExpired.
+func (s *ConversationService) Active(chatID, userID int64, now time.Time) (*models.Conversation, error) {
+	conversation, err := s.store.GetConversation(chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if conversation.Expired(now, s.timeout) {
+		s.logger.Info("Диалог создания события истёк",
+			zap.Int64("chat_id", chatID),
+			zap.Int64("user_id", userID))
+		s.store.DeleteConversation(chatID, userID)
+		return nil, ErrConversationExpired
+	}
+	return conversation, nil
+}
+
+// SetName проверяет имя события и переходит к выбору даты. Имя на любом языке станет
+// отображаемым именем, а совпадение его команды с другим событием разрешится при создании.
+func (s *ConversationService) SetName(conversation *models.Conversation, name string, now time.Time) error {
+	if !models.IsValidEventName(name) && !models.IsValidDisplayName(name) {
+		return errors.New("invalid event name")
+	}
+	if models.IsValidEventName(name) && models.IsSystemCommand(strings.ToLower(name)) {
+		return ErrReservedEventName
+	}
+	if models.IsValidEventName(name) && s.store.EventExists(conversation.ChatID, name) {
+		return errors.New("duplicate event name")
+	}
+	conversation.Name = name
+	conversation.Step = models.StepDate
+	return s.save(conversation, now)
+}
+
+// SetDate запоминает дату (YYYY-MM-DD) и переходит к выбору времени
+func (s *ConversationService) SetDate(conversation *models.Conversation, date string, now time.Time) error {
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return fmt.Errorf("invalid date: %s", date)
+	}
+	conversation.Date = date
+	conversation.Step = models.StepTime
+	return s.save(conversation, now)
+}
+
+// SetTime запоминает время (HH:MM) и переходит к описанию
+func (s *ConversationService) SetTime(conversation *models.Conversation, value string, now time.Time) error {
+	parsed, err := time.Parse("15:04", strings.TrimSpace(value))
+	if err != nil {
+		return fmt.Errorf("invalid time: %s", value)
+	}
+	conversation.Time = parsed.Format("15:04")
+	conversation.Step = models.StepDescription
+	return s.save(conversation, now)
+}
+
+// SetDescription запоминает описание и переходит к подтверждению
+func (s *ConversationService) SetDescription(conversation *models.Conversation, description string, now time.Time) error {
+	conversation.Description = strings.TrimSpace(description)
+	conversation.Step = models.StepConfirm
+	return s.save(conversation, now)
+}
+
+// SetMessageID запоминает сообщение бота, кнопки которого относятся к текущему шагу
+func (s *ConversationService) SetMessageID(conversation *models.Conversation, messageID int, now time.Time) error {
+	conversation.MessageID = messageID
+	return s.save(conversation, now)
+}
+
+// Finish завершает диалог (после создания события или отмены)
+func (s *ConversationService) Finish(chatID, userID int64) error {
+	err := s.store.DeleteConversation(chatID, userID)
+	if err != nil && !errors.Is(err, storage.ErrConversationNotFound) {
+		s.logger.Error("Ошибка удаления диалога", zap.Error(err))
+		return err
+	}
+	return nil
+}
+
+// ExpireConversations удаляет просроченные диалоги и возвращает их
+func (s *ConversationService) ExpireConversations(now time.Time) ([]models.Conversation, error) {
+	conversations, err := s.store.GetConversations()
+	if err != nil {
+		s.logger.Error("Ошибка получения диалогов", zap.Error(err))
+		return nil, err
+	}
+
+	var expired []models.Conversation
+	for _, conversation := range conversations {
+		if !conversation.Expired(now, s.timeout) {
+			continue
+		}
+		if err := s.store.DeleteConversation(conversation.ChatID, conversation.UserID); err != nil {
+			s.logger.Error("Ошибка удаления просроченного диалога",
+				zap.Int64("chat_id", conversation.ChatID),
+				zap.Int64("user_id", conversation.UserID),
+				zap.Error(err))
+			continue
+		}
+		expired = append(expired, conversation)
+	}
+	if len(expired) > 0 {
+		s.logger.Info("Просроченные диалоги удалены", zap.Int("count", len(expired)))
+	}
+	return expired, nil
+}
+
+// RunExpirer периодически удаляет просроченные диалоги и вызывает onExpire для каждого, пока не отменён ctx
+func (s *ConversationService) RunExpirer(ctx context.Context, interval time.Duration, onExpire func(models.Conversation)) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case now := <-ticker.C:
+			expired, _ := s.ExpireConversations(now)
+			for _, conversation := range expired {
+				onExpire(conversation)
+			}
+		}
+	}
+}
+
+func (s *ConversationService) save(conversation *models.Conversation, now time.Time) error {
+	conversation.UpdatedAt = now
+	if err := s.store.SaveConversation(*conversation); err != nil {
+		s.logger.Error("Ошибка сохранения диалога",
+			zap.Int64("chat_id", conversation.ChatID),
+			zap.Int64("user_id", conversation.UserID),
+			zap.Error(err))
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,55 @@
+package services
+
+import (
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"go.uber.org/zap"
+)
+
+// CountdownService хранит закреплённые сообщения с обратным отсчётом
+type CountdownService struct {
+	store  storage.Storage
+	logger *zap.Logger
+}
+
+func NewCountdownService(store storage.Storage) *CountdownService {
+	logger := zap.L()
+	return &CountdownService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Get возвращает закреплённый отсчёт чата или storage.ErrCountdownNotFound
+func (s *CountdownService) Get(chatID int64) (*models.PinnedCountdown, error) {
+	return s.store.GetPinnedCountdown(chatID)
+}
+
+// All возвращает закреплённые отсчёты всех чатов
+func (s *CountdownService) All() ([]models.PinnedCountdown, error) {
+	countdowns, err := s.store.GetPinnedCountdowns()
+	if err != nil {
+		s.logger.Error("Ошибка получения закреплённых отсчётов", zap.Error(err))
+	}
+	return countdowns, err
+}
+
+// Save создаёт или обновляет закреплённый отсчёт чата
+func (s *CountdownService) Save(countdown models.PinnedCountdown) error {
+	err := s.store.SavePinnedCountdown(countdown)
+	if err != nil {
+		s.logger.Error("Ошибка сохранения закреплённого отсчёта",
+			zap.Int64("chat_id", countdown.ChatID),
+			zap.Error(err))
+	}
+	return err
+}
+
+// Remove отключает закреплённый отсчёт чата
+func (s *CountdownService) Remove(chatID int64) error {
+	err := s.store.DeletePinnedCountdown(chatID)
+	if err == nil {
+		s.logger.Info("Закреплённый отсчёт отключён", zap.Int64("chat_id", chatID))
+	}
+	return err
+}
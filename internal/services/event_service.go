@@ -1,7 +1,10 @@
 package services
 
 import (
+	"context"
 	"errors"
+	"sort"
+	"strings"
 	"time"
 
 	"github.com/TheReshkin/tg-bot-family/internal/models"
@@ -9,56 +12,90 @@ import (
 	"go.uber.org/zap"
 )
 
+// ErrReservedEventName возвращается, когда имя события совпадает с командой бота: такое событие
+// нельзя было бы открыть
+var ErrReservedEventName = errors.New("event name is a bot command")
+
+// ErrRecurringCountUp возвращается при попытке вести счёт прошедшего времени для повторяющегося события
+var ErrRecurringCountUp = errors.New("count-up mode is not available for recurring events")
+
 type EventService struct {
 	store  storage.Storage
 	logger *zap.Logger
 }
 
 func NewEventService(store storage.Storage) *EventService {
-	logger, _ := zap.NewProduction()
 	return &EventService{
 		store:  store,
-		logger: logger,
+		logger: zap.L(),
 	}
 }
 
 func (s *EventService) CreateEvent(chatID int64, name, date, description string) error {
+	_, err := s.AddEvent(chatID, models.Event{
+		Name:        name,
+		Date:        date,
+		Description: description,
+	})
+	return err
+}
+
+// AddEvent проверяет и сохраняет новое событие чата, заполняя EventID, ChatID и статус.
+// Если у события не указан часовой пояс, дата понимается в часовом поясе чата.
+func (s *EventService) AddEvent(chatID int64, event models.Event) (*models.Event, error) {
 	s.logger.Info("Создание события",
 		zap.Int64("chat_id", chatID),
-		zap.String("event_name", name),
-		zap.String("date", date))
+		zap.String("event_name", event.Name),
+		zap.String("date", event.Date))
 
-	if !models.IsValidEventName(name) {
-		s.logger.Warn("Некорректное имя события", zap.String("event_name", name))
-		return errors.New("invalid event name")
+	name, displayName, err := s.eventName(chatID, event.Name, "")
+	if err != nil {
+		return nil, err
 	}
-	if !models.IsValidDate(date) {
-		s.logger.Warn("Некорректная дата", zap.String("date", date))
-		return errors.New("invalid date format")
+	event.Name, event.DisplayName = name, displayName
+	if !models.IsValidDate(event.Date) {
+		s.logger.Warn("Некорректная дата", zap.String("date", event.Date))
+		return nil, errors.New("invalid date format")
 	}
-	if s.store.EventExists(chatID, name) {
-		s.logger.Warn("Событие уже существует",
-			zap.Int64("chat_id", chatID),
-			zap.String("event_name", name))
-		return errors.New("duplicate event name")
+	if event.TimeZone == "" {
+		settings, err := s.store.GetChatSettings(chatID)
+		if err != nil {
+			s.logger.Error("Ошибка получения настроек чата", zap.Error(err))
+			return nil, err
+		}
+		event.TimeZone = settings.TimeZone
 	}
-	event := models.Event{
-		EventID:     models.GenerateEventID(),
-		Name:        name,
-		Date:        date,
-		Description: description,
-		Status:      models.StatusActive,
-		ChatID:      chatID,
+	start, err := event.Time()
+	if err != nil {
+		s.logger.Warn("Некорректный часовой пояс события", zap.String("time_zone", event.TimeZone), zap.Error(err))
+		return nil, err
 	}
-	err := s.store.SaveEvent(chatID, event)
+	if event.Recurrence != nil {
+		if err := event.Recurrence.Validate(start); err != nil {
+			s.logger.Warn("Некорректное правило повторения", zap.Error(err))
+			return nil, err
+		}
+		if event.IsCountUp() {
+			return nil, ErrRecurringCountUp
+		}
+	}
+	status, err := event.StatusAt(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	event.EventID = models.GenerateEventID()
+	event.Status = status
+	event.ChatID = chatID
+
+	err = s.store.SaveEvent(chatID, event)
 	if err != nil {
 		s.logger.Error("Ошибка сохранения события", zap.Error(err))
-		return err
+		return nil, err
 	}
 	s.logger.Info("Событие успешно создано",
 		zap.Int64("chat_id", chatID),
-		zap.String("event_name", name))
-	return nil
+		zap.String("event_name", event.Name))
+	return &event, nil
 }
 
 func (s *EventService) ListEvents(chatID int64) ([]models.Event, error) {
@@ -70,6 +107,49 @@ func (s *EventService) ListEvents(chatID int64) ([]models.Event, error) {
 	return events, err
 }
 
+// ListVisibleEvents возвращает события чата вместе с событиями каталогов, на которые он подписан
+func (s *EventService) ListVisibleEvents(chatID int64) ([]models.Event, error) {
+	events, err := s.ListEvents(chatID)
+	if err != nil {
+		return nil, err
+	}
+	err = s.forEachSubscribedEvent(chatID, func(event models.Event) bool {
+		events = append(events, event)
+		return true
+	})
+	return events, err
+}
+
+// UpcomingEvent - событие вместе с его ближайшей датой
+type UpcomingEvent struct {
+	Event      models.Event
+	Occurrence time.Time
+}
+
+// UpcomingEvents возвращает до limit ближайших событий, видимых чату (см. ListVisibleEvents),
+// в порядке наступления; limit <= 0 - без ограничения. Прошедшие события пропускаются.
+func (s *EventService) UpcomingEvents(chatID int64, now time.Time, limit int) ([]UpcomingEvent, error) {
+	events, err := s.ListVisibleEvents(chatID)
+	if err != nil {
+		return nil, err
+	}
+	upcoming := make([]UpcomingEvent, 0, len(events))
+	for _, event := range events {
+		occurrence, ok, err := event.NextOccurrence(now)
+		if err != nil || !ok {
+			continue
+		}
+		upcoming = append(upcoming, UpcomingEvent{Event: event, Occurrence: occurrence})
+	}
+	sort.SliceStable(upcoming, func(i, j int) bool {
+		return upcoming[i].Occurrence.Before(upcoming[j].Occurrence)
+	})
+	if limit > 0 && len(upcoming) > limit {
+		upcoming = upcoming[:limit]
+	}
+	return upcoming, nil
+}
+
 func (s *EventService) GetAllEvents() ([]models.Event, error) {
 	s.logger.Debug("Получение всех событий")
 	events, err := s.store.GetAllEvents()
@@ -83,7 +163,7 @@ func (s *EventService) GetEvent(chatID int64, name string) (*models.Event, error
 	s.logger.Debug("Получение события",
 		zap.Int64("chat_id", chatID),
 		zap.String("event_name", name))
-	event, err := s.store.GetEvent(chatID, name)
+	event, err := findEvent(s.store, chatID, name)
 	if err != nil {
 		s.logger.Warn("Событие не найдено",
 			zap.Int64("chat_id", chatID),
@@ -93,21 +173,93 @@ func (s *EventService) GetEvent(chatID int64, name string) (*models.Event, error
 	return event, err
 }
 
-func (s *EventService) FindEventAcrossChats(name string, excludeChatID int64) (*models.Event, int64, error) {
+// FindSubscribedEvent ищет событие по имени в каталогах, на которые подписан чат,
+// в порядке подписки
+func (s *EventService) FindSubscribedEvent(chatID int64, name string) (*models.Event, error) {
+	s.logger.Debug("Поиск события в подписках чата",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_name", name))
+	var found *models.Event
+	var folded *models.Event
+	err := s.forEachSubscribedEvent(chatID, func(event models.Event) bool {
+		if event.HasCommand(name) {
+			found = &event
+			return false
+		}
+		if folded == nil && event.HasCommandFold(name) {
+			folded = &event
+		}
+		return true
+	})
+	if err != nil {
+		return nil, err
+	}
+	if found == nil {
+		found = folded
+	}
+	if found == nil {
+		return nil, storage.ErrEventNotFound
+	}
+	s.logger.Info("Событие найдено в каталоге",
+		zap.String("event_name", name),
+		zap.Int64("found_in_chat_id", found.ChatID))
+	return found, nil
+}
+
+// FindEventAcrossChats ищет в других чатах событие, которое видно чату viewerChatID:
+// публичное или открытое для этого чата
+func (s *EventService) FindEventAcrossChats(name string, viewerChatID int64) (*models.Event, error) {
 	s.logger.Debug("Поиск события в других чатах",
 		zap.String("event_name", name),
-		zap.Int64("exclude_chat_id", excludeChatID))
-	event, chatID, err := s.store.FindEventAcrossChats(name, excludeChatID)
+		zap.Int64("viewer_chat_id", viewerChatID))
+	event, chatID, err := s.store.FindEventAcrossChats(name, viewerChatID)
 	if err != nil {
-		s.logger.Warn("Событие не найдено в других чатах",
+		s.logger.Debug("Видимое событие в других чатах не найдено",
 			zap.String("event_name", name),
 			zap.Error(err))
-	} else {
-		s.logger.Info("Событие найдено в другом чате",
+		return nil, err
+	}
+	// Хранилище уже отфильтровало события, но приватное событие не должно утечь даже при ошибке в запросе
+	if !event.IsVisibleTo(viewerChatID) {
+		s.logger.Error("Хранилище вернуло событие, невидимое для чата",
 			zap.String("event_name", name),
-			zap.Int64("found_in_chat_id", chatID))
+			zap.Int64("found_in_chat_id", chatID),
+			zap.Int64("viewer_chat_id", viewerChatID))
+		return nil, storage.ErrEventNotFound
 	}
-	return event, chatID, err
+	s.logger.Info("Событие найдено в другом чате",
+		zap.String("event_name", name),
+		zap.Int64("found_in_chat_id", chatID))
+	return event, nil
+}
+
+// forEachSubscribedEvent перебирает события каталогов, на которые подписан чат, пока visit
+// возвращает true. Собственные события чата и повторы из разных каталогов пропускаются.
+func (s *EventService) forEachSubscribedEvent(chatID int64, visit func(event models.Event) bool) error {
+	catalogs, err := s.store.GetSubscriptions(chatID)
+	if err != nil {
+		s.logger.Error("Ошибка получения подписок чата", zap.Int64("chat_id", chatID), zap.Error(err))
+		return err
+	}
+	seen := make(map[string]bool)
+	for _, catalog := range catalogs {
+		events, err := s.store.GetCatalogEvents(catalog)
+		if err != nil {
+			// Недоступный каталог не должен скрывать события остальных
+			s.logger.Warn("Ошибка получения событий каталога", zap.String("catalog", catalog), zap.Error(err))
+			continue
+		}
+		for _, event := range events {
+			if event.ChatID == chatID || seen[event.EventID] {
+				continue
+			}
+			seen[event.EventID] = true
+			if !visit(event) {
+				return nil
+			}
+		}
+	}
+	return nil
 }
 
 func (s *EventService) UpdateEventStatus(chatID int64, name string) error {
@@ -119,21 +271,402 @@ func (s *EventService) UpdateEventStatus(chatID int64, name string) error {
 		s.logger.Error("Ошибка получения события для обновления статуса", zap.Error(err))
 		return err
 	}
-	parsedDate, err := models.ParseEventDate(event.Date)
+	status, err := event.StatusAt(time.Now())
 	if err != nil {
 		s.logger.Error("Ошибка парсинга даты события", zap.Error(err))
 		return err
 	}
-	if parsedDate.Before(time.Now()) {
-		event.Status = models.StatusOutdated
-		err = s.store.SaveEvent(chatID, *event)
+	if status != event.Status {
+		err = s.store.SetEventStatus(chatID, event.EventID, status)
 		if err != nil {
 			s.logger.Error("Ошибка сохранения обновленного статуса", zap.Error(err))
 			return err
 		}
-		s.logger.Info("Статус события обновлен на устаревший",
+		s.logger.Info("Статус события обновлен",
 			zap.Int64("chat_id", chatID),
-			zap.String("event_name", name))
+			zap.String("event_name", name),
+			zap.String("status", string(status)))
 	}
 	return nil
 }
+
+// RefreshStatuses приводит статусы всех событий в соответствие с их датами на момент now
+// и возвращает количество изменённых событий
+func (s *EventService) RefreshStatuses(now time.Time) (int, error) {
+	events, err := s.store.GetAllEvents()
+	if err != nil {
+		s.logger.Error("Ошибка получения событий для обновления статусов", zap.Error(err))
+		return 0, err
+	}
+
+	updated := 0
+	for _, event := range events {
+		status, err := event.StatusAt(now)
+		if err != nil {
+			s.logger.Warn("Ошибка парсинга даты события",
+				zap.Int64("chat_id", event.ChatID),
+				zap.String("event_name", event.Name),
+				zap.Error(err))
+			continue
+		}
+		if status == event.Status {
+			continue
+		}
+		// Записывается только статус: изменение события, сделанное после чтения, не откатывается
+		if err := s.store.SetEventStatus(event.ChatID, event.EventID, status); err != nil {
+			if errors.Is(err, storage.ErrEventNotFound) {
+				continue // Событие удалено после чтения
+			}
+			s.logger.Error("Ошибка сохранения статуса события",
+				zap.Int64("chat_id", event.ChatID),
+				zap.String("event_name", event.Name),
+				zap.Error(err))
+			continue
+		}
+		updated++
+	}
+	if updated > 0 {
+		s.logger.Info("Статусы событий обновлены", zap.Int("count", updated))
+	}
+	return updated, nil
+}
+
+// RunStatusSweeper периодически обновляет статусы событий, пока не отменён ctx
+func (s *EventService) RunStatusSweeper(ctx context.Context, interval time.Duration) {
+	s.logger.Info("Запуск фонового обновления статусов", zap.Duration("interval", interval))
+	s.RefreshStatuses(time.Now())
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info("Фоновое обновление статусов остановлено")
+			return
+		case now := <-ticker.C:
+			s.RefreshStatuses(now)
+		}
+	}
+}
+
+// UpdateEventDate переносит событие на новую дату и пересчитывает его статус
+func (s *EventService) UpdateEventDate(chatID int64, name, date string) error {
+	if !models.IsValidDate(date) {
+		s.logger.Warn("Некорректная дата", zap.String("date", date))
+		return errors.New("invalid date format")
+	}
+	return s.modifyEvent(chatID, name, func(event *models.Event) error {
+		event.Date = date
+		status, err := event.StatusAt(time.Now())
+		if err != nil {
+			return err
+		}
+		event.Status = status
+		return nil
+	})
+}
+
+// SetEventTimeZone задаёт часовой пояс, в котором записана дата события; пустая
+// строка возвращает часовой пояс по умолчанию. Дата и время события не пересчитываются:
+// "18:00" остаётся 18:00, но уже по новому часовому поясу.
+func (s *EventService) SetEventTimeZone(chatID int64, name, timeZone string) error {
+	if timeZone != "" {
+		normalized, err := models.NormalizeTimeZone(timeZone)
+		if err != nil {
+			s.logger.Warn("Некорректный часовой пояс", zap.String("time_zone", timeZone), zap.Error(err))
+			return err
+		}
+		timeZone = normalized
+	}
+	return s.modifyEvent(chatID, name, func(event *models.Event) error {
+		event.TimeZone = timeZone
+		status, err := event.StatusAt(time.Now())
+		if err != nil {
+			return err
+		}
+		event.Status = status
+		return nil
+	})
+}
+
+// UpdateEventDescription заменяет описание события
+func (s *EventService) UpdateEventDescription(chatID int64, name, description string) error {
+	return s.modifyEvent(chatID, name, func(event *models.Event) error {
+		event.Description = description
+		return nil
+	})
+}
+
+// RenameEvent меняет имя события (см. eventName) и возвращает переименованное событие
+func (s *EventService) RenameEvent(chatID int64, name, newName string) (*models.Event, error) {
+	var renamed models.Event
+	err := s.modifyEvent(chatID, name, func(event *models.Event) error {
+		slug, displayName, err := s.eventName(chatID, newName, event.EventID)
+		if err != nil {
+			return err
+		}
+		event.Name, event.DisplayName = slug, displayName
+		// Псевдоним, ставший именем, больше не нужен
+		event.Aliases = removeAlias(event.Aliases, slug)
+		renamed = *event
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &renamed, nil
+}
+
+// AddAlias добавляет событию name псевдоним - ещё одну команду, по которой событие находится
+// в чате, подписках и других чатах. Псевдоним не из латиницы, цифр и "_" транслитерируется
+// (models.EventSlug). Псевдоним не должен совпадать с именем или псевдонимом другого события
+// чата, а у события может быть не больше models.MaxAliases псевдонимов. Возвращает изменённое
+// событие; новый псевдоним в нём последний.
+func (s *EventService) AddAlias(chatID int64, name, input string) (*models.Event, error) {
+	alias, err := aliasCommand(input)
+	if err != nil {
+		s.logger.Warn("Некорректный псевдоним события", zap.String("alias", input))
+		return nil, err
+	}
+	var updated models.Event
+	err = s.modifyEvent(chatID, name, func(event *models.Event) error {
+		if event.HasCommand(alias) {
+			return errors.New("event already has this alias")
+		}
+		if existing, err := s.store.GetEvent(chatID, alias); err == nil && existing.EventID != event.EventID {
+			s.logger.Warn("Псевдоним занят другим событием",
+				zap.Int64("chat_id", chatID),
+				zap.String("alias", alias),
+				zap.String("event_name", existing.Name))
+			return errors.New("alias is already used by another event")
+		}
+		if len(event.Aliases) >= models.MaxAliases {
+			return errors.New("too many aliases")
+		}
+		// Новый срез: событие из хранилища может делить массив с его данными
+		event.Aliases = append(append([]string(nil), event.Aliases...), alias)
+		updated = *event
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// RemoveAlias удаляет псевдоним события и возвращает событие, которому он принадлежал
+func (s *EventService) RemoveAlias(chatID int64, input string) (*models.Event, error) {
+	alias, err := aliasCommand(input)
+	if err != nil {
+		return nil, err
+	}
+	var updated models.Event
+	err = s.modifyEvent(chatID, alias, func(event *models.Event) error {
+		if !event.HasAlias(alias) {
+			return errors.New("not an alias")
+		}
+		event.Aliases = removeAlias(event.Aliases, alias)
+		updated = *event
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
+
+// aliasCommand возвращает команду псевдонима, введённого пользователем; как и у имени события,
+// из слишком длинного или не латинского псевдонима команда получается через models.EventSlug
+func aliasCommand(input string) (string, error) {
+	input = strings.TrimPrefix(strings.TrimSpace(input), "/")
+	if !models.IsValidEventName(input) {
+		if !models.IsValidDisplayName(input) {
+			return "", errors.New("invalid alias")
+		}
+		input = models.EventSlug(input)
+	}
+	return input, nil
+}
+
+// removeAlias возвращает список псевдонимов без alias
+func removeAlias(aliases []string, alias string) []string {
+	var kept []string
+	for _, existing := range aliases {
+		if existing != alias {
+			kept = append(kept, existing)
+		}
+	}
+	return kept
+}
+
+// eventName разбирает имя события, введённое пользователем, и возвращает команду события
+// и отображаемое имя. Имя из латиницы, цифр и "_" становится командой как есть, должно быть
+// уникальным в чате и не совпадать с командой бота. Из любого другого имени команда получается транслитерацией
+// (models.EventSlug), а если она уже занята событием или командой бота ("Help!" -> help_2),
+// к ней добавляется номер. Команда события eventID (при переименовании) занятой не считается.
+func (s *EventService) eventName(chatID int64, input, eventID string) (string, string, error) {
+	input = strings.TrimSpace(input)
+	taken := func(slug string) bool {
+		existing, err := s.store.GetEvent(chatID, slug)
+		return err == nil && existing.EventID != eventID
+	}
+	if models.IsValidEventName(input) {
+		if models.IsSystemCommand(strings.ToLower(input)) {
+			s.logger.Warn("Имя события совпадает с командой бота", zap.String("event_name", input))
+			return "", "", ErrReservedEventName
+		}
+		if taken(input) {
+			s.logger.Warn("Событие уже существует",
+				zap.Int64("chat_id", chatID),
+				zap.String("event_name", input))
+			return "", "", errors.New("duplicate event name")
+		}
+		return input, "", nil
+	}
+	if !models.IsValidDisplayName(input) {
+		s.logger.Warn("Некорректное имя события", zap.String("event_name", input))
+		return "", "", errors.New("invalid event name")
+	}
+
+	base := models.EventSlug(input)
+	slug := base
+	for n := 2; models.IsSystemCommand(slug) || taken(slug); n++ {
+		slug = models.NumberedSlug(base, n)
+	}
+	return slug, input, nil
+}
+
+// DeleteEvent удаляет событие чата по его идентификатору
+func (s *EventService) DeleteEvent(chatID int64, eventID string) error {
+	s.logger.Info("Удаление события",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_id", eventID))
+	err := s.store.DeleteEvent(chatID, eventID)
+	if err != nil {
+		s.logger.Error("Ошибка удаления события", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Событие удалено",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_id", eventID))
+	return nil
+}
+
+// findEvent ищет событие чата по команде, а если такой нет - по отображаемому имени
+// или его транслитерации: "/delete день_рождения" находит событие "День рождения"
+func findEvent(store storage.Storage, chatID int64, name string) (*models.Event, error) {
+	event, err := store.GetEvent(chatID, name)
+	if err == nil {
+		return event, nil
+	}
+	events, listErr := store.GetEvents(chatID)
+	if listErr != nil {
+		return nil, err
+	}
+	if models.IsValidEventName(name) {
+		// Команда в другом регистре: точное совпадение уже проверено выше
+		for i := range events {
+			if events[i].HasCommandFold(name) {
+				return &events[i], nil
+			}
+		}
+		return nil, err
+	}
+	slug := models.EventSlug(name)
+	var bySlug *models.Event
+	for i := range events {
+		if strings.EqualFold(events[i].DisplayName, name) {
+			return &events[i], nil
+		}
+		if bySlug == nil && events[i].Name == slug {
+			bySlug = &events[i]
+		}
+	}
+	if bySlug != nil {
+		return bySlug, nil
+	}
+	return nil, err
+}
+
+// modifyEvent загружает событие по имени, применяет изменение и сохраняет результат
+func (s *EventService) modifyEvent(chatID int64, name string, modify func(event *models.Event) error) error {
+	s.logger.Info("Изменение события",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_name", name))
+	event, err := findEvent(s.store, chatID, name)
+	if err != nil {
+		s.logger.Warn("Событие не найдено",
+			zap.Int64("chat_id", chatID),
+			zap.String("event_name", name),
+			zap.Error(err))
+		return err
+	}
+	if err := modify(event); err != nil {
+		return err
+	}
+	if err := s.store.UpdateEvent(chatID, *event); err != nil {
+		s.logger.Error("Ошибка сохранения изменённого события", zap.Error(err))
+		return err
+	}
+	s.logger.Info("Событие изменено",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_name", event.Name))
+	return nil
+}
+
+// SetEventVisibility задаёт, кому событие видно в других чатах; sharedWith учитывается
+// только для VisibilityShared. Права проверяет вызывающий код (PermissionService.CanEdit).
+func (s *EventService) SetEventVisibility(chatID int64, name string, visibility models.EventVisibility, sharedWith []int64) error {
+	if visibility == models.VisibilityShared && len(sharedWith) == 0 {
+		return errors.New("no chats to share with")
+	}
+	if visibility != models.VisibilityShared {
+		sharedWith = nil
+	}
+	return s.modifyEvent(chatID, name, func(event *models.Event) error {
+		event.Visibility = visibility
+		event.SharedWith = sharedWith
+		return nil
+	})
+}
+
+// SetEventReminders задаёт смещения напоминаний события. nil возвращает настройки
+// по умолчанию, пустой список отключает напоминания.
+func (s *EventService) SetEventReminders(chatID int64, name string, reminders []string) error {
+	if reminders != nil {
+		normalized, err := models.NormalizeReminders(reminders)
+		if err != nil {
+			s.logger.Warn("Некорректные напоминания", zap.Strings("reminders", reminders), zap.Error(err))
+			return err
+		}
+		reminders = normalized
+	}
+	return s.modifyEvent(chatID, name, func(event *models.Event) error {
+		event.Reminders = reminders
+		return nil
+	})
+}
+
+// SetEventCountUp включает (countUp) или выключает для события счёт прошедшего времени;
+// announce включает объявления о круглых датах (models.Milestone) в чате события.
+// Повторяющееся событие после даты переходит к следующему повторению, поэтому для него
+// счёт не ведётся.
+func (s *EventService) SetEventCountUp(chatID int64, name string, countUp, announce bool) (*models.Event, error) {
+	var updated models.Event
+	err := s.modifyEvent(chatID, name, func(event *models.Event) error {
+		if countUp && event.IsRecurring() {
+			return ErrRecurringCountUp
+		}
+		event.Mode = ""
+		if countUp {
+			event.Mode = models.ModeCountUp
+		}
+		event.AnnounceMilestones = countUp && announce
+		updated = *event
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &updated, nil
+}
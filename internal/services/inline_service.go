@@ -0,0 +1,214 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"go.uber.org/zap"
+)
+
+// DefaultInlineCacheTTL - сколько хранится результат inline-запроса пользователя
+const DefaultInlineCacheTTL = 30 * time.Second
+
+// DefaultMembershipCacheTTL - сколько хранится проверка членства пользователя в чате
+const DefaultMembershipCacheTTL = 10 * time.Minute
+
+// MaxInlineResults - ограничение Telegram на число результатов одного inline-ответа
+const MaxInlineResults = 50
+
+// MemberCheckFunc сообщает, состоит ли пользователь в чате сейчас (getChatMember)
+type MemberCheckFunc func(ctx context.Context, chatID, userID int64) (bool, error)
+
+// InlineOptions - настройки InlineService
+type InlineOptions struct {
+	// IsMember проверяет членство в группах; nil - доверять сохранённым данным об участниках
+	IsMember MemberCheckFunc
+	// CacheTTL - время жизни результатов поиска; 0 означает DefaultInlineCacheTTL
+	CacheTTL time.Duration
+	// MembershipTTL - время жизни проверки членства; 0 означает DefaultMembershipCacheTTL
+	MembershipTTL time.Duration
+}
+
+type inlineCacheKey struct {
+	userID int64
+	query  string
+}
+
+type inlineCacheEntry struct {
+	events    []models.Event
+	expiresAt time.Time
+}
+
+type membershipEntry struct {
+	member    bool
+	expiresAt time.Time
+}
+
+// InlineService ищет события для inline-запросов @bot. Пользователю видны события тех
+// чатов, где бот его видел и где он состоит сейчас, вместе с их подписками, а также
+// события личного чата с ботом.
+type InlineService struct {
+	events        *EventService
+	users         *UserService
+	isMember      MemberCheckFunc
+	cacheTTL      time.Duration
+	membershipTTL time.Duration
+
+	mu          sync.Mutex
+	results     map[inlineCacheKey]inlineCacheEntry
+	memberships map[[2]int64]membershipEntry
+
+	logger *zap.Logger
+}
+
+func NewInlineService(events *EventService, users *UserService, options InlineOptions) *InlineService {
+	cacheTTL := options.CacheTTL
+	if cacheTTL <= 0 {
+		cacheTTL = DefaultInlineCacheTTL
+	}
+	membershipTTL := options.MembershipTTL
+	if membershipTTL <= 0 {
+		membershipTTL = DefaultMembershipCacheTTL
+	}
+	return &InlineService{
+		events:        events,
+		users:         users,
+		isMember:      options.IsMember,
+		cacheTTL:      cacheTTL,
+		membershipTTL: membershipTTL,
+		results:       make(map[inlineCacheKey]inlineCacheEntry),
+		memberships:   make(map[[2]int64]membershipEntry),
+		logger:        zap.L(),
+	}
+}
+
+// Search возвращает видимые пользователю события, имя или команда которых содержит query (без учёта
+// регистра; пустой запрос - все события). Сначала идут ближайшие события, затем прошедшие.
+func (s *InlineService) Search(ctx context.Context, userID int64, query string, now time.Time) ([]models.Event, error) {
+	query = strings.ToLower(strings.TrimSpace(query))
+	key := inlineCacheKey{userID: userID, query: query}
+	s.mu.Lock()
+	entry, cached := s.results[key]
+	s.mu.Unlock()
+	if cached && now.Before(entry.expiresAt) {
+		return entry.events, nil
+	}
+
+	chatIDs, err := s.visibleChats(ctx, userID, now)
+	if err != nil {
+		return nil, err
+	}
+	type match struct {
+		event      models.Event
+		occurrence time.Time
+		upcoming   bool
+	}
+	var matches []match
+	seen := make(map[string]bool)
+	for _, chatID := range chatIDs {
+		events, err := s.events.ListVisibleEvents(chatID)
+		if err != nil {
+			continue
+		}
+		for _, event := range events {
+			if seen[event.EventID] || !matchesInlineQuery(event, query) {
+				continue
+			}
+			seen[event.EventID] = true
+			occurrence, upcoming, err := event.NextOccurrence(now)
+			if err != nil {
+				continue
+			}
+			matches = append(matches, match{event: event, occurrence: occurrence, upcoming: upcoming})
+		}
+	}
+	sort.SliceStable(matches, func(i, j int) bool {
+		if matches[i].upcoming != matches[j].upcoming {
+			return matches[i].upcoming
+		}
+		if matches[i].upcoming {
+			return matches[i].occurrence.Before(matches[j].occurrence)
+		}
+		return matches[i].occurrence.After(matches[j].occurrence)
+	})
+	if len(matches) > MaxInlineResults {
+		matches = matches[:MaxInlineResults]
+	}
+
+	events := make([]models.Event, len(matches))
+	for i, m := range matches {
+		events[i] = m.event
+	}
+	s.mu.Lock()
+	s.pruneLocked(now)
+	s.results[key] = inlineCacheEntry{events: events, expiresAt: now.Add(s.cacheTTL)}
+	s.mu.Unlock()
+	s.logger.Debug("Inline-поиск событий",
+		zap.Int64("user_id", userID),
+		zap.String("query", query),
+		zap.Int("chats", len(chatIDs)),
+		zap.Int("results", len(events)))
+	return events, nil
+}
+
+// matchesInlineQuery ищет запрос в отображаемом имени события и в его команде
+func matchesInlineQuery(event models.Event, query string) bool {
+	return strings.Contains(strings.ToLower(event.Title()), query) ||
+		strings.Contains(strings.ToLower(event.Name), query)
+}
+
+// visibleChats возвращает личный чат пользователя и группы, где он состоит
+func (s *InlineService) visibleChats(ctx context.Context, userID int64, now time.Time) ([]int64, error) {
+	known, err := s.users.ChatIDs(userID)
+	if err != nil {
+		return nil, err
+	}
+	chatIDs := []int64{userID}
+	for _, chatID := range known {
+		if chatID != userID && s.member(ctx, chatID, userID, now) {
+			chatIDs = append(chatIDs, chatID)
+		}
+	}
+	return chatIDs, nil
+}
+
+// member проверяет, что пользователь всё ещё состоит в чате. При ошибке проверки
+// события чата не показываются.
+func (s *InlineService) member(ctx context.Context, chatID, userID int64, now time.Time) bool {
+	if s.isMember == nil {
+		return true
+	}
+	key := [2]int64{chatID, userID}
+	s.mu.Lock()
+	entry, cached := s.memberships[key]
+	s.mu.Unlock()
+	if cached && now.Before(entry.expiresAt) {
+		return entry.member
+	}
+
+	member, err := s.isMember(ctx, chatID, userID)
+	if err != nil {
+		s.logger.Warn("Не удалось проверить участника чата",
+			zap.Int64("chat_id", chatID),
+			zap.Int64("user_id", userID),
+			zap.Error(err))
+		return false
+	}
+	s.mu.Lock()
+	s.memberships[key] = membershipEntry{member: member, expiresAt: now.Add(s.membershipTTL)}
+	s.mu.Unlock()
+	return member
+}
+
+// pruneLocked удаляет устаревшие результаты; вызывается под s.mu
+func (s *InlineService) pruneLocked(now time.Time) {
+	for key, entry := range s.results {
+		if !now.Before(entry.expiresAt) {
+			delete(s.results, key)
+		}
+	}
+}
@@ -0,0 +1,191 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"go.uber.org/zap"
+)
+
+// ErrPermissionDenied возвращается, когда политика чата не разрешает пользователю действие
+var ErrPermissionDenied = errors.New("permission denied")
+
+// DefaultAdminCacheTTL - сколько хранится список администраторов чата, если в PermissionOptions не задано
+const DefaultAdminCacheTTL = 5 * time.Minute
+
+// ChatAdminsFunc возвращает Telegram ID администраторов чата (getChatAdministrators)
+type ChatAdminsFunc func(ctx context.Context, chatID int64) ([]int64, error)
+
+// PermissionOptions - настройки PermissionService
+type PermissionOptions struct {
+	// SuperAdmins - администраторы бота, которым разрешено всё в любом чате
+	SuperAdmins []int64
+	// ChatAdmins загружает администраторов чата; nil - в группах администраторов нет
+	ChatAdmins ChatAdminsFunc
+	// AdminCacheTTL - время жизни кэша администраторов; 0 означает DefaultAdminCacheTTL
+	AdminCacheTTL time.Duration
+}
+
+type adminCacheEntry struct {
+	admins    map[int64]bool
+	fetchedAt time.Time
+}
+
+// PermissionService решает, кто может создавать, изменять и удалять события чата.
+// Роли: супер-администраторы бота, администраторы чата, создатель события (его
+// события хранятся в models.User) и остальные участники. Что разрешено участникам,
+// задают политики чата models.ChatSettings.
+type PermissionService struct {
+	store       storage.Storage
+	superAdmins map[int64]bool
+	chatAdmins  ChatAdminsFunc
+	ttl         time.Duration
+	now         func() time.Time
+
+	mu    sync.Mutex
+	cache map[int64]adminCacheEntry
+
+	logger *zap.Logger
+}
+
+func NewPermissionService(store storage.Storage, options PermissionOptions) *PermissionService {
+	ttl := options.AdminCacheTTL
+	if ttl <= 0 {
+		ttl = DefaultAdminCacheTTL
+	}
+	superAdmins := make(map[int64]bool, len(options.SuperAdmins))
+	for _, id := range options.SuperAdmins {
+		superAdmins[id] = true
+	}
+	return &PermissionService{
+		store:       store,
+		superAdmins: superAdmins,
+		chatAdmins:  options.ChatAdmins,
+		ttl:         ttl,
+		now:         time.Now,
+		cache:       make(map[int64]adminCacheEntry),
+		logger:      zap.L(),
+	}
+}
+
+// IsSuperAdmin сообщает, является ли пользователь администратором бота
+func (s *PermissionService) IsSuperAdmin(userID int64) bool {
+	return s.superAdmins[userID]
+}
+
+// IsChatAdmin сообщает, может ли пользователь управлять чатом: администратор бота,
+// администратор группы или собеседник в личном чате (ID личного чата совпадает с ID пользователя)
+func (s *PermissionService) IsChatAdmin(ctx context.Context, chatID, userID int64) (bool, error) {
+	if s.IsSuperAdmin(userID) || chatID == userID {
+		return true, nil
+	}
+	admins, err := s.admins(ctx, chatID)
+	if err != nil {
+		return false, err
+	}
+	return admins[userID], nil
+}
+
+// CanCreate проверяет, может ли пользователь создавать события в чате
+func (s *PermissionService) CanCreate(ctx context.Context, chatID, userID int64) error {
+	settings, err := s.store.GetChatSettings(chatID)
+	if err != nil {
+		return err
+	}
+	return s.check(ctx, chatID, userID, settings.EffectiveCreatePolicy(), "", "create")
+}
+
+// CanEdit проверяет, может ли пользователь изменить или удалить событие чата
+func (s *PermissionService) CanEdit(ctx context.Context, chatID, userID int64, eventID string) error {
+	settings, err := s.store.GetChatSettings(chatID)
+	if err != nil {
+		return err
+	}
+	return s.check(ctx, chatID, userID, settings.EffectiveEditPolicy(), eventID, "edit")
+}
+
+// CanManageChat проверяет, может ли пользователь менять настройки прав чата
+func (s *PermissionService) CanManageChat(ctx context.Context, chatID, userID int64) error {
+	return s.check(ctx, chatID, userID, models.PolicyAdmins, "", "manage")
+}
+
+// IsOwner сообщает, создал ли пользователь событие чата
+func (s *PermissionService) IsOwner(chatID, userID int64, eventID string) bool {
+	user, err := s.store.GetUser(chatID, userID)
+	if err != nil {
+		return false
+	}
+	for _, event := range user.Events {
+		if event.EventID == eventID {
+			return true
+		}
+	}
+	return false
+}
+
+// InvalidateChat сбрасывает кэш администраторов чата (например, после смены администраторов)
+func (s *PermissionService) InvalidateChat(chatID int64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.cache, chatID)
+}
+
+func (s *PermissionService) check(ctx context.Context, chatID, userID int64, policy models.PermissionPolicy, eventID, action string) error {
+	if policy == models.PolicyEveryone {
+		return nil
+	}
+	if policy == models.PolicyOwners && eventID != "" && s.IsOwner(chatID, userID, eventID) {
+		return nil
+	}
+	admin, err := s.IsChatAdmin(ctx, chatID, userID)
+	if err != nil {
+		return err
+	}
+	if admin {
+		return nil
+	}
+	s.logger.Info("Действие запрещено политикой чата",
+		zap.Int64("chat_id", chatID),
+		zap.Int64("user_id", userID),
+		zap.String("action", action),
+		zap.String("policy", string(policy)))
+	return ErrPermissionDenied
+}
+
+// admins возвращает администраторов чата из кэша или загружает их из Telegram.
+// Если загрузка не удалась, используется устаревший список, если он есть.
+func (s *PermissionService) admins(ctx context.Context, chatID int64) (map[int64]bool, error) {
+	if s.chatAdmins == nil {
+		return nil, nil
+	}
+	now := s.now()
+	s.mu.Lock()
+	entry, cached := s.cache[chatID]
+	s.mu.Unlock()
+	if cached && now.Sub(entry.fetchedAt) < s.ttl {
+		return entry.admins, nil
+	}
+
+	ids, err := s.chatAdmins(ctx, chatID)
+	if err != nil {
+		if cached {
+			s.logger.Warn("Не удалось обновить администраторов чата, используется кэш",
+				zap.Int64("chat_id", chatID), zap.Error(err))
+			return entry.admins, nil
+		}
+		s.logger.Error("Ошибка получения администраторов чата", zap.Int64("chat_id", chatID), zap.Error(err))
+		return nil, err
+	}
+	admins := make(map[int64]bool, len(ids))
+	for _, id := range ids {
+		admins[id] = true
+	}
+	s.mu.Lock()
+	s.cache[chatID] = adminCacheEntry{admins: admins, fetchedAt: now}
+	s.mu.Unlock()
+	return admins, nil
+}
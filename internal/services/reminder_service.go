@@ -0,0 +1,56 @@
+package services
+
+import (
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"go.uber.org/zap"
+)
+
+// ReminderService ведёт журнал отправленных напоминаний
+type ReminderService struct {
+	store  storage.Storage
+	logger *zap.Logger
+}
+
+func NewReminderService(store storage.Storage) *ReminderService {
+	logger := zap.L()
+	return &ReminderService{
+		store:  store,
+		logger: logger,
+	}
+}
+
+// Delivered возвращает множество уже отправленных напоминаний события
+// с ключами вида "<occurrence>|<offset>"
+func (s *ReminderService) Delivered(chatID int64, eventID string) (map[string]bool, error) {
+	deliveries, err := s.store.GetReminderDeliveries(chatID, eventID)
+	if err != nil {
+		s.logger.Error("Ошибка получения отправленных напоминаний",
+			zap.Int64("chat_id", chatID),
+			zap.String("event_id", eventID),
+			zap.Error(err))
+		return nil, err
+	}
+	delivered := make(map[string]bool, len(deliveries))
+	for _, delivery := range deliveries {
+		delivered[DeliveryKey(delivery.Occurrence, delivery.Offset)] = true
+	}
+	return delivered, nil
+}
+
+// MarkDelivered сохраняет факт отправки напоминания
+func (s *ReminderService) MarkDelivered(chatID int64, delivery models.ReminderDelivery) error {
+	err := s.store.SaveReminderDelivery(chatID, delivery)
+	if err != nil {
+		s.logger.Error("Ошибка сохранения отправленного напоминания",
+			zap.Int64("chat_id", chatID),
+			zap.String("event_id", delivery.EventID),
+			zap.Error(err))
+	}
+	return err
+}
+
+// DeliveryKey формирует ключ напоминания для результата Delivered
+func DeliveryKey(occurrence, offset string) string {
+	return occurrence + "|" + offset
+}
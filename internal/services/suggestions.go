@@ -0,0 +1,148 @@
+package services
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"go.uber.org/zap"
+)
+
+// MaxSuggestions - сколько похожих событий предлагается вместо ненайденного
+const MaxSuggestions = 3
+
+// minPrefixLength - с какой длины набранная команда может быть началом команды события
+const minPrefixLength = 3
+
+// Suggestion - событие чата, похожее на ненайденную команду
+type Suggestion struct {
+	Event models.Event
+	// Command - имя или псевдоним события, ближайший к набранной команде
+	Command string
+	// Distance - число правок до команды; у совпадения по началу команды оно не больше 1
+	Distance int
+}
+
+// suggestionKey приводит команду к виду для сравнения: строчные латинские буквы и цифры.
+// Русские буквы транслитерируются, поэтому /новый_год похож на /novyy_god, а /newyear - на /new_year.
+func suggestionKey(name string) string {
+	name = strings.ToLower(name)
+	if !models.IsValidEventName(name) {
+		name = models.EventSlug(name)
+	}
+	return strings.ReplaceAll(name, "_", "")
+}
+
+// SuggestEvents ищет среди имён и псевдонимов событий чата до limit команд, похожих на input:
+// совпадающих без учёта регистра, "_" и раскладки (транслитерация), начинающихся с input или
+// отличающихся от него несколькими опечатками. Ближайшие совпадения идут первыми.
+func (s *EventService) SuggestEvents(chatID int64, input string, limit int) ([]Suggestion, error) {
+	query := suggestionKey(input)
+	if query == "" || limit <= 0 {
+		return nil, nil
+	}
+	events, err := s.store.GetEvents(chatID)
+	if err != nil {
+		s.logger.Error("Ошибка получения событий для подсказок", zap.Int64("chat_id", chatID), zap.Error(err))
+		return nil, err
+	}
+
+	// lengthDiff различает равноудалённые команды: ближе та, что по длине похожа на набранную
+	type candidate struct {
+		Suggestion
+		lengthDiff int
+	}
+	maxDistance := suggestionDistance(query)
+	var candidates []candidate
+	for _, event := range events {
+		best := candidate{Suggestion: Suggestion{Distance: -1}}
+		consider := func(command, key string) {
+			distance := editDistance(query, key)
+			if len(query) >= minPrefixLength && strings.HasPrefix(key, query) && distance > 1 {
+				distance = 1
+			}
+			if distance > maxDistance {
+				return
+			}
+			lengthDiff := len(key) - len(query)
+			if lengthDiff < 0 {
+				lengthDiff = -lengthDiff
+			}
+			if best.Distance < 0 || distance < best.Distance || (distance == best.Distance && lengthDiff < best.lengthDiff) {
+				best = candidate{Suggestion: Suggestion{Event: event, Command: command, Distance: distance}, lengthDiff: lengthDiff}
+			}
+		}
+		consider(event.Name, suggestionKey(event.Name))
+		if event.DisplayName != "" {
+			consider(event.Name, suggestionKey(event.DisplayName))
+		}
+		for _, alias := range event.Aliases {
+			consider(alias, suggestionKey(alias))
+		}
+		if best.Distance >= 0 {
+			candidates = append(candidates, best)
+		}
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		if candidates[i].Distance != candidates[j].Distance {
+			return candidates[i].Distance < candidates[j].Distance
+		}
+		if candidates[i].lengthDiff != candidates[j].lengthDiff {
+			return candidates[i].lengthDiff < candidates[j].lengthDiff
+		}
+		return candidates[i].Command < candidates[j].Command
+	})
+	if len(candidates) > limit {
+		candidates = candidates[:limit]
+	}
+	suggestions := make([]Suggestion, len(candidates))
+	for i, c := range candidates {
+		suggestions[i] = c.Suggestion
+	}
+	s.logger.Debug("Подсказки для ненайденного события",
+		zap.Int64("chat_id", chatID),
+		zap.String("event_name", input),
+		zap.Int("suggestions", len(suggestions)))
+	return suggestions, nil
+}
+
+// suggestionDistance - сколько опечаток допускается в команде такой длины
+func suggestionDistance(query string) int {
+	switch n := len(query); {
+	case n <= 4:
+		return 1
+	case n <= 8:
+		return 2
+	default:
+		return 3
+	}
+}
+
+// editDistance - расстояние Дамерау-Левенштейна (вариант с ограниченными перестановками):
+// вставка, удаление, замена символа и перестановка соседних символов считаются одной правкой
+func editDistance(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	// Три строки матрицы: перестановке нужна строка на две позиции выше
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	cur := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		cur[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				cur[j] = min(cur[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, cur = prev, cur, prev2
+	}
+	return prev[len(rb)]
+}
@@ -1,18 +1,22 @@
 package services
 
 import (
+	"sync"
+
 	"github.com/TheReshkin/tg-bot-family/internal/models"
 	"github.com/TheReshkin/tg-bot-family/internal/storage"
 	"go.uber.org/zap"
 )
 
 type UserService struct {
-	store  storage.Storage
-	logger *zap.Logger
+	store storage.Storage
+	// members - пары чат/пользователь, уже записанные RememberMember за время работы
+	members sync.Map
+	logger  *zap.Logger
 }
 
 func NewUserService(store storage.Storage) *UserService {
-	logger, _ := zap.NewProduction()
+	logger := zap.L()
 	return &UserService{
 		store:  store,
 		logger: logger,
@@ -49,3 +53,30 @@ func (s *UserService) AddEventToUser(chatID, userID int64, event models.Event) e
 		zap.String("event_name", event.Name))
 	return nil
 }
+
+// RememberMember запоминает, что пользователь состоит в чате: по этим данным inline-запросы
+// находят чаты пользователя. Уже записанные пары повторно в хранилище не пишутся.
+func (s *UserService) RememberMember(chatID, userID int64) error {
+	key := [2]int64{chatID, userID}
+	if _, known := s.members.Load(key); known {
+		return nil
+	}
+	if err := s.store.AddUser(chatID, userID); err != nil {
+		s.logger.Error("Ошибка сохранения участника чата",
+			zap.Int64("chat_id", chatID),
+			zap.Int64("user_id", userID),
+			zap.Error(err))
+		return err
+	}
+	s.members.Store(key, true)
+	return nil
+}
+
+// ChatIDs возвращает чаты, в которых бот видел пользователя
+func (s *UserService) ChatIDs(userID int64) ([]int64, error) {
+	chatIDs, err := s.store.GetUserChatIDs(userID)
+	if err != nil {
+		s.logger.Error("Ошибка получения чатов пользователя", zap.Int64("user_id", userID), zap.Error(err))
+	}
+	return chatIDs, err
+}
@@ -0,0 +1,166 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"os"
+	"time"
+)
+
+// ImportResult описывает итог переноса events.json в SQL-хранилище
+type ImportResult struct {
+	Chats    int
+	Events   int
+	Users    int
+	Catalogs int
+	Skipped  int
+}
+
+// ImportJSON переносит данные из файла JSONStorage в базу одной транзакцией.
+// Повторный запуск безопасен: события сопоставляются по EventID, а события
+// с уже занятым в чате именем пропускаются и учитываются в Skipped.
+func (s *sqlStorage) ImportJSON(path string) (ImportResult, error) {
+	var result ImportResult
+
+	file, err := os.Open(path)
+	if err != nil {
+		return result, err
+	}
+	defer file.Close()
+
+	var data []ChatData
+	if err := json.NewDecoder(file).Decode(&data); err != nil {
+		return result, err
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return result, err
+	}
+	defer tx.Rollback()
+
+	for _, chat := range data {
+		if _, err := tx.ExecContext(ctx, saveChatSettingsQuery, append([]any{chat.ChatID}, chatSettingsValues(chat.Settings)...)...); err != nil {
+			return result, err
+		}
+		result.Chats++
+
+		imported := make(map[string]bool)
+		for _, event := range chat.Events {
+			// Старый UpdateEventStatus дописывал копию события с тем же EventID,
+			// поэтому более поздняя запись просто обновляет предыдущую.
+			var existingID string
+			err := tx.QueryRowContext(ctx, `SELECT event_id FROM events WHERE chat_id = $1 AND name = $2`,
+				chat.ChatID, event.Name).Scan(&existingID)
+			if err != nil && err != sql.ErrNoRows {
+				return result, err
+			}
+			if err == nil && existingID != event.EventID {
+				result.Skipped++
+				continue
+			}
+
+			args := append([]any{event.EventID, chat.ChatID}, eventWriteValues(event)...)
+			_, err = tx.ExecContext(ctx, upsertEventQuery, args...)
+			if err != nil {
+				return result, err
+			}
+			if !imported[event.EventID] {
+				imported[event.EventID] = true
+				result.Events++
+			}
+		}
+
+		for _, user := range chat.Users {
+			_, err := tx.ExecContext(ctx, `INSERT INTO users (chat_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`,
+				chat.ChatID, user.UserID)
+			if err != nil {
+				return result, err
+			}
+			result.Users++
+
+			for _, event := range user.Events {
+				var eventExists, linked bool
+				err := tx.QueryRowContext(ctx, `SELECT
+					EXISTS (SELECT 1 FROM events WHERE event_id = $1),
+					EXISTS (SELECT 1 FROM user_events WHERE chat_id = $2 AND user_id = $3 AND event_id = $1)`,
+					event.EventID, chat.ChatID, user.UserID).Scan(&eventExists, &linked)
+				if err != nil {
+					return result, err
+				}
+				if !eventExists || linked {
+					continue
+				}
+				_, err = tx.ExecContext(ctx, `INSERT INTO user_events (chat_id, user_id, event_id) VALUES ($1, $2, $3)`,
+					chat.ChatID, user.UserID, event.EventID)
+				if err != nil {
+					return result, err
+				}
+			}
+		}
+
+		for _, delivery := range chat.ReminderDeliveries {
+			if !imported[delivery.EventID] {
+				continue
+			}
+			_, err := tx.ExecContext(ctx, `INSERT INTO reminder_deliveries (chat_id, event_id, occurrence, reminder_offset, sent_at)
+				VALUES ($1, $2, $3, $4, $5)
+				ON CONFLICT (event_id, occurrence, reminder_offset) DO NOTHING`,
+				chat.ChatID, delivery.EventID, delivery.Occurrence, delivery.Offset, delivery.SentAt.UTC())
+			if err != nil {
+				return result, err
+			}
+		}
+
+		if countdown := chat.Countdown; countdown != nil {
+			_, err := tx.ExecContext(ctx, saveCountdownQuery, chat.ChatID, countdown.MessageID, countdown.Size,
+				countdown.Text, countdown.UpdatedAt.UTC())
+			if err != nil {
+				return result, err
+			}
+		}
+
+		for _, catalog := range chat.Catalogs {
+			_, err := tx.ExecContext(ctx, `INSERT INTO catalogs (name, owner_chat_id) VALUES ($1, $2)
+				ON CONFLICT (name) DO NOTHING`, catalog.Name, chat.ChatID)
+			if err != nil {
+				return result, err
+			}
+			result.Catalogs++
+			for _, eventID := range catalog.EventIDs {
+				if !imported[eventID] {
+					continue
+				}
+				_, err := tx.ExecContext(ctx, `INSERT INTO catalog_events (catalog, event_id) VALUES ($1, $2)
+					ON CONFLICT (catalog, event_id) DO NOTHING`, catalog.Name, eventID)
+				if err != nil {
+					return result, err
+				}
+			}
+		}
+	}
+
+	// Подписки переносятся после всех каталогов: каталог может принадлежать чату, идущему позже
+	for _, chat := range data {
+		for _, catalog := range chat.Subscriptions {
+			exists, err := catalogExists(ctx, tx, catalog)
+			if err != nil {
+				return result, err
+			}
+			if !exists {
+				continue
+			}
+			_, err = tx.ExecContext(ctx, `INSERT INTO catalog_subscriptions (chat_id, catalog) VALUES ($1, $2)
+				ON CONFLICT (chat_id, catalog) DO NOTHING`, chat.ChatID, catalog)
+			if err != nil {
+				return result, err
+			}
+		}
+	}
+
+	return result, tx.Commit()
+}
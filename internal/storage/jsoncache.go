@@ -0,0 +1,202 @@
+package storage
+
+import (
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"go.uber.org/zap"
+)
+
+// eventKey адресует событие чата по имени или по EventID
+type eventKey struct {
+	chatID int64
+	key    string
+}
+
+// jsonIndex ускоряет поиск по данным JSONStorage в памяти. Значения - позиции в срезах
+// ChatData, поэтому индекс перестраивается после каждого изменения данных.
+type jsonIndex struct {
+	chats   map[int64]int
+	byName  map[eventKey]int
+	byAlias map[eventKey]int
+	byID    map[eventKey]int
+	// nameToChat - чаты, где есть событие с таким именем или псевдонимом
+	nameToChat map[string][]int64
+	// catalogs - позиция каталога: индекс чата-владельца и индекс в его ChatData.Catalogs
+	catalogs map[string][2]int
+}
+
+func buildIndex(data []ChatData) jsonIndex {
+	index := jsonIndex{
+		chats:      make(map[int64]int, len(data)),
+		byName:     make(map[eventKey]int),
+		byAlias:    make(map[eventKey]int),
+		byID:       make(map[eventKey]int),
+		nameToChat: make(map[string][]int64),
+		catalogs:   make(map[string][2]int),
+	}
+	for i, chat := range data {
+		index.chats[chat.ChatID] = i
+		for j, event := range chat.Events {
+			nameKey := eventKey{chatID: chat.ChatID, key: event.Name}
+			if _, ok := index.byName[nameKey]; !ok {
+				index.nameToChat[event.Name] = append(index.nameToChat[event.Name], chat.ChatID)
+			}
+			index.byName[nameKey] = j
+			index.byID[eventKey{chatID: chat.ChatID, key: event.EventID}] = j
+		}
+		for j, event := range chat.Events {
+			for _, alias := range event.Aliases {
+				aliasKey := eventKey{chatID: chat.ChatID, key: alias}
+				if _, ok := index.byAlias[aliasKey]; ok {
+					continue
+				}
+				if _, ok := index.byName[aliasKey]; !ok {
+					index.nameToChat[alias] = append(index.nameToChat[alias], chat.ChatID)
+				}
+				index.byAlias[aliasKey] = j
+			}
+		}
+		for j, catalog := range chat.Catalogs {
+			index.catalogs[catalog.Name] = [2]int{i, j}
+		}
+	}
+	return index
+}
+
+// chat возвращает данные чата или nil, если чат неизвестен
+func (s *JSONStorage) chat(chatID int64) *ChatData {
+	if i, ok := s.index.chats[chatID]; ok {
+		return &s.data[i]
+	}
+	return nil
+}
+
+// eventByName возвращает событие чата по имени
+func (s *JSONStorage) eventByName(chatID int64, name string) (models.Event, bool) {
+	chat := s.chat(chatID)
+	if chat == nil {
+		return models.Event{}, false
+	}
+	j, ok := s.index.byName[eventKey{chatID: chatID, key: name}]
+	if !ok {
+		return models.Event{}, false
+	}
+	return chat.Events[j], true
+}
+
+// eventByCommand возвращает событие чата по имени, а если такого нет - по псевдониму
+func (s *JSONStorage) eventByCommand(chatID int64, name string) (models.Event, bool) {
+	if event, ok := s.eventByName(chatID, name); ok {
+		return event, true
+	}
+	chat := s.chat(chatID)
+	if chat == nil {
+		return models.Event{}, false
+	}
+	j, ok := s.index.byAlias[eventKey{chatID: chatID, key: name}]
+	if !ok {
+		return models.Event{}, false
+	}
+	return chat.Events[j], true
+}
+
+// catalog возвращает каталог по имени или nil, если каталога нет
+func (s *JSONStorage) catalog(name string) *models.Catalog {
+	if pos, ok := s.index.catalogs[name]; ok {
+		return &s.data[pos[0]].Catalogs[pos[1]]
+	}
+	return nil
+}
+
+// load читает events.json в память при запуске. Ошибка чтения запоминается и возвращается
+// всеми операциями, чтобы не перезаписать повреждённый файл пустыми данными.
+func (s *JSONStorage) load() {
+	data, err := s.readFile()
+	if err != nil {
+		s.loadErr = err
+		s.logger.Error("Ошибка загрузки файла данных", zap.String("path", s.path), zap.Error(err))
+		return
+	}
+	s.data = data
+	s.index = buildIndex(data)
+}
+
+// cloneData возвращает копию данных для изменения. Изменённая копия становится текущими
+// данными только после успешного saveData, поэтому неудачная запись не оставляет
+// в памяти состояние, которого нет на диске.
+func (s *JSONStorage) cloneData() ([]ChatData, error) {
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	data := make([]ChatData, len(s.data))
+	for i, chat := range s.data {
+		data[i] = chat
+		data[i].Events = append([]models.Event(nil), chat.Events...)
+		data[i].Users = make([]models.User, len(chat.Users))
+		for j, user := range chat.Users {
+			data[i].Users[j] = user
+			data[i].Users[j].Events = append([]models.Event(nil), user.Events...)
+		}
+		data[i].ReminderDeliveries = append([]models.ReminderDelivery(nil), chat.ReminderDeliveries...)
+		data[i].Conversations = append([]models.Conversation(nil), chat.Conversations...)
+		data[i].Catalogs = make([]models.Catalog, len(chat.Catalogs))
+		for j, catalog := range chat.Catalogs {
+			data[i].Catalogs[j] = catalog
+			data[i].Catalogs[j].EventIDs = append([]string(nil), catalog.EventIDs...)
+		}
+		data[i].Subscriptions = append([]string(nil), chat.Subscriptions...)
+		if chat.Countdown != nil {
+			countdown := *chat.Countdown
+			data[i].Countdown = &countdown
+		}
+	}
+	return data, nil
+}
+
+// saveData делает data текущими данными и сохраняет их на диск: сразу или, если задан
+// flushDelay, одной записью через flushDelay после первого изменения
+func (s *JSONStorage) saveData(data []ChatData) error {
+	if s.closed {
+		return ErrStorageClosed
+	}
+	if s.flushDelay <= 0 {
+		if err := s.writeData(data); err != nil {
+			return err
+		}
+	} else {
+		s.dirty = true
+		if s.flushTimer == nil {
+			s.flushTimer = time.AfterFunc(s.flushDelay, s.flushPending)
+		}
+	}
+	s.data = data
+	s.index = buildIndex(data)
+	return nil
+}
+
+// flushPending записывает отложенные изменения
+func (s *JSONStorage) flushPending() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.flushTimer = nil
+	if err := s.flush(); err != nil {
+		s.logger.Error("Ошибка отложенной записи файла данных", zap.String("path", s.path), zap.Error(err))
+	}
+}
+
+// flush записывает данные, если есть несохранённые изменения; вызывается под s.mu
+func (s *JSONStorage) flush() error {
+	if !s.dirty {
+		return nil
+	}
+	if err := s.writeData(s.data); err != nil {
+		// Изменения остаются в памяти, следующая запись повторит попытку
+		if s.flushTimer == nil && !s.closed {
+			s.flushTimer = time.AfterFunc(s.flushDelay, s.flushPending)
+		}
+		return err
+	}
+	s.dirty = false
+	return nil
+}
@@ -0,0 +1,197 @@
+package storage
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	backupPrefix = "events-"
+	backupSuffix = ".json"
+	// backupTimeFormat сортируется лексикографически в хронологическом порядке
+	backupTimeFormat = "20060102-150405.000000000"
+)
+
+// writeFileAtomic записывает data во временный файл рядом с path, сбрасывает его на диск
+// и переименовывает поверх path. При сбое или нехватке места прежний файл остаётся целым.
+func writeFileAtomic(path string, data []byte) error {
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	tmpName := tmp.Name()
+	defer os.Remove(tmpName) // после успешного переименования файла уже нет
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpName, path); err != nil {
+		return err
+	}
+	syncDir(dir)
+	return nil
+}
+
+// syncDir сбрасывает на диск запись каталога, чтобы переименование пережило отключение питания.
+// Не на всех платформах каталог можно открыть для Sync, поэтому ошибки игнорируются.
+func syncDir(dir string) {
+	if d, err := os.Open(dir); err == nil {
+		d.Sync()
+		d.Close()
+	}
+}
+
+// decodeChatData разбирает содержимое events.json
+func decodeChatData(raw []byte) ([]ChatData, error) {
+	var data []ChatData
+	if err := json.NewDecoder(bytes.NewReader(raw)).Decode(&data); err != nil {
+		return nil, err
+	}
+	return data, nil
+}
+
+// listBackups возвращает имена резервных копий от старой к новой
+func (s *JSONStorage) listBackups() ([]string, error) {
+	entries, err := os.ReadDir(s.backupDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var names []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, backupPrefix) || !strings.HasSuffix(name, backupSuffix) {
+			continue
+		}
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names, nil
+}
+
+// backupTime извлекает момент создания резервной копии из имени файла
+func backupTime(name string) (time.Time, bool) {
+	stamp := strings.TrimSuffix(strings.TrimPrefix(name, backupPrefix), backupSuffix)
+	parsed, err := time.Parse(backupTimeFormat, stamp)
+	return parsed, err == nil
+}
+
+// maybeBackup сохраняет только что записанные данные в резервную копию, если с прошлой копии
+// прошло не меньше backupInterval, и удаляет копии сверх backupCount. Ошибки резервного
+// копирования только логируются: основная запись уже выполнена.
+func (s *JSONStorage) maybeBackup(raw []byte, now time.Time) {
+	if s.backupCount <= 0 {
+		return
+	}
+	if !s.lastBackup.IsZero() && now.Sub(s.lastBackup) < s.backupInterval {
+		return
+	}
+	if err := os.MkdirAll(s.backupDir, os.ModePerm); err != nil {
+		s.logger.Error("Ошибка создания каталога резервных копий", zap.String("dir", s.backupDir), zap.Error(err))
+		return
+	}
+	name := backupPrefix + now.UTC().Format(backupTimeFormat) + backupSuffix
+	if err := writeFileAtomic(filepath.Join(s.backupDir, name), raw); err != nil {
+		s.logger.Error("Ошибка создания резервной копии", zap.String("backup", name), zap.Error(err))
+		return
+	}
+	s.lastBackup = now
+	s.pruneBackups()
+}
+
+// pruneBackups удаляет самые старые резервные копии сверх backupCount
+func (s *JSONStorage) pruneBackups() {
+	names, err := s.listBackups()
+	if err != nil {
+		s.logger.Error("Ошибка чтения каталога резервных копий", zap.String("dir", s.backupDir), zap.Error(err))
+		return
+	}
+	for len(names) > s.backupCount {
+		if err := os.Remove(filepath.Join(s.backupDir, names[0])); err != nil {
+			s.logger.Warn("Не удалось удалить старую резервную копию", zap.String("backup", names[0]), zap.Error(err))
+		}
+		names = names[1:]
+	}
+}
+
+// recoverDataFile проверяет events.json при запуске. Если файл не разбирается, он сохраняется
+// рядом с суффиксом .corrupt-<время>, а на его место восстанавливается последняя корректная
+// резервная копия.
+func (s *JSONStorage) recoverDataFile() {
+	names, err := s.listBackups()
+	if err != nil {
+		s.logger.Error("Ошибка чтения каталога резервных копий", zap.String("dir", s.backupDir), zap.Error(err))
+	}
+	if len(names) > 0 {
+		if stamp, ok := backupTime(names[len(names)-1]); ok {
+			s.lastBackup = stamp
+		}
+	}
+
+	raw, err := os.ReadFile(s.path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			s.logger.Error("Ошибка чтения файла данных", zap.String("path", s.path), zap.Error(err))
+		}
+		return
+	}
+	_, decodeErr := decodeChatData(raw)
+	if decodeErr == nil {
+		return
+	}
+
+	s.logger.Error("ФАЙЛ ДАННЫХ ПОВРЕЖДЁН, восстановление из резервной копии",
+		zap.String("path", s.path),
+		zap.Error(decodeErr))
+
+	for i := len(names) - 1; i >= 0; i-- {
+		backupPath := filepath.Join(s.backupDir, names[i])
+		backup, err := os.ReadFile(backupPath)
+		if err != nil {
+			s.logger.Warn("Не удалось прочитать резервную копию", zap.String("backup", backupPath), zap.Error(err))
+			continue
+		}
+		if _, err := decodeChatData(backup); err != nil {
+			s.logger.Warn("Резервная копия повреждена", zap.String("backup", backupPath), zap.Error(err))
+			continue
+		}
+
+		corruptPath := s.path + ".corrupt-" + time.Now().UTC().Format(backupTimeFormat)
+		if err := os.Rename(s.path, corruptPath); err != nil {
+			s.logger.Error("Не удалось сохранить повреждённый файл", zap.String("path", s.path), zap.Error(err))
+			return
+		}
+		if err := writeFileAtomic(s.path, backup); err != nil {
+			s.logger.Error("Не удалось восстановить файл данных из резервной копии",
+				zap.String("backup", backupPath),
+				zap.Error(err))
+			return
+		}
+		s.logger.Error("ДАННЫЕ ВОССТАНОВЛЕНЫ ИЗ РЕЗЕРВНОЙ КОПИИ: изменения после неё потеряны",
+			zap.String("backup", backupPath),
+			zap.String("corrupt_file", corruptPath))
+		return
+	}
+
+	s.logger.Error("Корректная резервная копия не найдена, файл данных оставлен без изменений",
+		zap.String("path", s.path),
+		zap.String("backup_dir", s.backupDir))
+}
@@ -0,0 +1,35 @@
+-- Начальная схема: чаты, события и пользователи (аналог ChatData из events.json)
+
+CREATE TABLE chats (
+    chat_id    BIGINT PRIMARY KEY,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT now()
+);
+
+CREATE TABLE events (
+    seq         BIGSERIAL PRIMARY KEY,
+    event_id    TEXT NOT NULL UNIQUE,
+    chat_id     BIGINT NOT NULL REFERENCES chats (chat_id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    date        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL
+);
+
+CREATE INDEX events_chat_name_idx ON events (chat_id, name);
+CREATE INDEX events_name_idx ON events (name);
+
+CREATE TABLE users (
+    chat_id BIGINT NOT NULL REFERENCES chats (chat_id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE TABLE user_events (
+    seq      BIGSERIAL PRIMARY KEY,
+    chat_id  BIGINT NOT NULL,
+    user_id  BIGINT NOT NULL,
+    event_id TEXT NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    FOREIGN KEY (chat_id, user_id) REFERENCES users (chat_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_events_user_idx ON user_events (chat_id, user_id);
@@ -0,0 +1,5 @@
+-- Имя события уникально в пределах чата (правило "duplicate event name" на уровне БД)
+
+DROP INDEX events_chat_name_idx;
+
+ALTER TABLE events ADD CONSTRAINT events_chat_name_key UNIQUE (chat_id, name);
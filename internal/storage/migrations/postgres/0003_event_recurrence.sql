@@ -0,0 +1,6 @@
+-- Правило повторения события (пустая строка recurrence - разовое событие)
+
+ALTER TABLE events
+    ADD COLUMN recurrence          TEXT    NOT NULL DEFAULT '',
+    ADD COLUMN recurrence_interval INTEGER NOT NULL DEFAULT 0,
+    ADD COLUMN recurrence_until    TEXT    NOT NULL DEFAULT '';
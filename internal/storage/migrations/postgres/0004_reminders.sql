@@ -0,0 +1,12 @@
+-- Настройка напоминаний события (NULL - по умолчанию) и журнал отправленных напоминаний
+
+ALTER TABLE events ADD COLUMN reminders TEXT;
+
+CREATE TABLE reminder_deliveries (
+    chat_id         BIGINT      NOT NULL,
+    event_id        TEXT        NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    occurrence      TEXT        NOT NULL,
+    reminder_offset TEXT        NOT NULL,
+    sent_at         TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (event_id, occurrence, reminder_offset)
+);
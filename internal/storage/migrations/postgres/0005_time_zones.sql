@@ -0,0 +1,5 @@
+-- Часовой пояс чата и собственный часовой пояс события (пустая строка - пояс по умолчанию)
+
+ALTER TABLE chats ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE events ADD COLUMN time_zone TEXT NOT NULL DEFAULT '';
@@ -0,0 +1,14 @@
+-- Состояние пошагового создания событий (по одному диалогу на пользователя в чате)
+
+CREATE TABLE conversations (
+    chat_id     BIGINT      NOT NULL,
+    user_id     BIGINT      NOT NULL,
+    step        TEXT        NOT NULL,
+    name        TEXT        NOT NULL DEFAULT '',
+    date        TEXT        NOT NULL DEFAULT '',
+    event_time  TEXT        NOT NULL DEFAULT '',
+    description TEXT        NOT NULL DEFAULT '',
+    message_id  BIGINT      NOT NULL DEFAULT 0,
+    updated_at  TIMESTAMPTZ NOT NULL,
+    PRIMARY KEY (chat_id, user_id)
+);
@@ -0,0 +1,20 @@
+-- Общие каталоги событий: чат-владелец публикует события, другие чаты подписываются
+
+CREATE TABLE catalogs (
+    name          TEXT PRIMARY KEY,
+    owner_chat_id BIGINT NOT NULL REFERENCES chats (chat_id) ON DELETE CASCADE
+);
+
+CREATE TABLE catalog_events (
+    seq      BIGSERIAL PRIMARY KEY,
+    catalog  TEXT NOT NULL REFERENCES catalogs (name) ON DELETE CASCADE,
+    event_id TEXT NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    UNIQUE (catalog, event_id)
+);
+
+CREATE TABLE catalog_subscriptions (
+    seq     BIGSERIAL PRIMARY KEY,
+    chat_id BIGINT NOT NULL REFERENCES chats (chat_id) ON DELETE CASCADE,
+    catalog TEXT NOT NULL REFERENCES catalogs (name) ON DELETE CASCADE,
+    UNIQUE (chat_id, catalog)
+);
@@ -0,0 +1,9 @@
+-- Закреплённые сообщения с обратным отсчётом (по одному на чат)
+
+CREATE TABLE pinned_countdowns (
+    chat_id    BIGINT      PRIMARY KEY,
+    message_id BIGINT      NOT NULL,
+    size       INTEGER     NOT NULL,
+    text       TEXT        NOT NULL DEFAULT '',
+    updated_at TIMESTAMPTZ NOT NULL
+);
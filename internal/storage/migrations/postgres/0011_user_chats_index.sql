@@ -0,0 +1,3 @@
+-- Поиск чатов пользователя для inline-запросов
+
+CREATE INDEX users_user_idx ON users (user_id);
@@ -0,0 +1,3 @@
+-- Дополнительные команды события через запятую; уникальность в чате проверяет EventService
+
+ALTER TABLE events ADD COLUMN aliases TEXT NOT NULL DEFAULT '';
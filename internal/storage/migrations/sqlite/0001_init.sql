@@ -0,0 +1,35 @@
+-- Начальная схема: чаты, события и пользователи (аналог ChatData из events.json)
+
+CREATE TABLE chats (
+    chat_id    INTEGER PRIMARY KEY,
+    created_at TIMESTAMP NOT NULL DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE TABLE events (
+    seq         INTEGER PRIMARY KEY AUTOINCREMENT,
+    event_id    TEXT NOT NULL UNIQUE,
+    chat_id     INTEGER NOT NULL REFERENCES chats (chat_id) ON DELETE CASCADE,
+    name        TEXT NOT NULL,
+    date        TEXT NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status      TEXT NOT NULL,
+    UNIQUE (chat_id, name)
+);
+
+CREATE INDEX events_name_idx ON events (name);
+
+CREATE TABLE users (
+    chat_id INTEGER NOT NULL REFERENCES chats (chat_id) ON DELETE CASCADE,
+    user_id INTEGER NOT NULL,
+    PRIMARY KEY (chat_id, user_id)
+);
+
+CREATE TABLE user_events (
+    seq      INTEGER PRIMARY KEY AUTOINCREMENT,
+    chat_id  INTEGER NOT NULL,
+    user_id  INTEGER NOT NULL,
+    event_id TEXT NOT NULL REFERENCES events (event_id) ON DELETE CASCADE,
+    FOREIGN KEY (chat_id, user_id) REFERENCES users (chat_id, user_id) ON DELETE CASCADE
+);
+
+CREATE INDEX user_events_user_idx ON user_events (chat_id, user_id);
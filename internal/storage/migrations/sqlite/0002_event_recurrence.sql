@@ -0,0 +1,5 @@
+-- Правило повторения события (пустая строка recurrence - разовое событие)
+
+ALTER TABLE events ADD COLUMN recurrence TEXT NOT NULL DEFAULT '';
+ALTER TABLE events ADD COLUMN recurrence_interval INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE events ADD COLUMN recurrence_until TEXT NOT NULL DEFAULT '';
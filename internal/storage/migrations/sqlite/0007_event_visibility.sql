@@ -0,0 +1,5 @@
+-- Видимость события в других чатах (пустая строка - только свой чат) и список чатов через запятую
+
+ALTER TABLE events ADD COLUMN visibility TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE events ADD COLUMN shared_with TEXT NOT NULL DEFAULT '';
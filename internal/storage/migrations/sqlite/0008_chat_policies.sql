@@ -0,0 +1,5 @@
+-- Политики прав чата: кто создаёт и кто изменяет события (пустая строка - по умолчанию)
+
+ALTER TABLE chats ADD COLUMN create_policy TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE chats ADD COLUMN edit_policy TEXT NOT NULL DEFAULT '';
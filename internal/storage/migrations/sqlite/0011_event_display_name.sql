@@ -0,0 +1,3 @@
+-- Отображаемое имя события на любом языке; name остаётся командой события (пустая строка - показывать name)
+
+ALTER TABLE events ADD COLUMN display_name TEXT NOT NULL DEFAULT '';
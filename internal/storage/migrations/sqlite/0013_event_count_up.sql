@@ -0,0 +1,6 @@
+-- Режим события (пустая строка - обратный отсчёт, countup - счёт прошедшего времени)
+-- и объявления о круглых датах с события
+
+ALTER TABLE events ADD COLUMN mode TEXT NOT NULL DEFAULT '';
+
+ALTER TABLE events ADD COLUMN announce_milestones INTEGER NOT NULL DEFAULT 0;
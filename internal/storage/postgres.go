@@ -0,0 +1,37 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	_ "github.com/jackc/pgx/v5/stdlib"
+)
+
+var _ Storage = (*PostgresStorage)(nil)
+
+// PostgresStorage хранит чаты, события и пользователей в PostgreSQL
+type PostgresStorage struct {
+	*sqlStorage
+}
+
+// NewPostgresStorage подключается к PostgreSQL по dsn и применяет миграции схемы
+func NewPostgresStorage(dsn string) (*PostgresStorage, error) {
+	db, err := sql.Open("pgx", dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	core, err := openSQLStorage(db, "migrations/postgres", isPostgresUniqueViolation)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open postgres storage: %w", err)
+	}
+	return &PostgresStorage{sqlStorage: core}, nil
+}
+
+func isPostgresUniqueViolation(err error) bool {
+	var pgErr *pgconn.PgError
+	return errors.As(err, &pgErr) && pgErr.Code == "23505"
+}
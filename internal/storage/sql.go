@@ -0,0 +1,818 @@
+package storage
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+// queryTimeout ограничивает время одного обращения к базе данных
+const queryTimeout = 10 * time.Second
+
+const eventColumns = "e.event_id, e.chat_id, e.name, e.date, e.description, e.status, " +
+	"e.recurrence, e.recurrence_interval, e.recurrence_until, e.reminders, e.time_zone, " +
+	"e.visibility, e.shared_with, e.display_name, e.aliases, e.mode, e.announce_milestones"
+
+// eventWriteColumns перечисляет изменяемые колонки событий в порядке eventWriteValues
+var eventWriteColumns = []string{
+	"name", "date", "description", "status",
+	"recurrence", "recurrence_interval", "recurrence_until",
+	"reminders", "time_zone",
+	"visibility", "shared_with", "display_name", "aliases", "mode", "announce_milestones",
+}
+
+// eventWriteValues возвращает значения колонок eventWriteColumns для события
+func eventWriteValues(event models.Event) []any {
+	var frequency, until string
+	var interval int
+	if event.Recurrence != nil {
+		frequency = string(event.Recurrence.Frequency)
+		interval = event.Recurrence.Interval
+		until = event.Recurrence.Until
+	}
+	// NULL - напоминания по умолчанию, пустая строка - напоминания отключены
+	var reminders sql.NullString
+	if event.Reminders != nil {
+		reminders = sql.NullString{String: strings.Join(event.Reminders, ","), Valid: true}
+	}
+	sharedWith := make([]string, len(event.SharedWith))
+	for i, chatID := range event.SharedWith {
+		sharedWith[i] = strconv.FormatInt(chatID, 10)
+	}
+	return []any{
+		event.Name, event.Date, event.Description, string(event.Status),
+		frequency, interval, until,
+		reminders, event.TimeZone,
+		string(event.Visibility), strings.Join(sharedWith, ","), event.DisplayName,
+		strings.Join(event.Aliases, ","), string(event.Mode), event.AnnounceMilestones,
+	}
+}
+
+// upsertEventQuery вставляет событие или обновляет существующее с тем же event_id.
+// Параметры: $1 event_id, $2 chat_id, далее значения eventWriteColumns.
+var upsertEventQuery = func() string {
+	placeholders := make([]string, len(eventWriteColumns))
+	updates := make([]string, len(eventWriteColumns))
+	for i, column := range eventWriteColumns {
+		placeholders[i] = fmt.Sprintf("$%d", i+3)
+		updates[i] = fmt.Sprintf("%s = EXCLUDED.%s", column, column)
+	}
+	return fmt.Sprintf(`INSERT INTO events (event_id, chat_id, %s) VALUES ($1, $2, %s)
+		ON CONFLICT (event_id) DO UPDATE SET %s`,
+		strings.Join(eventWriteColumns, ", "), strings.Join(placeholders, ", "), strings.Join(updates, ", "))
+}()
+
+// updateEventQuery обновляет событие чата. Параметры: $1 chat_id, $2 event_id,
+// далее значения eventWriteColumns.
+var updateEventQuery = func() string {
+	assignments := make([]string, len(eventWriteColumns))
+	for i, column := range eventWriteColumns {
+		assignments[i] = fmt.Sprintf("%s = $%d", column, i+3)
+	}
+	return fmt.Sprintf(`UPDATE events SET %s WHERE chat_id = $1 AND event_id = $2`, strings.Join(assignments, ", "))
+}()
+
+// saveChatSettingsQuery создаёт чат или обновляет его настройки.
+// Параметры: $1 chat_id, далее значения chatSettingsValues.
+const saveChatSettingsQuery = `INSERT INTO chats (chat_id, time_zone, create_policy, edit_policy) VALUES ($1, $2, $3, $4)
+	ON CONFLICT (chat_id) DO UPDATE SET time_zone = EXCLUDED.time_zone,
+		create_policy = EXCLUDED.create_policy, edit_policy = EXCLUDED.edit_policy`
+
+// chatSettingsValues возвращает параметры saveChatSettingsQuery после chat_id
+func chatSettingsValues(settings models.ChatSettings) []any {
+	return []any{settings.TimeZone, string(settings.CreatePolicy), string(settings.EditPolicy)}
+}
+
+// sqlStorage содержит общую для PostgreSQL и SQLite реализацию Storage.
+// Запросы используют плейсхолдеры $N, которые понимают оба драйвера.
+type sqlStorage struct {
+	db *sql.DB
+	// isUniqueViolation распознаёт нарушение ограничения уникальности конкретной СУБД
+	isUniqueViolation func(err error) bool
+}
+
+// openSQLStorage проверяет соединение и применяет миграции из каталога dir
+func openSQLStorage(db *sql.DB, dir string, isUniqueViolation func(error) bool) (*sqlStorage, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), time.Minute)
+	defer cancel()
+
+	if err := db.PingContext(ctx); err != nil {
+		return nil, err
+	}
+
+	migrations, err := loadMigrations(migrationsFS, dir)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyMigrations(ctx, db, migrations); err != nil {
+		return nil, err
+	}
+
+	return &sqlStorage{db: db, isUniqueViolation: isUniqueViolation}, nil
+}
+
+// Close закрывает соединения с базой данных
+func (s *sqlStorage) Close() error {
+	return s.db.Close()
+}
+
+type rowScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanEvent(row rowScanner) (models.Event, error) {
+	var event models.Event
+	var status, frequency, until, visibility, sharedWith, aliases, mode string
+	var interval int
+	var reminders sql.NullString
+	err := row.Scan(&event.EventID, &event.ChatID, &event.Name, &event.Date, &event.Description, &status,
+		&frequency, &interval, &until, &reminders, &event.TimeZone, &visibility, &sharedWith, &event.DisplayName, &aliases, &mode, &event.AnnounceMilestones)
+	event.Status = models.EventStatus(status)
+	event.Visibility = models.EventVisibility(visibility)
+	event.Mode = models.EventMode(mode)
+	if sharedWith != "" {
+		for _, id := range strings.Split(sharedWith, ",") {
+			if chatID, err := strconv.ParseInt(id, 10, 64); err == nil {
+				event.SharedWith = append(event.SharedWith, chatID)
+			}
+		}
+	}
+	if aliases != "" {
+		event.Aliases = strings.Split(aliases, ",")
+	}
+	if reminders.Valid {
+		event.Reminders = []string{}
+		if reminders.String != "" {
+			event.Reminders = strings.Split(reminders.String, ",")
+		}
+	}
+	if frequency != "" {
+		event.Recurrence = &models.Recurrence{
+			Frequency: models.RecurrenceFrequency(frequency),
+			Interval:  interval,
+			Until:     until,
+		}
+	}
+	return event, err
+}
+
+func (s *sqlStorage) queryEvents(query string, args ...any) ([]models.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	events := []models.Event{}
+	for rows.Next() {
+		event, err := scanEvent(rows)
+		if err != nil {
+			return nil, err
+		}
+		events = append(events, event)
+	}
+	return events, rows.Err()
+}
+
+func ensureChat(ctx context.Context, tx *sql.Tx, chatID int64) error {
+	_, err := tx.ExecContext(ctx, `INSERT INTO chats (chat_id) VALUES ($1) ON CONFLICT (chat_id) DO NOTHING`, chatID)
+	return err
+}
+
+func (s *sqlStorage) SaveEvent(chatID int64, event models.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := ensureChat(ctx, tx, chatID); err != nil {
+		return err
+	}
+	args := append([]any{event.EventID, chatID}, eventWriteValues(event)...)
+	_, err = tx.ExecContext(ctx, upsertEventQuery, args...)
+	if err != nil {
+		if s.isUniqueViolation(err) {
+			return ErrDuplicateEvent
+		}
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) UpdateEvent(chatID int64, event models.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	args := append([]any{chatID, event.EventID}, eventWriteValues(event)...)
+	result, err := s.db.ExecContext(ctx, updateEventQuery, args...)
+	if err != nil {
+		if s.isUniqueViolation(err) {
+			return ErrDuplicateEvent
+		}
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+func (s *sqlStorage) SetEventStatus(chatID int64, eventID string, status models.EventStatus) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `UPDATE events SET status = $1 WHERE chat_id = $2 AND event_id = $3`,
+		string(status), chatID, eventID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+func (s *sqlStorage) DeleteEvent(chatID int64, eventID string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM events WHERE chat_id = $1 AND event_id = $2`, chatID, eventID)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrEventNotFound
+	}
+	return nil
+}
+
+func (s *sqlStorage) GetEvents(chatID int64) ([]models.Event, error) {
+	return s.queryEvents(`SELECT `+eventColumns+` FROM events e WHERE e.chat_id = $1 ORDER BY e.seq`, chatID)
+}
+
+func (s *sqlStorage) GetAllEvents() ([]models.Event, error) {
+	return s.queryEvents(`SELECT ` + eventColumns + ` FROM events e
+		JOIN chats c ON c.chat_id = e.chat_id
+		ORDER BY c.created_at, c.chat_id, e.seq`)
+}
+
+func (s *sqlStorage) GetEvent(chatID int64, name string) (*models.Event, error) {
+	// Событие с таким именем важнее события с таким псевдонимом
+	events, err := s.queryEvents(`SELECT `+eventColumns+` FROM events e
+		WHERE e.chat_id = $1 AND (e.name = $2 OR `+aliasCondition("$3")+`)
+		ORDER BY CASE WHEN e.name = $2 THEN 0 ELSE 1 END, e.seq`, chatID, name, aliasPattern(name))
+	if err != nil {
+		return nil, err
+	}
+	return firstWithCommand(events, name)
+}
+
+func (s *sqlStorage) FindEventAcrossChats(name string, viewerChatID int64) (*models.Event, int64, error) {
+	// Чаты просматриваются в порядке их появления; shared_with хранит ID чатов через запятую
+	events, err := s.queryEvents(`SELECT `+eventColumns+` FROM events e
+		JOIN chats c ON c.chat_id = e.chat_id
+		WHERE (e.name = $1 OR `+aliasCondition("$6")+`) AND e.chat_id <> $2
+			AND (e.visibility = $3 OR (e.visibility = $4 AND ',' || e.shared_with || ',' LIKE $5))
+		ORDER BY c.created_at, c.chat_id, CASE WHEN e.name = $1 THEN 0 ELSE 1 END, e.seq`,
+		name, viewerChatID, string(models.VisibilityPublic), string(models.VisibilityShared),
+		fmt.Sprintf("%%,%d,%%", viewerChatID), aliasPattern(name))
+	if err != nil {
+		return nil, 0, err
+	}
+	event, err := firstWithCommand(events, name)
+	if err != nil {
+		return nil, 0, err
+	}
+	return event, event.ChatID, nil
+}
+
+func (s *sqlStorage) EventExists(chatID int64, name string) bool {
+	_, err := s.GetEvent(chatID, name)
+	return err == nil
+}
+
+// aliasCondition отбирает события, среди псевдонимов которых может быть значение параметра
+// placeholder (см. aliasPattern). LIKE в SQLite не учитывает регистр, поэтому кандидаты
+// дополнительно проверяются в firstWithCommand.
+func aliasCondition(placeholder string) string {
+	return `',' || e.aliases || ',' LIKE ` + placeholder + ` ESCAPE '\'`
+}
+
+// aliasPattern - шаблон LIKE для поиска псевдонима в списке через запятую
+func aliasPattern(name string) string {
+	escaped := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(name)
+	return "%," + escaped + ",%"
+}
+
+// firstWithCommand возвращает первое событие, имя или псевдоним которого совпадает с name
+func firstWithCommand(events []models.Event, name string) (*models.Event, error) {
+	for i := range events {
+		if events[i].HasCommand(name) {
+			return &events[i], nil
+		}
+	}
+	return nil, ErrEventNotFound
+}
+
+func (s *sqlStorage) GetUser(chatID, userID int64) (*models.User, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	var exists bool
+	err := s.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM users WHERE chat_id = $1 AND user_id = $2)`, chatID, userID).Scan(&exists)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrUserNotFound
+	}
+
+	events, err := s.queryEvents(`SELECT `+eventColumns+` FROM user_events ue
+		JOIN events e ON e.event_id = ue.event_id
+		WHERE ue.chat_id = $1 AND ue.user_id = $2
+		ORDER BY ue.seq`, chatID, userID)
+	if err != nil {
+		return nil, err
+	}
+	return &models.User{UserID: userID, ChatID: chatID, Events: events}, nil
+}
+
+func (s *sqlStorage) AddEventToUser(chatID, userID int64, event models.Event) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := ensureChat(ctx, tx, chatID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO users (chat_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, chatID, userID)
+	if err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO user_events (chat_id, user_id, event_id) VALUES ($1, $2, $3)`, chatID, userID, event.EventID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) AddUser(chatID, userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := ensureChat(ctx, tx, chatID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO users (chat_id, user_id) VALUES ($1, $2) ON CONFLICT DO NOTHING`, chatID, userID)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) GetUserChatIDs(userID int64) ([]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT u.chat_id FROM users u
+		JOIN chats c ON c.chat_id = u.chat_id
+		WHERE u.user_id = $1
+		ORDER BY c.created_at, c.chat_id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chatIDs := []int64{}
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, rows.Err()
+}
+
+func (s *sqlStorage) GetReminderDeliveries(chatID int64, eventID string) ([]models.ReminderDelivery, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT event_id, occurrence, reminder_offset, sent_at
+		FROM reminder_deliveries WHERE chat_id = $1 AND event_id = $2`, chatID, eventID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	deliveries := []models.ReminderDelivery{}
+	for rows.Next() {
+		var delivery models.ReminderDelivery
+		if err := rows.Scan(&delivery.EventID, &delivery.Occurrence, &delivery.Offset, &delivery.SentAt); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, delivery)
+	}
+	return deliveries, rows.Err()
+}
+
+func (s *sqlStorage) SaveReminderDelivery(chatID int64, delivery models.ReminderDelivery) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO reminder_deliveries (chat_id, event_id, occurrence, reminder_offset, sent_at)
+		VALUES ($1, $2, $3, $4, $5)
+		ON CONFLICT (event_id, occurrence, reminder_offset) DO NOTHING`,
+		chatID, delivery.EventID, delivery.Occurrence, delivery.Offset, delivery.SentAt.UTC())
+	return err
+}
+
+func (s *sqlStorage) GetChatSettings(chatID int64) (models.ChatSettings, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	var settings models.ChatSettings
+	var createPolicy, editPolicy string
+	err := s.db.QueryRowContext(ctx, `SELECT time_zone, create_policy, edit_policy FROM chats WHERE chat_id = $1`, chatID).
+		Scan(&settings.TimeZone, &createPolicy, &editPolicy)
+	if errors.Is(err, sql.ErrNoRows) {
+		return models.ChatSettings{}, nil
+	}
+	settings.CreatePolicy = models.PermissionPolicy(createPolicy)
+	settings.EditPolicy = models.PermissionPolicy(editPolicy)
+	return settings, err
+}
+
+func (s *sqlStorage) SaveChatSettings(chatID int64, settings models.ChatSettings) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, saveChatSettingsQuery, append([]any{chatID}, chatSettingsValues(settings)...)...)
+	return err
+}
+
+const conversationColumns = "chat_id, user_id, step, name, date, event_time, description, message_id, updated_at"
+
+func scanConversation(row rowScanner) (models.Conversation, error) {
+	var conversation models.Conversation
+	var step string
+	err := row.Scan(&conversation.ChatID, &conversation.UserID, &step, &conversation.Name, &conversation.Date,
+		&conversation.Time, &conversation.Description, &conversation.MessageID, &conversation.UpdatedAt)
+	conversation.Step = models.ConversationStep(step)
+	return conversation, err
+}
+
+func (s *sqlStorage) GetConversation(chatID, userID int64) (*models.Conversation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	conversation, err := scanConversation(s.db.QueryRowContext(ctx,
+		`SELECT `+conversationColumns+` FROM conversations WHERE chat_id = $1 AND user_id = $2`, chatID, userID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrConversationNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &conversation, nil
+}
+
+func (s *sqlStorage) SaveConversation(conversation models.Conversation) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, `INSERT INTO conversations (`+conversationColumns+`)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		ON CONFLICT (chat_id, user_id) DO UPDATE SET step = EXCLUDED.step, name = EXCLUDED.name,
+			date = EXCLUDED.date, event_time = EXCLUDED.event_time, description = EXCLUDED.description,
+			message_id = EXCLUDED.message_id, updated_at = EXCLUDED.updated_at`,
+		conversation.ChatID, conversation.UserID, string(conversation.Step), conversation.Name, conversation.Date,
+		conversation.Time, conversation.Description, conversation.MessageID, conversation.UpdatedAt.UTC())
+	return err
+}
+
+func (s *sqlStorage) DeleteConversation(chatID, userID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM conversations WHERE chat_id = $1 AND user_id = $2`, chatID, userID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrConversationNotFound
+	}
+	return nil
+}
+
+func (s *sqlStorage) GetConversations() ([]models.Conversation, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+conversationColumns+` FROM conversations ORDER BY updated_at`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	conversations := []models.Conversation{}
+	for rows.Next() {
+		conversation, err := scanConversation(rows)
+		if err != nil {
+			return nil, err
+		}
+		conversations = append(conversations, conversation)
+	}
+	return conversations, rows.Err()
+}
+
+func (s *sqlStorage) GetChatIDs() ([]int64, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT chat_id FROM chats ORDER BY created_at, chat_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	chatIDs := []int64{}
+	for rows.Next() {
+		var chatID int64
+		if err := rows.Scan(&chatID); err != nil {
+			return nil, err
+		}
+		chatIDs = append(chatIDs, chatID)
+	}
+	return chatIDs, rows.Err()
+}
+
+// queryRower - *sql.DB или *sql.Tx
+type queryRower interface {
+	QueryRowContext(ctx context.Context, query string, args ...any) *sql.Row
+}
+
+// catalogExists сообщает, есть ли каталог с таким именем
+func catalogExists(ctx context.Context, q queryRower, name string) (bool, error) {
+	var exists bool
+	err := q.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM catalogs WHERE name = $1)`, name).Scan(&exists)
+	return exists, err
+}
+
+func (s *sqlStorage) GetCatalog(name string) (*models.Catalog, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	catalog := models.Catalog{Name: name, EventIDs: []string{}}
+	err := s.db.QueryRowContext(ctx, `SELECT owner_chat_id FROM catalogs WHERE name = $1`, name).Scan(&catalog.OwnerChatID)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCatalogNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	rows, err := s.db.QueryContext(ctx, `SELECT event_id FROM catalog_events WHERE catalog = $1 ORDER BY seq`, name)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var eventID string
+		if err := rows.Scan(&eventID); err != nil {
+			return nil, err
+		}
+		catalog.EventIDs = append(catalog.EventIDs, eventID)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return &catalog, nil
+}
+
+func (s *sqlStorage) SaveCatalog(catalog models.Catalog) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	if err := ensureChat(ctx, tx, catalog.OwnerChatID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO catalogs (name, owner_chat_id) VALUES ($1, $2)
+		ON CONFLICT (name) DO UPDATE SET owner_chat_id = EXCLUDED.owner_chat_id`, catalog.Name, catalog.OwnerChatID)
+	if err != nil {
+		return err
+	}
+	// Список событий заменяется целиком, порядок публикации задаётся порядком вставки
+	if _, err := tx.ExecContext(ctx, `DELETE FROM catalog_events WHERE catalog = $1`, catalog.Name); err != nil {
+		return err
+	}
+	for _, eventID := range catalog.EventIDs {
+		_, err := tx.ExecContext(ctx, `INSERT INTO catalog_events (catalog, event_id) VALUES ($1, $2)
+			ON CONFLICT (catalog, event_id) DO NOTHING`, catalog.Name, eventID)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) GetCatalogEvents(name string) ([]models.Event, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	exists, err := catalogExists(ctx, s.db, name)
+	if err != nil {
+		return nil, err
+	}
+	if !exists {
+		return nil, ErrCatalogNotFound
+	}
+	// В каталоге показываются только события чата-владельца
+	return s.queryEvents(`SELECT `+eventColumns+` FROM catalog_events ce
+		JOIN catalogs c ON c.name = ce.catalog
+		JOIN events e ON e.event_id = ce.event_id AND e.chat_id = c.owner_chat_id
+		WHERE ce.catalog = $1
+		ORDER BY ce.seq`, name)
+}
+
+func (s *sqlStorage) GetSubscriptions(chatID int64) ([]string, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT catalog FROM catalog_subscriptions WHERE chat_id = $1 ORDER BY seq`, chatID)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	catalogs := []string{}
+	for rows.Next() {
+		var catalog string
+		if err := rows.Scan(&catalog); err != nil {
+			return nil, err
+		}
+		catalogs = append(catalogs, catalog)
+	}
+	return catalogs, rows.Err()
+}
+
+func (s *sqlStorage) Subscribe(chatID int64, catalog string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	exists, err := catalogExists(ctx, tx, catalog)
+	if err != nil {
+		return err
+	}
+	if !exists {
+		return ErrCatalogNotFound
+	}
+	if err := ensureChat(ctx, tx, chatID); err != nil {
+		return err
+	}
+	_, err = tx.ExecContext(ctx, `INSERT INTO catalog_subscriptions (chat_id, catalog) VALUES ($1, $2)
+		ON CONFLICT (chat_id, catalog) DO NOTHING`, chatID, catalog)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (s *sqlStorage) Unsubscribe(chatID int64, catalog string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM catalog_subscriptions WHERE chat_id = $1 AND catalog = $2`, chatID, catalog)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return ErrSubscriptionNotFound
+	}
+	return nil
+}
+
+const countdownColumns = "chat_id, message_id, size, text, updated_at"
+
+// saveCountdownQuery создаёт или заменяет закреплённый отсчёт чата
+const saveCountdownQuery = `INSERT INTO pinned_countdowns (` + countdownColumns + `)
+	VALUES ($1, $2, $3, $4, $5)
+	ON CONFLICT (chat_id) DO UPDATE SET message_id = EXCLUDED.message_id, size = EXCLUDED.size,
+		text = EXCLUDED.text, updated_at = EXCLUDED.updated_at`
+
+func scanCountdown(row rowScanner) (models.PinnedCountdown, error) {
+	var countdown models.PinnedCountdown
+	err := row.Scan(&countdown.ChatID, &countdown.MessageID, &countdown.Size, &countdown.Text, &countdown.UpdatedAt)
+	return countdown, err
+}
+
+func (s *sqlStorage) GetPinnedCountdown(chatID int64) (*models.PinnedCountdown, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	countdown, err := scanCountdown(s.db.QueryRowContext(ctx,
+		`SELECT `+countdownColumns+` FROM pinned_countdowns WHERE chat_id = $1`, chatID))
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrCountdownNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &countdown, nil
+}
+
+func (s *sqlStorage) SavePinnedCountdown(countdown models.PinnedCountdown) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	_, err := s.db.ExecContext(ctx, saveCountdownQuery, countdown.ChatID, countdown.MessageID, countdown.Size,
+		countdown.Text, countdown.UpdatedAt.UTC())
+	return err
+}
+
+func (s *sqlStorage) DeletePinnedCountdown(chatID int64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	result, err := s.db.ExecContext(ctx, `DELETE FROM pinned_countdowns WHERE chat_id = $1`, chatID)
+	if err != nil {
+		return err
+	}
+	if affected, err := result.RowsAffected(); err == nil && affected == 0 {
+		return ErrCountdownNotFound
+	}
+	return nil
+}
+
+func (s *sqlStorage) GetPinnedCountdowns() ([]models.PinnedCountdown, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), queryTimeout)
+	defer cancel()
+
+	rows, err := s.db.QueryContext(ctx, `SELECT `+countdownColumns+` FROM pinned_countdowns ORDER BY chat_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	countdowns := []models.PinnedCountdown{}
+	for rows.Next() {
+		countdown, err := scanCountdown(rows)
+		if err != nil {
+			return nil, err
+		}
+		countdowns = append(countdowns, countdown)
+	}
+	return countdowns, rows.Err()
+}
@@ -0,0 +1,50 @@
+package storage
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"modernc.org/sqlite"
+	sqlite3 "modernc.org/sqlite/lib"
+)
+
+var _ Storage = (*SQLiteStorage)(nil)
+
+// SQLiteStorage хранит данные в одном файле SQLite и не требует отдельного сервера
+type SQLiteStorage struct {
+	*sqlStorage
+}
+
+// NewSQLiteStorage открывает (или создаёт) базу SQLite по пути path и применяет миграции схемы
+func NewSQLiteStorage(path string) (*SQLiteStorage, error) {
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return nil, err
+	}
+
+	dsn := "file:" + path + "?_pragma=foreign_keys(1)&_pragma=busy_timeout(5000)&_pragma=journal_mode(WAL)"
+	db, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	// SQLite допускает только одного писателя, поэтому работаем через одно соединение
+	db.SetMaxOpenConns(1)
+
+	core, err := openSQLStorage(db, "migrations/sqlite", isSQLiteUniqueViolation)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("open sqlite storage: %w", err)
+	}
+	return &SQLiteStorage{sqlStorage: core}, nil
+}
+
+func isSQLiteUniqueViolation(err error) bool {
+	var sqliteErr *sqlite.Error
+	if !errors.As(err, &sqliteErr) {
+		return false
+	}
+	code := sqliteErr.Code()
+	return code == sqlite3.SQLITE_CONSTRAINT_UNIQUE || code == sqlite3.SQLITE_CONSTRAINT_PRIMARYKEY
+}
@@ -1,95 +1,244 @@
 package storage
 
 import (
+	"bytes"
 	"encoding/json"
 	"errors"
 	"os"
+	"path/filepath"
 	"sync"
+	"time"
 
-	"github.com/TheReshkin/tg-bot-family/internal/config"
 	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"go.uber.org/zap"
 )
 
-const eventsFile = "./data/events.json"
+// backupsDirName - каталог рядом с файлом данных, где хранятся ротируемые резервные копии
+const backupsDirName = "backups"
+
+var (
+	ErrEventNotFound = errors.New("event not found")
+	ErrUserNotFound  = errors.New("user not found")
+	// ErrDuplicateEvent возвращается, когда в чате уже есть событие с таким именем
+	ErrDuplicateEvent       = errors.New("duplicate event name")
+	ErrConversationNotFound = errors.New("conversation not found")
+	// ErrStorageClosed возвращается при записи в закрытое хранилище
+	ErrStorageClosed        = errors.New("storage closed")
+	ErrCatalogNotFound      = errors.New("catalog not found")
+	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrCountdownNotFound    = errors.New("pinned countdown not found")
+)
 
 type Storage interface {
 	SaveEvent(chatID int64, event models.Event) error
+	UpdateEvent(chatID int64, event models.Event) error
+	// SetEventStatus меняет только статус события, не затрагивая остальные поля: фоновое
+	// обновление статусов не откатывает изменения, сделанные после чтения события
+	SetEventStatus(chatID int64, eventID string, status models.EventStatus) error
+	DeleteEvent(chatID int64, eventID string) error
 	GetEvents(chatID int64) ([]models.Event, error)
 	GetAllEvents() ([]models.Event, error)
+	// GetEvent ищет событие чата по имени, а если такого нет - по псевдониму (models.Event.Aliases)
 	GetEvent(chatID int64, name string) (*models.Event, error)
-	FindEventAcrossChats(name string, excludeChatID int64) (*models.Event, int64, error)
+	// FindEventAcrossChats ищет в других чатах событие с таким именем или псевдонимом, которое видно
+	// чату viewerChatID (см. models.Event.IsVisibleTo); чаты просматриваются в порядке появления
+	FindEventAcrossChats(name string, viewerChatID int64) (*models.Event, int64, error)
 	EventExists(chatID int64, name string) bool
 	GetUser(chatID, userID int64) (*models.User, error)
 	AddEventToUser(chatID, userID int64, event models.Event) error
+	// AddUser запоминает, что пользователь состоит в чате; повторный вызов ничего не меняет
+	AddUser(chatID, userID int64) error
+	// GetUserChatIDs возвращает чаты, где известен пользователь, в порядке появления чатов
+	GetUserChatIDs(userID int64) ([]int64, error)
+	GetReminderDeliveries(chatID int64, eventID string) ([]models.ReminderDelivery, error)
+	SaveReminderDelivery(chatID int64, delivery models.ReminderDelivery) error
+	// GetChatSettings возвращает настройки чата; для неизвестного чата - настройки по умолчанию
+	GetChatSettings(chatID int64) (models.ChatSettings, error)
+	SaveChatSettings(chatID int64, settings models.ChatSettings) error
+	GetConversation(chatID, userID int64) (*models.Conversation, error)
+	// SaveConversation создаёт или заменяет диалог пользователя в чате
+	SaveConversation(conversation models.Conversation) error
+	DeleteConversation(chatID, userID int64) error
+	GetConversations() ([]models.Conversation, error)
+	// GetChatIDs возвращает все известные чаты в порядке их появления
+	GetChatIDs() ([]int64, error)
+	GetCatalog(name string) (*models.Catalog, error)
+	// SaveCatalog создаёт каталог или заменяет его владельца и список событий
+	SaveCatalog(catalog models.Catalog) error
+	// GetCatalogEvents возвращает опубликованные в каталоге события в порядке публикации
+	GetCatalogEvents(name string) ([]models.Event, error)
+	// GetSubscriptions возвращает имена каталогов, на которые подписан чат, в порядке подписки
+	GetSubscriptions(chatID int64) ([]string, error)
+	// Subscribe подписывает чат на каталог; повторная подписка игнорируется
+	Subscribe(chatID int64, catalog string) error
+	Unsubscribe(chatID int64, catalog string) error
+	GetPinnedCountdown(chatID int64) (*models.PinnedCountdown, error)
+	// SavePinnedCountdown создаёт или заменяет закреплённый отсчёт чата
+	SavePinnedCountdown(countdown models.PinnedCountdown) error
+	DeletePinnedCountdown(chatID int64) error
+	GetPinnedCountdowns() ([]models.PinnedCountdown, error)
 }
 
 type JSONStorage struct {
-	mu sync.RWMutex
+	mu     sync.RWMutex
+	closed bool
+
+	// data - всё содержимое events.json, загруженное при запуске; index ускоряет поиск по нему
+	data    []ChatData
+	index   jsonIndex
+	loadErr error
+
+	// flushDelay > 0 откладывает запись на диск, объединяя несколько изменений в одну запись
+	flushDelay time.Duration
+	dirty      bool
+	flushTimer *time.Timer
+
+	path           string
+	backupDir      string
+	backupCount    int
+	backupInterval time.Duration
+	lastBackup     time.Time
+	logger         *zap.Logger
+}
+
+// DefaultJSONPath - файл JSON-хранилища, если путь не указан в JSONOptions
+const DefaultJSONPath = "./data/events.json"
+
+// JSONOptions - настройки JSONStorage
+type JSONOptions struct {
+	// Path - файл данных; пустая строка означает DefaultJSONPath
+	Path string
+	// BackupCount - сколько резервных копий хранить; 0 отключает копии
+	BackupCount int
+	// BackupInterval - минимальное время между резервными копиями; 0 - копия при каждой записи
+	BackupInterval time.Duration
+	// FlushDelay > 0 откладывает запись на диск, объединяя несколько изменений в одну запись
+	FlushDelay time.Duration
 }
 
-func NewJSONStorage() *JSONStorage {
-	return &JSONStorage{}
+// NewJSONStorage открывает хранилище в файле options.Path и загружает его в память.
+// Если файл повреждён, он заменяется последней корректной резервной копией
+// из каталога backups рядом с ним.
+func NewJSONStorage(options JSONOptions) *JSONStorage {
+	if options.Path == "" {
+		options.Path = DefaultJSONPath
+	}
+	s := &JSONStorage{
+		path:           options.Path,
+		backupDir:      filepath.Join(filepath.Dir(options.Path), backupsDirName),
+		backupCount:    options.BackupCount,
+		backupInterval: options.BackupInterval,
+		flushDelay:     options.FlushDelay,
+		logger:         zap.L(),
+	}
+	s.recoverDataFile()
+	s.load()
+	return s
+}
+
+// Close дожидается завершения текущей записи в файл, записывает отложенные изменения
+// и запрещает дальнейшие, чтобы процесс не завершился посреди записи events.json
+func (s *JSONStorage) Close() error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.flushTimer != nil {
+		s.flushTimer.Stop()
+		s.flushTimer = nil
+	}
+	err := s.flush()
+	s.closed = true
+	return err
 }
 
 type ChatData struct {
-	ChatID int64          `json:"chat_id"`
-	Events []models.Event `json:"events"`
-	Users  []models.User  `json:"users"`
+	ChatID             int64                     `json:"chat_id"`
+	Events             []models.Event            `json:"events"`
+	Users              []models.User             `json:"users"`
+	ReminderDeliveries []models.ReminderDelivery `json:"reminder_deliveries,omitempty"`
+	Settings           models.ChatSettings       `json:"settings"`
+	Conversations      []models.Conversation     `json:"conversations,omitempty"`
+	// Catalogs - каталоги, владельцем которых является чат
+	Catalogs []models.Catalog `json:"catalogs,omitempty"`
+	// Subscriptions - имена каталогов, на которые подписан чат
+	Subscriptions []string `json:"subscriptions,omitempty"`
+	// Countdown - закреплённое сообщение с обратным отсчётом, если оно есть
+	Countdown *models.PinnedCountdown `json:"countdown,omitempty"`
 }
 
-func (s *JSONStorage) loadData() ([]ChatData, error) {
-	file, err := os.Open(eventsFile)
+// readFile читает и разбирает events.json
+func (s *JSONStorage) readFile() ([]ChatData, error) {
+	raw, err := os.ReadFile(s.path)
 	if err != nil {
 		if os.IsNotExist(err) {
 			return []ChatData{}, nil
 		}
 		return nil, err
 	}
-	defer file.Close()
 
-	var data []ChatData
-	err = json.NewDecoder(file).Decode(&data)
+	data, err := decodeChatData(raw)
 	if err != nil {
 		return nil, err
 	}
+	for i := range data {
+		data[i].Events = dedupeEvents(data[i].Events)
+	}
 	return data, nil
 }
 
-func (s *JSONStorage) saveData(data []ChatData) error {
-	if _, err := os.Stat("./data"); os.IsNotExist(err) {
-		err := os.Mkdir("./data", os.ModePerm)
-		if err != nil {
-			return err
+// dedupeEvents схлопывает повторные записи с одинаковым EventID, оставляя последнюю версию.
+// Такие записи создавал прежний UpdateEventStatus, дописывавший событие вместо замены.
+func dedupeEvents(events []models.Event) []models.Event {
+	positions := make(map[string]int, len(events))
+	result := make([]models.Event, 0, len(events))
+	for _, event := range events {
+		if pos, ok := positions[event.EventID]; ok {
+			result[pos] = event
+			continue
 		}
+		positions[event.EventID] = len(result)
+		result = append(result, event)
 	}
+	return result
+}
 
-	file, err := os.Create(eventsFile)
-	if err != nil {
+// writeData атомарно записывает data в events.json и при необходимости делает резервную копию
+func (s *JSONStorage) writeData(data []ChatData) error {
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
 		return err
 	}
-	defer file.Close()
 
-	return json.NewEncoder(file).Encode(data)
+	var buf bytes.Buffer
+	if err := json.NewEncoder(&buf).Encode(data); err != nil {
+		return err
+	}
+	if err := writeFileAtomic(s.path, buf.Bytes()); err != nil {
+		return err
+	}
+	s.maybeBackup(buf.Bytes(), time.Now())
+	return nil
 }
 
+// SaveEvent добавляет событие в чат или заменяет существующее с тем же EventID
 func (s *JSONStorage) SaveEvent(chatID int64, event models.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	data, err := s.loadData()
+	if existing, ok := s.eventByName(chatID, event.Name); ok && existing.EventID != event.EventID {
+		return ErrDuplicateEvent
+	}
+	data, err := s.cloneData()
 	if err != nil {
 		return err
 	}
 
-	found := false
-	for i, chat := range data {
-		if chat.ChatID == chatID {
+	// Копия данных сохраняет позиции, поэтому индекс применим к ней
+	if i, ok := s.index.chats[chatID]; ok {
+		if j, ok := s.index.byID[eventKey{chatID: chatID, key: event.EventID}]; ok {
+			data[i].Events[j] = event
+		} else {
 			data[i].Events = append(data[i].Events, event)
-			found = true
-			break
 		}
-	}
-	if !found {
+	} else {
 		data = append(data, ChatData{
 			ChatID: chatID,
 			Events: []models.Event{event},
@@ -100,20 +249,137 @@ func (s *JSONStorage) SaveEvent(chatID int64, event models.Event) error {
 	return s.saveData(data)
 }
 
-func (s *JSONStorage) GetEvents(chatID int64) ([]models.Event, error) {
-	s.mu.RLock()
-	defer s.mu.RUnlock()
+// UpdateEvent заменяет событие чата с тем же EventID, включая копии у пользователей
+func (s *JSONStorage) UpdateEvent(chatID int64, event models.Event) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
 
-	data, err := s.loadData()
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+	i, ok := s.index.chats[chatID]
+	if !ok {
+		return ErrEventNotFound
+	}
+	index, ok := s.index.byID[eventKey{chatID: chatID, key: event.EventID}]
+	if !ok {
+		return ErrEventNotFound
+	}
+	if existing, ok := s.eventByName(chatID, event.Name); ok && existing.EventID != event.EventID {
+		return ErrDuplicateEvent
+	}
+	data, err := s.cloneData()
 	if err != nil {
-		return nil, err
+		return err
 	}
 
-	for _, chat := range data {
-		if chat.ChatID == chatID {
-			return chat.Events, nil
+	data[i].Events[index] = event
+	for j, user := range data[i].Users {
+		for k, userEvent := range user.Events {
+			if userEvent.EventID == event.EventID {
+				data[i].Users[j].Events[k] = event
+			}
 		}
 	}
+	return s.saveData(data)
+}
+
+// SetEventStatus меняет статус события чата, включая копии у пользователей
+func (s *JSONStorage) SetEventStatus(chatID int64, eventID string, status models.EventStatus) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+	i, ok := s.index.chats[chatID]
+	if !ok {
+		return ErrEventNotFound
+	}
+	index, ok := s.index.byID[eventKey{chatID: chatID, key: eventID}]
+	if !ok {
+		return ErrEventNotFound
+	}
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	data[i].Events[index].Status = status
+	for j, user := range data[i].Users {
+		for k, userEvent := range user.Events {
+			if userEvent.EventID == eventID {
+				data[i].Users[j].Events[k].Status = status
+			}
+		}
+	}
+	return s.saveData(data)
+}
+
+// DeleteEvent удаляет событие из чата и из списков событий пользователей
+func (s *JSONStorage) DeleteEvent(chatID int64, eventID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	for i, chat := range data {
+		if chat.ChatID != chatID {
+			continue
+		}
+		events := make([]models.Event, 0, len(chat.Events))
+		for _, event := range chat.Events {
+			if event.EventID != eventID {
+				events = append(events, event)
+			}
+		}
+		if len(events) == len(chat.Events) {
+			return ErrEventNotFound
+		}
+		data[i].Events = events
+		for j, user := range chat.Users {
+			userEvents := make([]models.Event, 0, len(user.Events))
+			for _, event := range user.Events {
+				if event.EventID != eventID {
+					userEvents = append(userEvents, event)
+				}
+			}
+			data[i].Users[j].Events = userEvents
+		}
+		deliveries := make([]models.ReminderDelivery, 0, len(chat.ReminderDeliveries))
+		for _, delivery := range chat.ReminderDeliveries {
+			if delivery.EventID != eventID {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+		data[i].ReminderDeliveries = deliveries
+		for j, catalog := range chat.Catalogs {
+			eventIDs := make([]string, 0, len(catalog.EventIDs))
+			for _, id := range catalog.EventIDs {
+				if id != eventID {
+					eventIDs = append(eventIDs, id)
+				}
+			}
+			data[i].Catalogs[j].EventIDs = eventIDs
+		}
+		return s.saveData(data)
+	}
+	return ErrEventNotFound
+}
+
+func (s *JSONStorage) GetEvents(chatID int64) ([]models.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	if chat := s.chat(chatID); chat != nil {
+		return append([]models.Event{}, chat.Events...), nil
+	}
 	return []models.Event{}, nil
 }
 
@@ -121,13 +387,11 @@ func (s *JSONStorage) GetAllEvents() ([]models.Event, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	data, err := s.loadData()
-	if err != nil {
-		return nil, err
+	if s.loadErr != nil {
+		return nil, s.loadErr
 	}
-
 	var allEvents []models.Event
-	for _, chat := range data {
+	for _, chat := range s.data {
 		allEvents = append(allEvents, chat.Events...)
 	}
 	return allEvents, nil
@@ -137,58 +401,31 @@ func (s *JSONStorage) GetEvent(chatID int64, name string) (*models.Event, error)
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	data, err := s.loadData()
-	if err != nil {
-		return nil, err
+	if s.loadErr != nil {
+		return nil, s.loadErr
 	}
-
-	for _, chat := range data {
-		if chat.ChatID == chatID {
-			for _, event := range chat.Events {
-				if event.Name == name {
-					return &event, nil
-				}
-			}
-		}
+	if event, ok := s.eventByCommand(chatID, name); ok {
+		return &event, nil
 	}
-	return nil, errors.New("event not found")
+	return nil, ErrEventNotFound
 }
 
-func (s *JSONStorage) FindEventAcrossChats(name string, excludeChatID int64) (*models.Event, int64, error) {
+func (s *JSONStorage) FindEventAcrossChats(name string, viewerChatID int64) (*models.Event, int64, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	data, err := s.loadData()
-	if err != nil {
-		return nil, 0, err
-	}
-
-	// First, try to find in the test chat (prioritize it for cross-chat searches)
-	testChatID := config.LoadTestChatID()
-	if excludeChatID != testChatID {
-		for _, chat := range data {
-			if chat.ChatID == testChatID {
-				for _, event := range chat.Events {
-					if event.Name == name {
-						return &event, testChatID, nil
-					}
-				}
-			}
-		}
+	if s.loadErr != nil {
+		return nil, 0, s.loadErr
 	}
-
-	// If not found in test chat, search other chats
-	for _, chat := range data {
-		if chat.ChatID != excludeChatID && chat.ChatID != testChatID {
-			for _, event := range chat.Events {
-				if event.Name == name {
-					return &event, chat.ChatID, nil
-				}
-			}
+	for _, chatID := range s.index.nameToChat[name] {
+		if chatID == viewerChatID {
+			continue
+		}
+		if event, _ := s.eventByCommand(chatID, name); event.IsVisibleTo(viewerChatID) {
+			return &event, chatID, nil
 		}
 	}
-
-	return nil, 0, errors.New("event not found")
+	return nil, 0, ErrEventNotFound
 }
 
 func (s *JSONStorage) EventExists(chatID int64, name string) bool {
@@ -200,28 +437,25 @@ func (s *JSONStorage) GetUser(chatID, userID int64) (*models.User, error) {
 	s.mu.RLock()
 	defer s.mu.RUnlock()
 
-	data, err := s.loadData()
-	if err != nil {
-		return nil, err
+	if s.loadErr != nil {
+		return nil, s.loadErr
 	}
-
-	for _, chat := range data {
-		if chat.ChatID == chatID {
-			for _, user := range chat.Users {
-				if user.UserID == userID {
-					return &user, nil
-				}
+	if chat := s.chat(chatID); chat != nil {
+		for _, user := range chat.Users {
+			if user.UserID == userID {
+				user.Events = append([]models.Event{}, user.Events...)
+				return &user, nil
 			}
 		}
 	}
-	return nil, errors.New("user not found")
+	return nil, ErrUserNotFound
 }
 
 func (s *JSONStorage) AddEventToUser(chatID, userID int64, event models.Event) error {
 	s.mu.Lock()
 	defer s.mu.Unlock()
 
-	data, err := s.loadData()
+	data, err := s.cloneData()
 	if err != nil {
 		return err
 	}
@@ -263,3 +497,440 @@ func (s *JSONStorage) AddEventToUser(chatID, userID int64, event models.Event) e
 
 	return s.saveData(data)
 }
+
+func (s *JSONStorage) AddUser(chatID, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.loadErr != nil {
+		return s.loadErr
+	}
+	if chat := s.chat(chatID); chat != nil {
+		for _, user := range chat.Users {
+			if user.UserID == userID {
+				return nil
+			}
+		}
+	}
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	user := models.User{UserID: userID, ChatID: chatID, Events: []models.Event{}}
+	if i, ok := s.index.chats[chatID]; ok {
+		data[i].Users = append(data[i].Users, user)
+	} else {
+		data = append(data, ChatData{
+			ChatID: chatID,
+			Events: []models.Event{},
+			Users:  []models.User{user},
+		})
+	}
+	return s.saveData(data)
+}
+
+func (s *JSONStorage) GetUserChatIDs(userID int64) ([]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	chatIDs := []int64{}
+	for _, chat := range s.data {
+		for _, user := range chat.Users {
+			if user.UserID == userID {
+				chatIDs = append(chatIDs, chat.ChatID)
+				break
+			}
+		}
+	}
+	return chatIDs, nil
+}
+
+func (s *JSONStorage) GetReminderDeliveries(chatID int64, eventID string) ([]models.ReminderDelivery, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	deliveries := []models.ReminderDelivery{}
+	if chat := s.chat(chatID); chat != nil {
+		for _, delivery := range chat.ReminderDeliveries {
+			if delivery.EventID == eventID {
+				deliveries = append(deliveries, delivery)
+			}
+		}
+	}
+	return deliveries, nil
+}
+
+// SaveReminderDelivery запоминает отправленное напоминание; повторная запись игнорируется
+func (s *JSONStorage) SaveReminderDelivery(chatID int64, delivery models.ReminderDelivery) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	for i, chat := range data {
+		if chat.ChatID != chatID {
+			continue
+		}
+		for _, existing := range chat.ReminderDeliveries {
+			if existing.EventID == delivery.EventID && existing.Occurrence == delivery.Occurrence && existing.Offset == delivery.Offset {
+				return nil
+			}
+		}
+		data[i].ReminderDeliveries = append(data[i].ReminderDeliveries, delivery)
+		return s.saveData(data)
+	}
+	return ErrEventNotFound
+}
+
+func (s *JSONStorage) GetChatSettings(chatID int64) (models.ChatSettings, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return models.ChatSettings{}, s.loadErr
+	}
+	if chat := s.chat(chatID); chat != nil {
+		return chat.Settings, nil
+	}
+	return models.ChatSettings{}, nil
+}
+
+func (s *JSONStorage) SaveChatSettings(chatID int64, settings models.ChatSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	for i, chat := range data {
+		if chat.ChatID == chatID {
+			data[i].Settings = settings
+			return s.saveData(data)
+		}
+	}
+	data = append(data, ChatData{
+		ChatID:   chatID,
+		Events:   []models.Event{},
+		Users:    []models.User{},
+		Settings: settings,
+	})
+	return s.saveData(data)
+}
+
+func (s *JSONStorage) GetConversation(chatID, userID int64) (*models.Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	if chat := s.chat(chatID); chat != nil {
+		for _, conversation := range chat.Conversations {
+			if conversation.UserID == userID {
+				return &conversation, nil
+			}
+		}
+	}
+	return nil, ErrConversationNotFound
+}
+
+func (s *JSONStorage) SaveConversation(conversation models.Conversation) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	for i, chat := range data {
+		if chat.ChatID != conversation.ChatID {
+			continue
+		}
+		for j, existing := range chat.Conversations {
+			if existing.UserID == conversation.UserID {
+				data[i].Conversations[j] = conversation
+				return s.saveData(data)
+			}
+		}
+		data[i].Conversations = append(data[i].Conversations, conversation)
+		return s.saveData(data)
+	}
+	data = append(data, ChatData{
+		ChatID:        conversation.ChatID,
+		Events:        []models.Event{},
+		Users:         []models.User{},
+		Conversations: []models.Conversation{conversation},
+	})
+	return s.saveData(data)
+}
+
+func (s *JSONStorage) DeleteConversation(chatID, userID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	for i, chat := range data {
+		if chat.ChatID != chatID {
+			continue
+		}
+		for j, conversation := range chat.Conversations {
+			if conversation.UserID == userID {
+				data[i].Conversations = append(chat.Conversations[:j], chat.Conversations[j+1:]...)
+				return s.saveData(data)
+			}
+		}
+	}
+	return ErrConversationNotFound
+}
+
+func (s *JSONStorage) GetConversations() ([]models.Conversation, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	conversations := []models.Conversation{}
+	for _, chat := range s.data {
+		conversations = append(conversations, chat.Conversations...)
+	}
+	return conversations, nil
+}
+
+func (s *JSONStorage) GetChatIDs() ([]int64, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	chatIDs := make([]int64, 0, len(s.data))
+	for _, chat := range s.data {
+		chatIDs = append(chatIDs, chat.ChatID)
+	}
+	return chatIDs, nil
+}
+
+func (s *JSONStorage) GetCatalog(name string) (*models.Catalog, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	catalog := s.catalog(name)
+	if catalog == nil {
+		return nil, ErrCatalogNotFound
+	}
+	result := *catalog
+	result.EventIDs = append([]string{}, catalog.EventIDs...)
+	return &result, nil
+}
+
+// SaveCatalog хранит каталог в данных чата-владельца; при смене владельца каталог переносится
+func (s *JSONStorage) SaveCatalog(catalog models.Catalog) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	if pos, ok := s.index.catalogs[catalog.Name]; ok {
+		if data[pos[0]].ChatID == catalog.OwnerChatID {
+			data[pos[0]].Catalogs[pos[1]] = catalog
+			return s.saveData(data)
+		}
+		catalogs := data[pos[0]].Catalogs
+		data[pos[0]].Catalogs = append(catalogs[:pos[1]], catalogs[pos[1]+1:]...)
+	}
+	if i, ok := s.index.chats[catalog.OwnerChatID]; ok {
+		data[i].Catalogs = append(data[i].Catalogs, catalog)
+	} else {
+		data = append(data, ChatData{
+			ChatID:   catalog.OwnerChatID,
+			Events:   []models.Event{},
+			Users:    []models.User{},
+			Catalogs: []models.Catalog{catalog},
+		})
+	}
+	return s.saveData(data)
+}
+
+// GetCatalogEvents возвращает события каталога; удалённые события пропускаются
+func (s *JSONStorage) GetCatalogEvents(name string) ([]models.Event, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	catalog := s.catalog(name)
+	if catalog == nil {
+		return nil, ErrCatalogNotFound
+	}
+	events := []models.Event{}
+	owner := s.chat(catalog.OwnerChatID)
+	for _, eventID := range catalog.EventIDs {
+		if j, ok := s.index.byID[eventKey{chatID: catalog.OwnerChatID, key: eventID}]; ok {
+			events = append(events, owner.Events[j])
+		}
+	}
+	return events, nil
+}
+
+func (s *JSONStorage) GetSubscriptions(chatID int64) ([]string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	if chat := s.chat(chatID); chat != nil {
+		return append([]string{}, chat.Subscriptions...), nil
+	}
+	return []string{}, nil
+}
+
+func (s *JSONStorage) Subscribe(chatID int64, catalog string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+	if _, ok := s.index.catalogs[catalog]; !ok {
+		return ErrCatalogNotFound
+	}
+
+	for i, chat := range data {
+		if chat.ChatID != chatID {
+			continue
+		}
+		for _, name := range chat.Subscriptions {
+			if name == catalog {
+				return nil
+			}
+		}
+		data[i].Subscriptions = append(data[i].Subscriptions, catalog)
+		return s.saveData(data)
+	}
+	data = append(data, ChatData{
+		ChatID:        chatID,
+		Events:        []models.Event{},
+		Users:         []models.User{},
+		Subscriptions: []string{catalog},
+	})
+	return s.saveData(data)
+}
+
+func (s *JSONStorage) Unsubscribe(chatID int64, catalog string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	for i, chat := range data {
+		if chat.ChatID != chatID {
+			continue
+		}
+		for j, name := range chat.Subscriptions {
+			if name == catalog {
+				data[i].Subscriptions = append(chat.Subscriptions[:j], chat.Subscriptions[j+1:]...)
+				return s.saveData(data)
+			}
+		}
+	}
+	return ErrSubscriptionNotFound
+}
+
+func (s *JSONStorage) GetPinnedCountdown(chatID int64) (*models.PinnedCountdown, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	if chat := s.chat(chatID); chat != nil && chat.Countdown != nil {
+		countdown := *chat.Countdown
+		return &countdown, nil
+	}
+	return nil, ErrCountdownNotFound
+}
+
+func (s *JSONStorage) SavePinnedCountdown(countdown models.PinnedCountdown) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	if i, ok := s.index.chats[countdown.ChatID]; ok {
+		data[i].Countdown = &countdown
+		return s.saveData(data)
+	}
+	data = append(data, ChatData{
+		ChatID:    countdown.ChatID,
+		Events:    []models.Event{},
+		Users:     []models.User{},
+		Countdown: &countdown,
+	})
+	return s.saveData(data)
+}
+
+func (s *JSONStorage) DeletePinnedCountdown(chatID int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	data, err := s.cloneData()
+	if err != nil {
+		return err
+	}
+
+	i, ok := s.index.chats[chatID]
+	if !ok || data[i].Countdown == nil {
+		return ErrCountdownNotFound
+	}
+	data[i].Countdown = nil
+	return s.saveData(data)
+}
+
+func (s *JSONStorage) GetPinnedCountdowns() ([]models.PinnedCountdown, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	if s.loadErr != nil {
+		return nil, s.loadErr
+	}
+	countdowns := []models.PinnedCountdown{}
+	for _, chat := range s.data {
+		if chat.Countdown != nil {
+			countdowns = append(countdowns, *chat.Countdown)
+		}
+	}
+	return countdowns, nil
+}
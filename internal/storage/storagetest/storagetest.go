@@ -0,0 +1,802 @@
+// Package storagetest содержит общий набор проверок, которому должна соответствовать
+// любая реализация storage.Storage.
+package storagetest
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+// Factory создаёт новое пустое хранилище для одной проверки. Освобождение ресурсов
+// (закрытие соединений, удаление файлов) регистрируется через t.Cleanup.
+type Factory func(t *testing.T) storage.Storage
+
+// Run прогоняет набор проверок; каждая проверка получает отдельное хранилище
+func Run(t *testing.T, newStorage Factory) {
+	tests := []struct {
+		name string
+		run  func(t *testing.T, store storage.Storage)
+	}{
+		{"SaveAndGet", testSaveAndGet},
+		{"DuplicateDetection", testDuplicateDetection},
+		{"ChatIsolation", testChatIsolation},
+		{"CrossChatLookupOrder", testCrossChatLookupOrder},
+		{"CrossChatVisibility", testCrossChatVisibility},
+		{"Aliases", testAliases},
+		{"UpdateAndDelete", testUpdateAndDelete},
+		{"EventStatus", testEventStatus},
+		{"UserEvents", testUserEvents},
+		{"UserChats", testUserChats},
+		{"ReminderDeliveries", testReminderDeliveries},
+		{"ChatSettings", testChatSettings},
+		{"Conversations", testConversations},
+		{"Catalogs", testCatalogs},
+		{"Subscriptions", testSubscriptions},
+		{"PinnedCountdowns", testPinnedCountdowns},
+		{"ConcurrentWriters", testConcurrentWriters},
+		{"ConcurrentDuplicates", testConcurrentDuplicates},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tt.run(t, newStorage(t))
+		})
+	}
+}
+
+func newEvent(chatID int64, name string) models.Event {
+	return models.Event{
+		EventID:     models.GenerateEventID(),
+		Name:        name,
+		Date:        "2030-06-01 18:00",
+		Description: "Описание " + name,
+		Status:      models.StatusActive,
+		ChatID:      chatID,
+	}
+}
+
+func mustSave(t *testing.T, store storage.Storage, event models.Event) models.Event {
+	t.Helper()
+	if err := store.SaveEvent(event.ChatID, event); err != nil {
+		t.Fatalf("Ошибка сохранения события %s в чате %d: %v", event.Name, event.ChatID, err)
+	}
+	return event
+}
+
+func testSaveAndGet(t *testing.T, store storage.Storage) {
+	event := newEvent(100, "party")
+	event.TimeZone = "Asia/Yekaterinburg"
+	event.Reminders = []string{"1d", "1h"}
+	event.Recurrence = &models.Recurrence{Frequency: models.FrequencyYearly, Interval: 1}
+	event.DisplayName = "Вечеринка у бабушки"
+	event.Mode = models.ModeCountUp
+	event.AnnounceMilestones = true
+	mustSave(t, store, event)
+
+	got, err := store.GetEvent(100, "party")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	if got.EventID != event.EventID || got.Date != event.Date || got.Description != event.Description ||
+		got.Status != event.Status || got.ChatID != 100 || got.TimeZone != event.TimeZone || got.DisplayName != event.DisplayName {
+		t.Errorf("Событие сохранено с искажениями: ожидалось %+v, получено %+v", event, *got)
+	}
+	if len(got.Reminders) != 2 || got.Recurrence == nil || got.Recurrence.Frequency != models.FrequencyYearly {
+		t.Errorf("Напоминания и повторение должны сохраняться, получено %+v", *got)
+	}
+	if !got.IsCountUp() || !got.AnnounceMilestones {
+		t.Errorf("Режим счёта прошедшего времени должен сохраняться, получено %q, %v", got.Mode, got.AnnounceMilestones)
+	}
+	if !store.EventExists(100, "party") {
+		t.Error("EventExists должен находить сохранённое событие")
+	}
+
+	if _, err := store.GetEvent(100, "missing"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound, получено %v", err)
+	}
+	if store.EventExists(100, "missing") {
+		t.Error("EventExists не должен находить несуществующее событие")
+	}
+
+	// Повторное сохранение с тем же EventID заменяет событие
+	event.Status = models.StatusOutdated
+	mustSave(t, store, event)
+	events, err := store.GetEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 1 || events[0].Status != models.StatusOutdated {
+		t.Errorf("Ожидалось одно устаревшее событие, получено %+v", events)
+	}
+}
+
+func testDuplicateDetection(t *testing.T, store storage.Storage) {
+	first := mustSave(t, store, newEvent(100, "party"))
+
+	duplicate := newEvent(100, "party")
+	if err := store.SaveEvent(100, duplicate); !errors.Is(err, storage.ErrDuplicateEvent) {
+		t.Errorf("Ожидалась ошибка ErrDuplicateEvent, получено %v", err)
+	}
+
+	// Переименование в занятое имя тоже отклоняется
+	second := mustSave(t, store, newEvent(100, "trip"))
+	second.Name = "party"
+	if err := store.UpdateEvent(100, second); !errors.Is(err, storage.ErrDuplicateEvent) {
+		t.Errorf("Ожидалась ошибка ErrDuplicateEvent при переименовании, получено %v", err)
+	}
+
+	// В другом чате то же имя допустимо
+	mustSave(t, store, newEvent(200, "party"))
+
+	got, err := store.GetEvent(100, "party")
+	if err != nil || got.EventID != first.EventID {
+		t.Errorf("Исходное событие не должно меняться, получено %+v, %v", got, err)
+	}
+	if trip, err := store.GetEvent(100, "trip"); err != nil || trip.EventID != second.EventID {
+		t.Errorf("Отклонённое переименование не должно менять событие, получено %+v, %v", trip, err)
+	}
+}
+
+func testChatIsolation(t *testing.T, store storage.Storage) {
+	party := mustSave(t, store, newEvent(100, "party"))
+	mustSave(t, store, newEvent(100, "trip"))
+	mustSave(t, store, newEvent(200, "meeting"))
+
+	events, err := store.GetEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 2 {
+		t.Errorf("В чате 100 ожидалось 2 события, получено %d", len(events))
+	}
+	for _, event := range events {
+		if event.ChatID != 100 {
+			t.Errorf("В списке чата 100 оказалось событие чата %d", event.ChatID)
+		}
+	}
+	if store.EventExists(200, "party") {
+		t.Error("Событие не должно быть видно в другом чате")
+	}
+	if events, err := store.GetEvents(300); err != nil || len(events) != 0 {
+		t.Errorf("У неизвестного чата не должно быть событий, получено %v, %v", events, err)
+	}
+
+	// Удаление по EventID работает только в своём чате
+	if err := store.DeleteEvent(200, party.EventID); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound при удалении из чужого чата, получено %v", err)
+	}
+	if !store.EventExists(100, "party") {
+		t.Error("Событие не должно удаляться из чужого чата")
+	}
+
+	all, err := store.GetAllEvents()
+	if err != nil {
+		t.Fatalf("Ошибка получения всех событий: %v", err)
+	}
+	if len(all) != 3 {
+		t.Errorf("Ожидалось 3 события во всех чатах, получено %d", len(all))
+	}
+}
+
+func newPublicEvent(chatID int64, name string) models.Event {
+	event := newEvent(chatID, name)
+	event.Visibility = models.VisibilityPublic
+	return event
+}
+
+// Поиск в других чатах просматривает чаты в порядке их появления
+func testCrossChatLookupOrder(t *testing.T, store storage.Storage) {
+	first := mustSave(t, store, newPublicEvent(100, "birthday"))
+	second := mustSave(t, store, newPublicEvent(200, "birthday"))
+	mustSave(t, store, newPublicEvent(300, "birthday"))
+
+	event, chatID, err := store.FindEventAcrossChats("birthday", 300)
+	if err != nil {
+		t.Fatalf("Ошибка поиска события: %v", err)
+	}
+	if chatID != 100 || event.EventID != first.EventID {
+		t.Errorf("Ожидалось событие из чата 100, найдено в чате %d", chatID)
+	}
+
+	// Исключённый чат пропускается
+	event, chatID, err = store.FindEventAcrossChats("birthday", 100)
+	if err != nil {
+		t.Fatalf("Ошибка поиска события: %v", err)
+	}
+	if chatID != 200 || event.EventID != second.EventID {
+		t.Errorf("Ожидалось событие из чата 200, найдено в чате %d", chatID)
+	}
+
+	// После удаления поиск переходит к следующему чату
+	if err := store.DeleteEvent(100, first.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	if _, chatID, err := store.FindEventAcrossChats("birthday", 300); err != nil || chatID != 200 {
+		t.Errorf("Ожидалось событие из чата 200, найдено в чате %d, %v", chatID, err)
+	}
+
+	if _, _, err := store.FindEventAcrossChats("birthday", 0); err != nil {
+		t.Errorf("Событие должно находиться без исключённого чата: %v", err)
+	}
+	if _, _, err := store.FindEventAcrossChats("unknown", 100); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound, получено %v", err)
+	}
+}
+
+// Поиск в других чатах находит только публичные события и события, открытые для чата
+func testCrossChatVisibility(t *testing.T, store storage.Storage) {
+	mustSave(t, store, newEvent(100, "surprise"))
+	shared := newEvent(200, "surprise")
+	shared.Visibility = models.VisibilityShared
+	shared.SharedWith = []int64{300, 400}
+	shared = mustSave(t, store, shared)
+
+	loaded, err := store.GetEvent(200, "surprise")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	if loaded.Visibility != models.VisibilityShared || fmt.Sprint(loaded.SharedWith) != "[300 400]" {
+		t.Errorf("Видимость сохранена неверно: %s %v", loaded.Visibility, loaded.SharedWith)
+	}
+
+	// Приватное событие чата 100 пропускается, открытое событие чата 200 видно чату 400
+	event, chatID, err := store.FindEventAcrossChats("surprise", 400)
+	if err != nil {
+		t.Fatalf("Ошибка поиска события: %v", err)
+	}
+	if chatID != 200 || event.EventID != shared.EventID {
+		t.Errorf("Ожидалось событие из чата 200, найдено в чате %d", chatID)
+	}
+	// ID 30 - префикс 300, но не он сам
+	for _, viewer := range []int64{30, 500, 100} {
+		if _, _, err := store.FindEventAcrossChats("surprise", viewer); !errors.Is(err, storage.ErrEventNotFound) {
+			t.Errorf("Событие не должно быть видно чату %d, получено %v", viewer, err)
+		}
+	}
+
+	shared.Visibility = models.VisibilityPrivate
+	shared.SharedWith = nil
+	if err := store.UpdateEvent(200, shared); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	if _, _, err := store.FindEventAcrossChats("surprise", 400); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Приватное событие не должно находиться, получено %v", err)
+	}
+}
+
+// Событие находится и по псевдонимам; совпадение с именем важнее совпадения с псевдонимом
+func testAliases(t *testing.T, store storage.Storage) {
+	newYear := newPublicEvent(100, "new_year")
+	newYear.Aliases = []string{"ny", "novyy_god"}
+	newYear = mustSave(t, store, newYear)
+
+	for _, name := range []string{"new_year", "ny", "novyy_god"} {
+		event, err := store.GetEvent(100, name)
+		if err != nil {
+			t.Fatalf("Событие должно находиться по команде %s: %v", name, err)
+		}
+		if event.EventID != newYear.EventID || fmt.Sprint(event.Aliases) != "[ny novyy_god]" {
+			t.Errorf("По команде %s найдено событие %s с псевдонимами %v", name, event.Name, event.Aliases)
+		}
+	}
+	if !store.EventExists(100, "ny") {
+		t.Error("EventExists должен учитывать псевдонимы")
+	}
+	// Псевдоним ищется целиком, с учётом регистра и без шаблонов LIKE
+	for _, name := range []string{"n", "NY", "n_", "%", "ny,novyy_god"} {
+		if _, err := store.GetEvent(100, name); !errors.Is(err, storage.ErrEventNotFound) {
+			t.Errorf("Команда %q не должна находить событие, получено %v", name, err)
+		}
+	}
+	if _, err := store.GetEvent(200, "ny"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Псевдоним не должен находиться в другом чате, получено %v", err)
+	}
+
+	event, chatID, err := store.FindEventAcrossChats("novyy_god", 200)
+	if err != nil || chatID != 100 || event.EventID != newYear.EventID {
+		t.Errorf("Публичное событие должно находиться по псевдониму в другом чате: %d, %v", chatID, err)
+	}
+
+	// Имя события важнее псевдонима другого события
+	ny := mustSave(t, store, newEvent(100, "ny"))
+	if event, err := store.GetEvent(100, "ny"); err != nil || event.EventID != ny.EventID {
+		t.Errorf("Ожидалось событие с именем ny, получено %v", err)
+	}
+
+	newYear.Aliases = []string{"novyy_god"}
+	if err := store.UpdateEvent(100, newYear); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	if err := store.DeleteEvent(100, ny.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	if _, err := store.GetEvent(100, "ny"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Удалённый псевдоним не должен находиться, получено %v", err)
+	}
+	if event, err := store.GetEvent(100, "novyy_god"); err != nil || fmt.Sprint(event.Aliases) != "[novyy_god]" {
+		t.Errorf("Оставшийся псевдоним должен находиться, получено %v", err)
+	}
+}
+
+func testUpdateAndDelete(t *testing.T, store storage.Storage) {
+	event := mustSave(t, store, newEvent(100, "party"))
+
+	event.Name = "big_party"
+	event.Description = "Новое описание"
+	if err := store.UpdateEvent(100, event); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	if store.EventExists(100, "party") {
+		t.Error("После переименования старое имя должно освободиться")
+	}
+	got, err := store.GetEvent(100, "big_party")
+	if err != nil || got.Description != "Новое описание" {
+		t.Errorf("Ожидалось обновлённое событие, получено %+v, %v", got, err)
+	}
+
+	missing := newEvent(100, "missing")
+	if err := store.UpdateEvent(100, missing); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound при обновлении, получено %v", err)
+	}
+
+	if err := store.DeleteEvent(100, event.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	if store.EventExists(100, "big_party") {
+		t.Error("Удалённое событие не должно находиться")
+	}
+	if err := store.DeleteEvent(100, event.EventID); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound при повторном удалении, получено %v", err)
+	}
+}
+
+func testEventStatus(t *testing.T, store storage.Storage) {
+	stale := mustSave(t, store, newEvent(100, "party"))
+	if err := store.AddEventToUser(100, 1, stale); err != nil {
+		t.Fatalf("Ошибка привязки события к пользователю: %v", err)
+	}
+
+	// Событие переименовано после того, как фоновое обновление его прочитало
+	renamed := stale
+	renamed.Name = "big_party"
+	renamed.Description = "Новое описание"
+	if err := store.UpdateEvent(100, renamed); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	if err := store.SetEventStatus(100, stale.EventID, models.StatusOutdated); err != nil {
+		t.Fatalf("Ошибка изменения статуса: %v", err)
+	}
+
+	got, err := store.GetEvent(100, "big_party")
+	if err != nil || got.Status != models.StatusOutdated || got.Description != "Новое описание" {
+		t.Errorf("Статус должен измениться без отката других полей, получено %+v, %v", got, err)
+	}
+	user, err := store.GetUser(100, 1)
+	if err != nil || len(user.Events) != 1 || user.Events[0].Status != models.StatusOutdated {
+		t.Errorf("Статус должен измениться и у пользователя, получено %+v, %v", user, err)
+	}
+
+	if err := store.SetEventStatus(100, models.GenerateEventID(), models.StatusOutdated); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound, получено %v", err)
+	}
+	if err := store.SetEventStatus(200, stale.EventID, models.StatusOutdated); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Событие другого чата не должно меняться, получено %v", err)
+	}
+}
+
+func testUserEvents(t *testing.T, store storage.Storage) {
+	if _, err := store.GetUser(100, 1); !errors.Is(err, storage.ErrUserNotFound) {
+		t.Errorf("Ожидалась ошибка ErrUserNotFound, получено %v", err)
+	}
+
+	party := mustSave(t, store, newEvent(100, "party"))
+	trip := mustSave(t, store, newEvent(100, "trip"))
+	for _, event := range []models.Event{party, trip} {
+		if err := store.AddEventToUser(100, 1, event); err != nil {
+			t.Fatalf("Ошибка добавления события пользователю: %v", err)
+		}
+	}
+
+	user, err := store.GetUser(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if user.UserID != 1 || user.ChatID != 100 || len(user.Events) != 2 {
+		t.Errorf("Ожидался пользователь с двумя событиями, получено %+v", *user)
+	}
+	if _, err := store.GetUser(200, 1); !errors.Is(err, storage.ErrUserNotFound) {
+		t.Errorf("Пользователь не должен быть виден в другом чате, получено %v", err)
+	}
+
+	// Изменение события видно в списке пользователя, удаление убирает его оттуда
+	party.Description = "Обновлено"
+	if err := store.UpdateEvent(100, party); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	if err := store.DeleteEvent(100, trip.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	user, err = store.GetUser(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if len(user.Events) != 1 || user.Events[0].EventID != party.EventID || user.Events[0].Description != "Обновлено" {
+		t.Errorf("Ожидалось одно обновлённое событие пользователя, получено %+v", user.Events)
+	}
+}
+
+func testUserChats(t *testing.T, store storage.Storage) {
+	if chatIDs, err := store.GetUserChatIDs(1); err != nil || len(chatIDs) != 0 {
+		t.Errorf("Ожидался пустой список чатов, получено %v, %v", chatIDs, err)
+	}
+
+	party := mustSave(t, store, newEvent(100, "party"))
+	if err := store.AddEventToUser(100, 1, party); err != nil {
+		t.Fatalf("Ошибка добавления события пользователю: %v", err)
+	}
+	for _, chatID := range []int64{100, 200, 200} {
+		if err := store.AddUser(chatID, 1); err != nil {
+			t.Fatalf("Ошибка добавления участника: %v", err)
+		}
+	}
+	if err := store.AddUser(300, 2); err != nil {
+		t.Fatalf("Ошибка добавления участника: %v", err)
+	}
+
+	chatIDs, err := store.GetUserChatIDs(1)
+	if err != nil {
+		t.Fatalf("Ошибка получения чатов пользователя: %v", err)
+	}
+	if len(chatIDs) != 2 || chatIDs[0] != 100 || chatIDs[1] != 200 {
+		t.Errorf("Ожидались чаты 100 и 200, получено %v", chatIDs)
+	}
+	// Повторное добавление не сбрасывает события пользователя
+	if user, err := store.GetUser(100, 1); err != nil || len(user.Events) != 1 {
+		t.Errorf("События участника должны сохраниться, получено %+v, %v", user, err)
+	}
+	if user, err := store.GetUser(200, 1); err != nil || len(user.Events) != 0 {
+		t.Errorf("Ожидался участник без событий, получено %+v, %v", user, err)
+	}
+}
+
+func testReminderDeliveries(t *testing.T, store storage.Storage) {
+	event := mustSave(t, store, newEvent(100, "party"))
+	delivery := models.ReminderDelivery{
+		EventID:    event.EventID,
+		Occurrence: event.Date,
+		Offset:     "1d",
+		SentAt:     time.Date(2030, 5, 31, 18, 0, 0, 0, time.UTC),
+	}
+	for i := 0; i < 2; i++ {
+		if err := store.SaveReminderDelivery(100, delivery); err != nil {
+			t.Fatalf("Ошибка сохранения отправленного напоминания: %v", err)
+		}
+	}
+
+	deliveries, err := store.GetReminderDeliveries(100, event.EventID)
+	if err != nil {
+		t.Fatalf("Ошибка получения напоминаний: %v", err)
+	}
+	if len(deliveries) != 1 || deliveries[0].Offset != "1d" || !deliveries[0].SentAt.Equal(delivery.SentAt) {
+		t.Errorf("Повторная запись напоминания должна игнорироваться, получено %+v", deliveries)
+	}
+
+	if err := store.DeleteEvent(100, event.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	if deliveries, _ := store.GetReminderDeliveries(100, event.EventID); len(deliveries) != 0 {
+		t.Errorf("Напоминания удалённого события должны удаляться, получено %+v", deliveries)
+	}
+}
+
+func testChatSettings(t *testing.T, store storage.Storage) {
+	settings, err := store.GetChatSettings(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения настроек: %v", err)
+	}
+	if settings != (models.ChatSettings{}) {
+		t.Errorf("У неизвестного чата ожидались настройки по умолчанию, получено %+v", settings)
+	}
+
+	if err := store.SaveChatSettings(100, models.ChatSettings{TimeZone: "Asia/Yekaterinburg"}); err != nil {
+		t.Fatalf("Ошибка сохранения настроек: %v", err)
+	}
+	mustSave(t, store, newEvent(100, "party"))
+	settings, err = store.GetChatSettings(100)
+	if err != nil || settings.TimeZone != "Asia/Yekaterinburg" {
+		t.Errorf("Ожидался часовой пояс Asia/Yekaterinburg, получено %+v, %v", settings, err)
+	}
+	if settings, _ := store.GetChatSettings(200); settings.TimeZone != "" {
+		t.Errorf("Настройки не должны переходить в другой чат, получено %+v", settings)
+	}
+
+	settings.CreatePolicy = models.PolicyAdmins
+	settings.EditPolicy = models.PolicyEveryone
+	if err := store.SaveChatSettings(100, settings); err != nil {
+		t.Fatalf("Ошибка сохранения настроек: %v", err)
+	}
+	loaded, err := store.GetChatSettings(100)
+	if err != nil || loaded != settings {
+		t.Errorf("Ожидались настройки %+v, получено %+v, %v", settings, loaded, err)
+	}
+}
+
+func testConversations(t *testing.T, store storage.Storage) {
+	if _, err := store.GetConversation(100, 1); !errors.Is(err, storage.ErrConversationNotFound) {
+		t.Errorf("Ожидалась ошибка ErrConversationNotFound, получено %v", err)
+	}
+
+	conversation := models.Conversation{
+		ChatID:    100,
+		UserID:    1,
+		Step:      models.StepDate,
+		Name:      "party",
+		UpdatedAt: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := store.SaveConversation(conversation); err != nil {
+		t.Fatalf("Ошибка сохранения диалога: %v", err)
+	}
+	conversation.Step = models.StepTime
+	conversation.Date = "2030-06-01"
+	if err := store.SaveConversation(conversation); err != nil {
+		t.Fatalf("Ошибка обновления диалога: %v", err)
+	}
+	if err := store.SaveConversation(models.Conversation{ChatID: 200, UserID: 1, Step: models.StepName, UpdatedAt: conversation.UpdatedAt}); err != nil {
+		t.Fatalf("Ошибка сохранения диалога: %v", err)
+	}
+
+	got, err := store.GetConversation(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения диалога: %v", err)
+	}
+	if got.Step != models.StepTime || got.Date != "2030-06-01" || got.Name != "party" || !got.UpdatedAt.Equal(conversation.UpdatedAt) {
+		t.Errorf("Диалог сохранён с искажениями: %+v", *got)
+	}
+	if conversations, err := store.GetConversations(); err != nil || len(conversations) != 2 {
+		t.Errorf("Ожидалось 2 диалога, получено %d, %v", len(conversations), err)
+	}
+
+	if err := store.DeleteConversation(100, 1); err != nil {
+		t.Fatalf("Ошибка удаления диалога: %v", err)
+	}
+	if err := store.DeleteConversation(100, 1); !errors.Is(err, storage.ErrConversationNotFound) {
+		t.Errorf("Ожидалась ошибка ErrConversationNotFound, получено %v", err)
+	}
+	if _, err := store.GetConversation(200, 1); err != nil {
+		t.Errorf("Диалог в другом чате не должен удаляться: %v", err)
+	}
+}
+
+// Одновременные записи разных событий не должны терять данные
+func testPinnedCountdowns(t *testing.T, store storage.Storage) {
+	if _, err := store.GetPinnedCountdown(100); !errors.Is(err, storage.ErrCountdownNotFound) {
+		t.Errorf("Ожидалась ошибка ErrCountdownNotFound, получено %v", err)
+	}
+
+	countdown := models.PinnedCountdown{
+		ChatID:    100,
+		MessageID: 42,
+		Size:      5,
+		Text:      "Ближайшие события",
+		UpdatedAt: time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC),
+	}
+	if err := store.SavePinnedCountdown(countdown); err != nil {
+		t.Fatalf("Ошибка сохранения отсчёта: %v", err)
+	}
+	countdown.MessageID = 43
+	countdown.Text = "Обновлённый отсчёт"
+	if err := store.SavePinnedCountdown(countdown); err != nil {
+		t.Fatalf("Ошибка обновления отсчёта: %v", err)
+	}
+	if err := store.SavePinnedCountdown(models.PinnedCountdown{ChatID: 200, MessageID: 7, Size: 3, UpdatedAt: countdown.UpdatedAt}); err != nil {
+		t.Fatalf("Ошибка сохранения отсчёта: %v", err)
+	}
+
+	got, err := store.GetPinnedCountdown(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения отсчёта: %v", err)
+	}
+	if got.MessageID != 43 || got.Size != 5 || got.Text != "Обновлённый отсчёт" || !got.UpdatedAt.Equal(countdown.UpdatedAt) {
+		t.Errorf("Отсчёт сохранён с искажениями: %+v", *got)
+	}
+	if countdowns, err := store.GetPinnedCountdowns(); err != nil || len(countdowns) != 2 {
+		t.Errorf("Ожидалось 2 отсчёта, получено %d, %v", len(countdowns), err)
+	}
+
+	if err := store.DeletePinnedCountdown(100); err != nil {
+		t.Fatalf("Ошибка удаления отсчёта: %v", err)
+	}
+	if err := store.DeletePinnedCountdown(100); !errors.Is(err, storage.ErrCountdownNotFound) {
+		t.Errorf("Ожидалась ошибка ErrCountdownNotFound, получено %v", err)
+	}
+	if _, err := store.GetPinnedCountdown(200); err != nil {
+		t.Errorf("Отсчёт другого чата не должен удаляться: %v", err)
+	}
+}
+
+func testConcurrentWriters(t *testing.T, store storage.Storage) {
+	const writers = 4
+	const perWriter = 10
+
+	var wg sync.WaitGroup
+	errs := make(chan error, writers*perWriter)
+	for w := 0; w < writers; w++ {
+		wg.Add(1)
+		go func(w int) {
+			defer wg.Done()
+			for i := 0; i < perWriter; i++ {
+				chatID := int64(100 + w%2)
+				event := newEvent(chatID, fmt.Sprintf("event_%d_%d", w, i))
+				if err := store.SaveEvent(chatID, event); err != nil {
+					errs <- err
+					continue
+				}
+				if err := store.AddEventToUser(chatID, int64(w), event); err != nil {
+					errs <- err
+				}
+			}
+		}(w)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Ошибка одновременной записи: %v", err)
+	}
+
+	all, err := store.GetAllEvents()
+	if err != nil {
+		t.Fatalf("Ошибка получения всех событий: %v", err)
+	}
+	if len(all) != writers*perWriter {
+		t.Errorf("Ожидалось %d событий, получено %d", writers*perWriter, len(all))
+	}
+	for w := 0; w < writers; w++ {
+		user, err := store.GetUser(int64(100+w%2), int64(w))
+		if err != nil || len(user.Events) != perWriter {
+			t.Errorf("У пользователя %d ожидалось %d событий, получено %+v, %v", w, perWriter, user, err)
+		}
+	}
+}
+
+// Из одновременных попыток создать событие с одним именем успешна ровно одна
+func testConcurrentDuplicates(t *testing.T, store storage.Storage) {
+	const attempts = 8
+
+	var wg sync.WaitGroup
+	results := make(chan error, attempts)
+	for i := 0; i < attempts; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results <- store.SaveEvent(100, newEvent(100, "party"))
+		}()
+	}
+	wg.Wait()
+	close(results)
+
+	saved := 0
+	for err := range results {
+		switch {
+		case err == nil:
+			saved++
+		case !errors.Is(err, storage.ErrDuplicateEvent):
+			t.Errorf("Ожидалась ошибка ErrDuplicateEvent, получено %v", err)
+		}
+	}
+	if saved != 1 {
+		t.Errorf("Ожидалось ровно одно сохранённое событие, сохранено %d", saved)
+	}
+	if events, _ := store.GetEvents(100); len(events) != 1 {
+		t.Errorf("В чате должно остаться одно событие, получено %d", len(events))
+	}
+}
+
+// Каталог хранит события владельца в порядке публикации; удалённые события из него пропадают
+func testCatalogs(t *testing.T, store storage.Storage) {
+	if _, err := store.GetCatalog("family"); !errors.Is(err, storage.ErrCatalogNotFound) {
+		t.Errorf("Ожидалась ошибка ErrCatalogNotFound, получено %v", err)
+	}
+	if _, err := store.GetCatalogEvents("family"); !errors.Is(err, storage.ErrCatalogNotFound) {
+		t.Errorf("Ожидалась ошибка ErrCatalogNotFound, получено %v", err)
+	}
+
+	party := mustSave(t, store, newEvent(100, "party"))
+	birthday := mustSave(t, store, newEvent(100, "birthday"))
+	foreign := mustSave(t, store, newEvent(200, "foreign"))
+
+	catalog := models.Catalog{Name: "family", OwnerChatID: 100, EventIDs: []string{birthday.EventID, party.EventID, foreign.EventID}}
+	if err := store.SaveCatalog(catalog); err != nil {
+		t.Fatalf("Ошибка сохранения каталога: %v", err)
+	}
+	loaded, err := store.GetCatalog("family")
+	if err != nil {
+		t.Fatalf("Ошибка получения каталога: %v", err)
+	}
+	if loaded.OwnerChatID != 100 || len(loaded.EventIDs) != 3 {
+		t.Errorf("Каталог сохранён неверно: %+v", loaded)
+	}
+
+	// Чужие события в каталоге не показываются
+	events, err := store.GetCatalogEvents("family")
+	if err != nil {
+		t.Fatalf("Ошибка получения событий каталога: %v", err)
+	}
+	if len(events) != 2 || events[0].EventID != birthday.EventID || events[1].EventID != party.EventID {
+		t.Errorf("Ожидались birthday и party в порядке публикации, получено %+v", events)
+	}
+
+	if err := store.DeleteEvent(100, birthday.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	events, err = store.GetCatalogEvents("family")
+	if err != nil {
+		t.Fatalf("Ошибка получения событий каталога: %v", err)
+	}
+	if len(events) != 1 || events[0].EventID != party.EventID {
+		t.Errorf("Удалённое событие должно пропасть из каталога, получено %+v", events)
+	}
+
+	// Замена списка событий и перенос каталога другому чату
+	catalog = models.Catalog{Name: "family", OwnerChatID: 200, EventIDs: []string{foreign.EventID}}
+	if err := store.SaveCatalog(catalog); err != nil {
+		t.Fatalf("Ошибка сохранения каталога: %v", err)
+	}
+	events, err = store.GetCatalogEvents("family")
+	if err != nil {
+		t.Fatalf("Ошибка получения событий каталога: %v", err)
+	}
+	if len(events) != 1 || events[0].EventID != foreign.EventID {
+		t.Errorf("Ожидалось событие нового владельца, получено %+v", events)
+	}
+}
+
+func testSubscriptions(t *testing.T, store storage.Storage) {
+	if err := store.Subscribe(200, "family"); !errors.Is(err, storage.ErrCatalogNotFound) {
+		t.Errorf("Подписка на несуществующий каталог: ожидалась ErrCatalogNotFound, получено %v", err)
+	}
+
+	mustSave(t, store, newEvent(100, "party"))
+	for _, name := range []string{"family", "friends"} {
+		if err := store.SaveCatalog(models.Catalog{Name: name, OwnerChatID: 100}); err != nil {
+			t.Fatalf("Ошибка сохранения каталога: %v", err)
+		}
+	}
+	for _, name := range []string{"friends", "family", "friends"} {
+		if err := store.Subscribe(200, name); err != nil {
+			t.Fatalf("Ошибка подписки: %v", err)
+		}
+	}
+	subscriptions, err := store.GetSubscriptions(200)
+	if err != nil {
+		t.Fatalf("Ошибка получения подписок: %v", err)
+	}
+	if fmt.Sprint(subscriptions) != "[friends family]" {
+		t.Errorf("Ожидались подписки [friends family] в порядке подписки, получено %v", subscriptions)
+	}
+	if subscriptions, err := store.GetSubscriptions(300); err != nil || len(subscriptions) != 0 {
+		t.Errorf("У чата без подписок ожидался пустой список, получено %v, %v", subscriptions, err)
+	}
+
+	if err := store.Unsubscribe(200, "friends"); err != nil {
+		t.Fatalf("Ошибка отписки: %v", err)
+	}
+	if err := store.Unsubscribe(200, "friends"); !errors.Is(err, storage.ErrSubscriptionNotFound) {
+		t.Errorf("Ожидалась ошибка ErrSubscriptionNotFound, получено %v", err)
+	}
+	if subscriptions, _ := store.GetSubscriptions(200); fmt.Sprint(subscriptions) != "[family]" {
+		t.Errorf("Ожидалась подписка [family], получено %v", subscriptions)
+	}
+
+	chatIDs, err := store.GetChatIDs()
+	if err != nil {
+		t.Fatalf("Ошибка получения чатов: %v", err)
+	}
+	if fmt.Sprint(chatIDs) != "[100 200]" {
+		t.Errorf("Ожидались чаты [100 200] в порядке появления, получено %v", chatIDs)
+	}
+}
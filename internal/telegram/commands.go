@@ -0,0 +1,50 @@
+// Package telegram содержит разбор команд Telegram, общий для обработчиков бота
+package telegram
+
+import (
+	"strings"
+
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+// ParseCommand отделяет от команды упоминание бота: "/list@family_bot" -> "/list", "family_bot"
+func ParseCommand(word string) (string, string) {
+	command, mention, _ := strings.Cut(word, "@")
+	return command, mention
+}
+
+// CommandArgs разбивает текст команды на слова, удаляя @bot_username только из имени команды
+func CommandArgs(text string) []string {
+	parts := strings.Fields(text)
+	if len(parts) > 0 {
+		parts[0], _ = ParseCommand(parts[0])
+	}
+	return parts
+}
+
+// CommandMatcher проверяет, что сообщение - одна из команд commands без упоминания или с упоминанием
+// botName. Команда другого бота ("/delete@other_bot") в группе адресована не нам и не совпадает.
+// В отличие от bot.MatchTypePrefix, "/new" не совпадает с "/new_year", а "/list" - с "/listen":
+// go-telegram/bot вызывает первый подходящий обработчик, и команда события не дошла бы до него.
+func CommandMatcher(botName string, commands ...string) bot.MatchFunc {
+	return func(update *tgmodels.Update) bool {
+		if update.Message == nil {
+			return false
+		}
+		parts := strings.Fields(update.Message.Text)
+		if len(parts) == 0 {
+			return false
+		}
+		command, mention := ParseCommand(parts[0])
+		if mention != "" && !strings.EqualFold(mention, botName) {
+			return false
+		}
+		for _, candidate := range commands {
+			if command == candidate {
+				return true
+			}
+		}
+		return false
+	}
+}
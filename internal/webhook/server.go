@@ -0,0 +1,176 @@
+package webhook
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+	"go.uber.org/zap"
+)
+
+// SecretTokenHeader - заголовок, в котором Telegram передаёт секрет, указанный в setWebhook
+const SecretTokenHeader = "X-Telegram-Bot-Api-Secret-Token"
+
+// HealthPath отвечает 200 OK для проверок балансировщика или оркестратора
+const HealthPath = "/healthz"
+
+// Options настраивает приём обновлений через webhook
+type Options struct {
+	// ListenAddr - адрес HTTP-сервера, например ":8080"
+	ListenAddr string
+	// Path - путь, на который Telegram присылает обновления
+	Path string
+	// PublicURL - внешний адрес бота (https://bot.example.com); к нему добавляется Path
+	PublicURL string
+	// SecretToken сверяется с заголовком SecretTokenHeader каждого запроса
+	SecretToken string
+	// CertFile и KeyFile включают TLS на самом сервере; за обратным прокси их не задают
+	CertFile string
+	KeyFile  string
+	// SelfSigned загружает CertFile в Telegram при вызове setWebhook
+	SelfSigned bool
+}
+
+// WebhookURL возвращает адрес, который регистрируется в Telegram
+func (o Options) WebhookURL() string {
+	return strings.TrimRight(o.PublicURL, "/") + o.Path
+}
+
+// GenerateSecretToken создаёт случайный секрет из допустимых для Telegram символов
+func GenerateSecretToken() string {
+	bytes := make([]byte, 32)
+	rand.Read(bytes)
+	return hex.EncodeToString(bytes)
+}
+
+// Handler принимает обновления только методом POST и только с верным секретом,
+// остальные запросы отклоняются до передачи в updates
+func Handler(secretToken string, updates http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			w.Header().Set("Allow", http.MethodPost)
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		received := r.Header.Get(SecretTokenHeader)
+		if secretToken == "" || subtle.ConstantTimeCompare([]byte(received), []byte(secretToken)) != 1 {
+			http.Error(w, "invalid secret token", http.StatusUnauthorized)
+			return
+		}
+		updates.ServeHTTP(w, r)
+	})
+}
+
+// Server - HTTP-сервер, передающий обновления Telegram в обработчик бота
+type Server struct {
+	options  Options
+	server   *http.Server
+	listener net.Listener
+	logger   *zap.Logger
+}
+
+// NewServer создаёт сервер; updates обычно равен (*bot.Bot).WebhookHandler()
+func NewServer(options Options, updates http.Handler) *Server {
+	logger := zap.L()
+	mux := http.NewServeMux()
+	mux.Handle(options.Path, Handler(options.SecretToken, updates))
+	mux.HandleFunc(HealthPath, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("ok"))
+	})
+	return &Server{
+		options: options,
+		server: &http.Server{
+			Addr:              options.ListenAddr,
+			Handler:           mux,
+			ReadHeaderTimeout: 10 * time.Second,
+		},
+		logger: logger,
+	}
+}
+
+// Listen занимает адрес ListenAddr; после него Addr возвращает фактический адрес
+func (s *Server) Listen() error {
+	listener, err := net.Listen("tcp", s.options.ListenAddr)
+	if err != nil {
+		return err
+	}
+	s.listener = listener
+	return nil
+}
+
+// Addr возвращает адрес, на котором слушает сервер
+func (s *Server) Addr() net.Addr {
+	if s.listener == nil {
+		return nil
+	}
+	return s.listener.Addr()
+}
+
+// Serve обрабатывает запросы до вызова Shutdown. TLS включается, если заданы CertFile и KeyFile.
+func (s *Server) Serve() error {
+	if s.listener == nil {
+		if err := s.Listen(); err != nil {
+			return err
+		}
+	}
+	s.logger.Info("Webhook-сервер запущен",
+		zap.String("addr", s.listener.Addr().String()),
+		zap.String("path", s.options.Path),
+		zap.Bool("tls", s.options.CertFile != ""))
+
+	var err error
+	if s.options.CertFile != "" && s.options.KeyFile != "" {
+		err = s.server.ServeTLS(s.listener, s.options.CertFile, s.options.KeyFile)
+	} else {
+		err = s.server.Serve(s.listener)
+	}
+	if errors.Is(err, http.ErrServerClosed) {
+		return nil
+	}
+	return err
+}
+
+// Shutdown останавливает приём запросов, дожидаясь уже принятых
+func (s *Server) Shutdown(ctx context.Context) error {
+	return s.server.Shutdown(ctx)
+}
+
+// Registrar регистрирует webhook в Telegram; реализуется *bot.Bot
+type Registrar interface {
+	SetWebhook(ctx context.Context, params *bot.SetWebhookParams) (bool, error)
+	DeleteWebhook(ctx context.Context, params *bot.DeleteWebhookParams) (bool, error)
+}
+
+// Register вызывает setWebhook с адресом и секретом из options
+func Register(ctx context.Context, registrar Registrar, options Options) error {
+	params := &bot.SetWebhookParams{
+		URL:         options.WebhookURL(),
+		SecretToken: options.SecretToken,
+	}
+	if options.SelfSigned && options.CertFile != "" {
+		certificate, err := os.Open(options.CertFile)
+		if err != nil {
+			return err
+		}
+		defer certificate.Close()
+		params.Certificate = &tgmodels.InputFileUpload{Filename: filepath.Base(options.CertFile), Data: certificate}
+	}
+	_, err := registrar.SetWebhook(ctx, params)
+	return err
+}
+
+// Unregister вызывает deleteWebhook, чтобы Telegram перестал присылать обновления
+func Unregister(ctx context.Context, registrar Registrar) error {
+	_, err := registrar.DeleteWebhook(ctx, &bot.DeleteWebhookParams{})
+	return err
+}
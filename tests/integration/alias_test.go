@@ -0,0 +1,105 @@
+package integration
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestEventAliases(t *testing.T) {
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+
+	event, err := eventService.AddEvent(100, models.Event{Name: "new_year", Date: "2030-12-31 23:59"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if _, err := eventService.AddAlias(100, "new_year", "ny"); err != nil {
+		t.Fatalf("Ошибка добавления псевдонима: %v", err)
+	}
+	// Псевдоним на русском транслитерируется, событие можно указать псевдонимом
+	updated, err := eventService.AddAlias(100, "ny", "Новый год")
+	if err != nil {
+		t.Fatalf("Ошибка добавления псевдонима: %v", err)
+	}
+	if fmt.Sprint(updated.Aliases) != "[ny novyy_god]" {
+		t.Errorf("Ожидались псевдонимы [ny novyy_god], получено %v", updated.Aliases)
+	}
+	for _, name := range []string{"new_year", "ny", "novyy_god"} {
+		found, err := eventService.GetEvent(100, name)
+		if err != nil || found.EventID != event.EventID {
+			t.Errorf("Событие должно находиться по команде %s, получено %v", name, err)
+		}
+	}
+
+	// Имена и псевдонимы уникальны в пределах чата
+	if _, err := eventService.AddAlias(100, "new_year", "ny"); err == nil {
+		t.Error("Повторный псевдоним должен отклоняться")
+	}
+	if err := eventService.CreateEvent(100, "ny", "2031-01-01", ""); err == nil {
+		t.Error("Имя события, совпадающее с псевдонимом, должно отклоняться")
+	}
+	if err := eventService.CreateEvent(100, "birthday", "2031-01-01", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if _, err := eventService.AddAlias(100, "birthday", "novyy_god"); err == nil {
+		t.Error("Псевдоним другого события должен отклоняться")
+	}
+	if _, err := eventService.AddAlias(100, "birthday", "new_year"); err == nil {
+		t.Error("Псевдоним, совпадающий с именем другого события, должен отклоняться")
+	}
+	if _, err := eventService.AddAlias(100, "birthday", "a b\nc"); err == nil {
+		t.Error("Некорректный псевдоним должен отклоняться")
+	}
+	// В другом чате та же команда свободна
+	if err := eventService.CreateEvent(200, "ny", "2031-01-01", ""); err != nil {
+		t.Errorf("Псевдоним не должен занимать имя в другом чате: %v", err)
+	}
+
+	// Русское имя, транслитерация которого занята псевдонимом, получает номер
+	party, err := eventService.AddEvent(100, models.Event{Name: "Новый год", Date: "2031-12-31"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if party.Name != "novyy_god_2" {
+		t.Errorf("Ожидалась команда novyy_god_2, получено %q", party.Name)
+	}
+
+	for i := len(updated.Aliases); i < models.MaxAliases; i++ {
+		if _, err := eventService.AddAlias(100, "new_year", fmt.Sprintf("ny_%d", i)); err != nil {
+			t.Fatalf("Ошибка добавления псевдонима: %v", err)
+		}
+	}
+	if _, err := eventService.AddAlias(100, "new_year", "one_more"); err == nil {
+		t.Errorf("Больше %d псевдонимов добавлять нельзя", models.MaxAliases)
+	}
+
+	// Основное имя удалить как псевдоним нельзя
+	if _, err := eventService.RemoveAlias(100, "new_year"); err == nil {
+		t.Error("Имя события не должно удаляться как псевдоним")
+	}
+	removed, err := eventService.RemoveAlias(100, "ny")
+	if err != nil || removed.EventID != event.EventID {
+		t.Fatalf("Ошибка удаления псевдонима: %v", err)
+	}
+	if _, err := eventService.GetEvent(100, "ny"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Удалённый псевдоним не должен находиться, получено %v", err)
+	}
+	if _, err := eventService.RemoveAlias(100, "ny"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound, получено %v", err)
+	}
+
+	// Переименование в собственный псевдоним убирает его из списка
+	renamed, err := eventService.RenameEvent(100, "new_year", "novyy_god")
+	if err != nil {
+		t.Fatalf("Ошибка переименования: %v", err)
+	}
+	if renamed.Name != "novyy_god" || renamed.HasAlias("novyy_god") || !renamed.HasAlias("ny_2") {
+		t.Errorf("Неожиданные имя и псевдонимы после переименования: %s %v", renamed.Name, renamed.Aliases)
+	}
+}
@@ -0,0 +1,124 @@
+package integration
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestCatalogPublishAndSubscribe(t *testing.T) {
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	catalogService := services.NewCatalogService(store)
+
+	if err := eventService.CreateEvent(100, "birthday", "2030-05-01 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := eventService.CreateEvent(100, "secret", "2030-06-01 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := eventService.CreateEvent(200, "party", "2030-07-01 18:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	// Без подписки события других чатов не видны и не находятся
+	if _, err := eventService.FindSubscribedEvent(200, "birthday"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Без подписки событие не должно находиться, получено %v", err)
+	}
+
+	if err := catalogService.Publish(100, "family_main", "birthday"); err != nil {
+		t.Fatalf("Ошибка публикации: %v", err)
+	}
+	if err := catalogService.Publish(200, "family_main", "party"); !errors.Is(err, services.ErrCatalogOwnedByOtherChat) {
+		t.Errorf("Публикация в чужой каталог: ожидалась ErrCatalogOwnedByOtherChat, получено %v", err)
+	}
+	if err := catalogService.Subscribe(100, "family_main"); !errors.Is(err, services.ErrOwnCatalog) {
+		t.Errorf("Подписка на свой каталог: ожидалась ErrOwnCatalog, получено %v", err)
+	}
+	if err := catalogService.Subscribe(200, "unknown"); !errors.Is(err, storage.ErrCatalogNotFound) {
+		t.Errorf("Ожидалась ошибка ErrCatalogNotFound, получено %v", err)
+	}
+	if err := catalogService.Subscribe(200, "family_main"); err != nil {
+		t.Fatalf("Ошибка подписки: %v", err)
+	}
+
+	events, err := eventService.ListVisibleEvents(200)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 2 || events[0].Name != "party" || events[1].Name != "birthday" {
+		t.Errorf("Ожидались party и опубликованный birthday, получено %+v", events)
+	}
+
+	event, err := eventService.FindSubscribedEvent(200, "birthday")
+	if err != nil || event.ChatID != 100 {
+		t.Errorf("Ожидалось событие из чата 100, получено %+v, %v", event, err)
+	}
+	// Неопубликованные события каталога не видны подписчикам
+	if _, err := eventService.FindSubscribedEvent(200, "secret"); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Неопубликованное событие не должно находиться, получено %v", err)
+	}
+
+	if err := catalogService.Unpublish(100, "family_main", "birthday"); err != nil {
+		t.Fatalf("Ошибка снятия публикации: %v", err)
+	}
+	if events, _ := eventService.ListVisibleEvents(200); len(events) != 1 {
+		t.Errorf("После снятия публикации ожидалось 1 событие, получено %d", len(events))
+	}
+
+	if err := catalogService.Unsubscribe(200, "family_main"); err != nil {
+		t.Fatalf("Ошибка отписки: %v", err)
+	}
+	if err := catalogService.Unsubscribe(200, "family_main"); !errors.Is(err, storage.ErrSubscriptionNotFound) {
+		t.Errorf("Ожидалась ошибка ErrSubscriptionNotFound, получено %v", err)
+	}
+}
+
+func TestMigrateTestChatToCatalog(t *testing.T) {
+	const testChatID = 999
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	catalogService := services.NewCatalogService(store)
+
+	for _, chatID := range []int64{100, 200, testChatID} {
+		if err := eventService.CreateEvent(chatID, "birthday", "2030-05-01 00:00", ""); err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+	}
+	if err := eventService.CreateEvent(testChatID, "new_year", "2030-12-31 23:59", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	if err := catalogService.MigrateTestChat(testChatID, "test_chat"); err != nil {
+		t.Fatalf("Ошибка переноса тестового чата: %v", err)
+	}
+
+	// События тестового чата видны в остальных чатах, как и раньше
+	events, err := eventService.ListVisibleEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 3 {
+		t.Errorf("Ожидалось 3 события вместе с каталогом тестового чата, получено %d", len(events))
+	}
+	if events, _ := eventService.ListVisibleEvents(testChatID); len(events) != 2 {
+		t.Errorf("В самом тестовом чате события не должны дублироваться, получено %d", len(events))
+	}
+	if event, err := eventService.FindSubscribedEvent(200, "new_year"); err != nil || event.ChatID != testChatID {
+		t.Errorf("Ожидалось событие из тестового чата, получено %+v, %v", event, err)
+	}
+
+	// Повторный запуск не возвращает отписавшиеся чаты
+	if err := catalogService.Unsubscribe(200, "test_chat"); err != nil {
+		t.Fatalf("Ошибка отписки: %v", err)
+	}
+	if err := catalogService.MigrateTestChat(testChatID, "test_chat"); err != nil {
+		t.Fatalf("Ошибка повторного переноса: %v", err)
+	}
+	if subscriptions, _ := catalogService.Subscriptions(200); len(subscriptions) != 0 {
+		t.Errorf("Повторный перенос не должен подписывать чат снова, получено %v", subscriptions)
+	}
+}
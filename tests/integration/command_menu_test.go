@@ -0,0 +1,176 @@
+package integration
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+// fakeCommandMenuClient запоминает меню, отправленные в Telegram
+type fakeCommandMenuClient struct {
+	menus map[int64][]scheduler.Command
+	calls int
+	err   error
+}
+
+func (c *fakeCommandMenuClient) SetChatCommands(ctx context.Context, chatID int64, commands []scheduler.Command) error {
+	c.calls++
+	if c.err != nil {
+		return c.err
+	}
+	c.menus[chatID] = commands
+	return nil
+}
+
+func (c *fakeCommandMenuClient) DeleteChatCommands(ctx context.Context, chatID int64) error {
+	c.calls++
+	if c.err != nil {
+		return c.err
+	}
+	delete(c.menus, chatID)
+	return nil
+}
+
+func commandNames(commands []scheduler.Command) []string {
+	names := make([]string, len(commands))
+	for i, command := range commands {
+		names[i] = command.Command
+	}
+	return names
+}
+
+func TestCommandMenu(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	chatService := services.NewChatService(store)
+	client := &fakeCommandMenuClient{menus: make(map[int64][]scheduler.Command)}
+	location, _ := models.LoadTimeZone("")
+	clock := &fakeClock{now: time.Date(2030, 5, 1, 10, 0, 0, 0, location)}
+	system := []scheduler.Command{{Command: "list", Description: "Список событий"}, {Command: "help", Description: "Справка"}}
+	menu := scheduler.NewCommandMenu(eventService, chatService, client, clock, system)
+
+	for _, event := range []struct {
+		chatID int64
+		name   string
+		date   string
+	}{
+		{100, "birthday", "2030-05-10 00:00"},
+		{100, "party", "2030-05-01 18:30"},
+		{100, "past", "2030-04-01 00:00"},
+		{100, "NewYear", "2030-12-31 23:59"},
+		{200, "old", "2020-01-01 00:00"},
+	} {
+		if err := eventService.CreateEvent(event.chatID, event.name, event.date, "Описание"); err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+	}
+
+	// Событие, созданное до запрета имён-команд бота, в меню не попадает
+	legacy := models.Event{EventID: models.GenerateEventID(), Name: "list", Date: "2030-06-01 00:00", Status: models.StatusActive, ChatID: 100}
+	if err := store.SaveEvent(100, legacy); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+
+	// При запуске меню отправляются всем чатам; чат без активных событий получает общее меню
+	client.menus[200] = system
+	if updated, err := menu.Tick(ctx); err != nil || updated != 2 {
+		t.Fatalf("Ожидалось обновление двух меню, получено %d (%v)", updated, err)
+	}
+	names := commandNames(client.menus[100])
+	if fmt.Sprint(names) != "[list help party birthday newyear]" {
+		t.Errorf("Ожидались системные команды и события по дате, получено %v", names)
+	}
+	if description := client.menus[100][2].Description; description != "2030-05-01 18:30 · Описание" {
+		t.Errorf("Неожиданное описание команды: %q", description)
+	}
+	if _, ok := client.menus[200]; ok {
+		t.Error("Меню чата без активных событий должно быть удалено")
+	}
+	// Команды в меню строчные, и команда в другом регистре находит событие
+	if event, err := eventService.GetEvent(100, "newyear"); err != nil || event.Name != "NewYear" {
+		t.Errorf("Команда /newyear должна открывать событие NewYear, получено %+v, %v", event, err)
+	}
+
+	// Неизменившиеся меню повторно не отправляются
+	calls := client.calls
+	if updated, _ := menu.Tick(ctx); updated != 0 || client.calls != calls {
+		t.Errorf("Неизменившиеся меню не должны отправляться, обновлено %d", updated)
+	}
+
+	// Новое событие появляется в меню сразу
+	if err := eventService.CreateEvent(100, "meeting", "2030-05-02 09:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := menu.Refresh(ctx, 100); err != nil {
+		t.Fatalf("Ошибка обновления меню: %v", err)
+	}
+	if names := commandNames(client.menus[100]); fmt.Sprint(names) != "[list help party meeting birthday newyear]" {
+		t.Errorf("Новое событие должно появиться в меню, получено %v", names)
+	}
+
+	// Прошедшее событие исчезает при следующем проходе
+	clock.now = time.Date(2030, 5, 1, 19, 0, 0, 0, location)
+	if updated, _ := menu.Tick(ctx); updated != 1 {
+		t.Errorf("Ожидалось обновление одного меню, получено %d", updated)
+	}
+	if names := commandNames(client.menus[100]); fmt.Sprint(names) != "[list help meeting birthday newyear]" {
+		t.Errorf("Прошедшее событие должно исчезнуть из меню, получено %v", names)
+	}
+
+	// При ограничении частоты проход прерывается, а меню обновляется после паузы
+	if err := eventService.CreateEvent(100, "picnic", "2030-07-01 12:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	client.err = &scheduler.RateLimitError{RetryAfter: 30 * time.Second}
+	if _, err := menu.Tick(ctx); err == nil {
+		t.Error("Ожидалась ошибка ограничения частоты")
+	}
+	client.err = nil
+	calls = client.calls
+	menu.Refresh(ctx, 100)
+	if client.calls != calls {
+		t.Error("До окончания паузы меню не должно отправляться")
+	}
+	clock.now = clock.now.Add(time.Minute)
+	menu.Tick(ctx)
+	if names := commandNames(client.menus[100]); len(names) != 6 || names[4] != "picnic" {
+		t.Errorf("После паузы меню должно обновиться, получено %v", names)
+	}
+}
+
+func TestCommandMenuLimit(t *testing.T) {
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	system := []scheduler.Command{{Command: "help", Description: "Справка"}}
+	menu := scheduler.NewCommandMenu(eventService, services.NewChatService(store),
+		&fakeCommandMenuClient{menus: make(map[int64][]scheduler.Command)}, &fakeClock{}, system)
+
+	for i := 0; i < scheduler.MaxChatCommands+20; i++ {
+		date := time.Date(2030, 1, 1, 0, 0, 0, 0, time.UTC).AddDate(0, 0, scheduler.MaxChatCommands+20-i)
+		if err := eventService.CreateEvent(100, fmt.Sprintf("event_%03d", i), date.Format("2006-01-02"), ""); err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+	}
+
+	now := time.Date(2029, 1, 1, 0, 0, 0, 0, time.UTC)
+	commands, err := menu.Commands(100, now)
+	if err != nil {
+		t.Fatalf("Ошибка построения меню: %v", err)
+	}
+	if len(commands) != scheduler.MaxChatCommands {
+		t.Fatalf("Меню должно содержать %d команд, получено %d", scheduler.MaxChatCommands, len(commands))
+	}
+	// В меню попадают ближайшие события
+	if commands[1].Command != "event_119" || commands[len(commands)-1].Command != "event_021" {
+		t.Errorf("В меню должны попасть ближайшие события, получено %s ... %s",
+			commands[1].Command, commands[len(commands)-1].Command)
+	}
+}
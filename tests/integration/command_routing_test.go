@@ -0,0 +1,87 @@
+package integration
+
+import (
+	"context"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/telegram"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+// routeCommand регистрирует системные команды так же, как бот, и возвращает,
+// какой обработчик получил сообщение text: команду или "/" для динамических команд
+func routeCommand(t *testing.T, text string) string {
+	t.Helper()
+	b, err := bot.New("123456:test", bot.WithSkipGetMe(), bot.WithNotAsyncHandlers())
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+	var handled string
+	for _, command := range []string{"/set_date", "/list", "/all", "/active", "/outdated", "/help", "/delete", "/remind", "/timezone"} {
+		b.RegisterHandlerMatchFunc(telegram.CommandMatcher("family_bot", command), func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+			handled = command
+		})
+	}
+	// Обработчик динамических команд регистрируется последним
+	b.RegisterHandler(bot.HandlerTypeMessageText, "/", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		handled = "/"
+	})
+
+	b.ProcessUpdate(context.Background(), &tgmodels.Update{Message: &tgmodels.Message{
+		Chat: tgmodels.Chat{ID: 100},
+		Text: text,
+	}})
+	return handled
+}
+
+func TestEventCommandsReachDynamicHandler(t *testing.T) {
+	tests := []struct {
+		text     string
+		expected string
+	}{
+		// Команды событий, начинающиеся с системной команды
+		{"/all_hands", "/"},
+		{"/listen", "/"},
+		{"/help_desk", "/"},
+		{"/set_date_party", "/"},
+		{"/delete_party", "/"},
+		{"/remind_me", "/"},
+		{"/timezone_trip", "/"},
+		// Системные команды с аргументами и упоминанием бота
+		{"/list", "/list"},
+		{"/all@family_bot", "/all"},
+		{"/help", "/help"},
+		{"/set_date 2030-01-01 party", "/set_date"},
+		{"/delete party", "/delete"},
+		{"/all@Family_Bot", "/all"},
+		// Команда другого бота не доходит до наших обработчиков, а динамический обработчик её пропускает
+		{"/delete@other_bot party", "/"},
+		{"/list@other_bot", "/"},
+	}
+	for _, tt := range tests {
+		if handled := routeCommand(t, tt.text); handled != tt.expected {
+			t.Errorf("%q: ожидался обработчик %s, получен %q", tt.text, tt.expected, handled)
+		}
+	}
+}
+
+func TestCommandMatcherChecksMention(t *testing.T) {
+	match := telegram.CommandMatcher("family_bot", "/delete")
+	tests := []struct {
+		text     string
+		expected bool
+	}{
+		{"/delete party", true},
+		{"/delete@family_bot party", true},
+		{"/delete@FAMILY_BOT party", true},
+		{"/delete@other_bot party", false},
+		{"/delete_party", false},
+	}
+	for _, tt := range tests {
+		update := &tgmodels.Update{Message: &tgmodels.Message{Text: tt.text}}
+		if got := match(update); got != tt.expected {
+			t.Errorf("%q: ожидалось %v, получено %v", tt.text, tt.expected, got)
+		}
+	}
+}
@@ -0,0 +1,165 @@
+package integration
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func testConversationFlow(t *testing.T, store storage.Storage) {
+	conversations := services.NewConversationService(store, 30*time.Minute)
+	eventService := services.NewEventService(store)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if err := eventService.CreateEvent(100, "taken", "2030-06-01", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	conversation, err := conversations.Start(100, 1, now)
+	if err != nil {
+		t.Fatalf("Ошибка начала диалога: %v", err)
+	}
+	// Имя на русском допустимо, а многострочное - нет
+	if err := conversations.SetName(conversation, "Мама\nи папа", now); err == nil {
+		t.Error("Ожидалась ошибка для некорректного имени")
+	}
+	if err := conversations.SetName(conversation, "taken", now); err == nil {
+		t.Error("Ожидалась ошибка для занятого имени")
+	}
+	if err := conversations.SetName(conversation, "party", now); err != nil {
+		t.Fatalf("Ошибка установки имени: %v", err)
+	}
+	if err := conversations.SetDate(conversation, "2030-13-01", now); err == nil {
+		t.Error("Ожидалась ошибка для некорректной даты")
+	}
+	if err := conversations.SetDate(conversation, "2030-06-15", now); err != nil {
+		t.Fatalf("Ошибка установки даты: %v", err)
+	}
+	if err := conversations.SetMessageID(conversation, 42, now); err != nil {
+		t.Fatalf("Ошибка сохранения сообщения: %v", err)
+	}
+
+	// Состояние хранится в storage: "после перезапуска" диалог продолжается с того же шага
+	restored, err := services.NewConversationService(store, 30*time.Minute).Active(100, 1, now.Add(time.Minute))
+	if err != nil {
+		t.Fatalf("Ошибка получения диалога: %v", err)
+	}
+	if restored.Step != models.StepTime || restored.Name != "party" || restored.Date != "2030-06-15" || restored.MessageID != 42 {
+		t.Errorf("Неожиданное состояние диалога: %+v", *restored)
+	}
+
+	// Диалоги разных пользователей независимы
+	if _, err := conversations.Active(100, 2, now); !errors.Is(err, storage.ErrConversationNotFound) {
+		t.Errorf("Ожидалась ошибка ErrConversationNotFound, получено %v", err)
+	}
+
+	if err := conversations.SetTime(restored, "7:5", now); err == nil {
+		t.Error("Ожидалась ошибка для некорректного времени")
+	}
+	if err := conversations.SetTime(restored, "18:30", now); err != nil {
+		t.Fatalf("Ошибка установки времени: %v", err)
+	}
+	if err := conversations.SetDescription(restored, "  Вечеринка ", now); err != nil {
+		t.Fatalf("Ошибка установки описания: %v", err)
+	}
+	if restored.Step != models.StepConfirm || restored.EventDate() != "2030-06-15 18:30" || restored.Description != "Вечеринка" {
+		t.Errorf("Неожиданный черновик: %+v", *restored)
+	}
+
+	if err := conversations.Finish(100, 1); err != nil {
+		t.Fatalf("Ошибка завершения диалога: %v", err)
+	}
+	if _, err := conversations.Active(100, 1, now); !errors.Is(err, storage.ErrConversationNotFound) {
+		t.Errorf("Диалог должен быть удалён, получено %v", err)
+	}
+}
+
+func testConversationTimeout(t *testing.T, store storage.Storage) {
+	conversations := services.NewConversationService(store, 30*time.Minute)
+	now := time.Date(2030, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	if _, err := conversations.Start(100, 1, now); err != nil {
+		t.Fatalf("Ошибка начала диалога: %v", err)
+	}
+	if _, err := conversations.Start(200, 1, now.Add(20*time.Minute)); err != nil {
+		t.Fatalf("Ошибка начала диалога: %v", err)
+	}
+
+	// Обращение к просроченному диалогу удаляет его
+	if _, err := conversations.Active(100, 1, now.Add(31*time.Minute)); !errors.Is(err, services.ErrConversationExpired) {
+		t.Errorf("Ожидалась ошибка ErrConversationExpired, получено %v", err)
+	}
+	if _, err := conversations.Active(100, 1, now.Add(31*time.Minute)); !errors.Is(err, storage.ErrConversationNotFound) {
+		t.Errorf("Просроченный диалог должен быть удалён, получено %v", err)
+	}
+
+	// Фоновая очистка удаляет только просроченные диалоги
+	expired, err := conversations.ExpireConversations(now.Add(40 * time.Minute))
+	if err != nil {
+		t.Fatalf("Ошибка очистки диалогов: %v", err)
+	}
+	if len(expired) != 0 {
+		t.Errorf("Ожидалось 0 просроченных диалогов, получено %d", len(expired))
+	}
+	expired, err = conversations.ExpireConversations(now.Add(51 * time.Minute))
+	if err != nil {
+		t.Fatalf("Ошибка очистки диалогов: %v", err)
+	}
+	if len(expired) != 1 || expired[0].ChatID != 200 {
+		t.Errorf("Ожидался просроченный диалог чата 200, получено %+v", expired)
+	}
+}
+
+func TestConversationFlowJSON(t *testing.T) {
+	chdirTemp(t)
+	testConversationFlow(t, storage.NewJSONStorage(storage.JSONOptions{}))
+}
+
+func TestConversationFlowSQLite(t *testing.T) {
+	testConversationFlow(t, newSQLiteStorage(t))
+}
+
+func TestConversationTimeoutJSON(t *testing.T) {
+	chdirTemp(t)
+	testConversationTimeout(t, storage.NewJSONStorage(storage.JSONOptions{}))
+}
+
+func TestConversationTimeoutSQLite(t *testing.T) {
+	testConversationTimeout(t, newSQLiteStorage(t))
+}
+
+func TestConversationSurvivesSQLiteReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.db")
+	store, err := storage.NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Ошибка инициализации SQLiteStorage: %v", err)
+	}
+	now := time.Now()
+	conversations := services.NewConversationService(store, time.Hour)
+	conversation, err := conversations.Start(100, 1, now)
+	if err != nil {
+		t.Fatalf("Ошибка начала диалога: %v", err)
+	}
+	if err := conversations.SetName(conversation, "trip", now); err != nil {
+		t.Fatalf("Ошибка установки имени: %v", err)
+	}
+	store.Close()
+
+	reopened, err := storage.NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Ошибка повторного открытия SQLiteStorage: %v", err)
+	}
+	defer reopened.Close()
+	restored, err := services.NewConversationService(reopened, time.Hour).Active(100, 1, now)
+	if err != nil {
+		t.Fatalf("Ошибка получения диалога после перезапуска: %v", err)
+	}
+	if restored.Step != models.StepDate || restored.Name != "trip" {
+		t.Errorf("Неожиданное состояние диалога: %+v", *restored)
+	}
+}
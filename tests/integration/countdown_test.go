@@ -0,0 +1,194 @@
+package integration
+
+import (
+	"context"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+// fakeCountdownClient имитирует сообщения и закрепление в Telegram
+type fakeCountdownClient struct {
+	nextID    int
+	messages  map[int]string
+	pinned    int
+	edits     int
+	rateLimit time.Duration
+}
+
+func newFakeCountdownClient() *fakeCountdownClient {
+	return &fakeCountdownClient{nextID: 1, messages: make(map[int]string)}
+}
+
+func (c *fakeCountdownClient) Post(ctx context.Context, chatID int64, text string) (int, error) {
+	id := c.nextID
+	c.nextID++
+	c.messages[id] = text
+	return id, nil
+}
+
+func (c *fakeCountdownClient) Edit(ctx context.Context, chatID int64, messageID int, text string) error {
+	if c.rateLimit > 0 {
+		return &scheduler.RateLimitError{RetryAfter: c.rateLimit}
+	}
+	current, ok := c.messages[messageID]
+	if !ok {
+		return scheduler.ErrMessageNotFound
+	}
+	if current == text {
+		return scheduler.ErrMessageNotModified
+	}
+	c.messages[messageID] = text
+	c.edits++
+	return nil
+}
+
+func (c *fakeCountdownClient) Pin(ctx context.Context, chatID int64, messageID int) error {
+	if _, ok := c.messages[messageID]; !ok {
+		return scheduler.ErrMessageNotFound
+	}
+	c.pinned = messageID
+	return nil
+}
+
+func (c *fakeCountdownClient) Unpin(ctx context.Context, chatID int64, messageID int) error {
+	if c.pinned == messageID {
+		c.pinned = 0
+	}
+	return nil
+}
+
+func (c *fakeCountdownClient) PinnedMessageID(ctx context.Context, chatID int64) (int, error) {
+	return c.pinned, nil
+}
+
+func (c *fakeCountdownClient) delete(messageID int) {
+	delete(c.messages, messageID)
+	if c.pinned == messageID {
+		c.pinned = 0
+	}
+}
+
+func TestPinnedCountdown(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	countdownService := services.NewCountdownService(store)
+	client := newFakeCountdownClient()
+	location, _ := models.LoadTimeZone("")
+	clock := &fakeClock{now: time.Date(2030, 5, 1, 10, 0, 0, 0, location)}
+	countdowns := scheduler.NewCountdownScheduler(eventService, countdownService, client, clock,
+		scheduler.CountdownOptions{ChatLocation: func(int64) *time.Location { return location }})
+
+	if err := eventService.CreateEvent(100, "party", "2030-05-01 18:30", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := eventService.CreateEvent(100, "birthday", "2030-05-10 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := eventService.CreateEvent(100, "past", "2030-04-01 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	countdown, err := countdowns.Pin(ctx, 100, 5)
+	if err != nil {
+		t.Fatalf("Ошибка закрепления отсчёта: %v", err)
+	}
+	text := client.messages[countdown.MessageID]
+	if client.pinned != countdown.MessageID {
+		t.Errorf("Отсчёт должен быть закреплён")
+	}
+	if !strings.Contains(text, "party: 2030-05-01 18:30 - через 8 часов 30 минут") || !strings.Contains(text, "birthday") || strings.Contains(text, "past") {
+		t.Errorf("Неожиданный текст отсчёта:\n%s", text)
+	}
+	if strings.Index(text, "party") > strings.Index(text, "birthday") {
+		t.Errorf("События должны идти в порядке наступления:\n%s", text)
+	}
+
+	// Правки не чаще одной в минуту
+	clock.now = clock.now.Add(30 * time.Second)
+	if updated, _ := countdowns.Tick(ctx); updated != 0 {
+		t.Errorf("Правка раньше чем через минуту не ожидалась, обновлено %d", updated)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	if updated, _ := countdowns.Tick(ctx); updated != 1 || client.edits != 1 {
+		t.Errorf("Ожидалась одна правка, обновлено %d, правок %d", updated, client.edits)
+	}
+
+	// Новое событие попадает в отсчёт при следующем обновлении
+	if err := eventService.CreateEvent(100, "meeting", "2030-05-02 12:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	clock.now = clock.now.Add(time.Minute)
+	countdowns.Tick(ctx)
+	if !strings.Contains(client.messages[countdown.MessageID], "meeting") {
+		t.Errorf("Новое событие должно появиться в отсчёте:\n%s", client.messages[countdown.MessageID])
+	}
+
+	// Ограничение частоты откладывает правки на RetryAfter
+	client.rateLimit = 10 * time.Minute
+	clock.now = clock.now.Add(time.Minute)
+	countdowns.Tick(ctx)
+	client.rateLimit = 0
+	clock.now = clock.now.Add(5 * time.Minute)
+	edits := client.edits
+	if countdowns.Tick(ctx); client.edits != edits {
+		t.Error("До истечения RetryAfter правки не ожидались")
+	}
+
+	// Удалённое сообщение отправляется и закрепляется заново
+	client.delete(countdown.MessageID)
+	clock.now = clock.now.Add(10 * time.Minute)
+	if updated, _ := countdowns.Tick(ctx); updated != 1 {
+		t.Errorf("Ожидалась повторная отправка отсчёта, обновлено %d", updated)
+	}
+	restored, err := countdownService.Get(100)
+	if err != nil || restored.MessageID == countdown.MessageID || client.pinned != restored.MessageID {
+		t.Fatalf("Отсчёт должен быть отправлен и закреплён заново: %+v, %v, закреплено %d", restored, err, client.pinned)
+	}
+
+	// Снятый с закрепления отсчёт закрепляется снова при плановой проверке
+	client.pinned = 0
+	clock.now = clock.now.Add(scheduler.DefaultCountdownPinCheck)
+	countdowns.Tick(ctx)
+	if client.pinned != restored.MessageID {
+		t.Errorf("Отсчёт должен быть закреплён снова, закреплено %d", client.pinned)
+	}
+
+	if err := countdowns.Unpin(ctx, 100); err != nil {
+		t.Fatalf("Ошибка открепления: %v", err)
+	}
+	if client.pinned != 0 {
+		t.Error("Отсчёт должен быть откреплён")
+	}
+	if _, err := countdownService.Get(100); err == nil {
+		t.Error("Отключённый отсчёт не должен храниться")
+	}
+}
+
+func TestCountdownTextRoundsLongIntervals(t *testing.T) {
+	location, _ := models.LoadTimeZone("")
+	now := time.Date(2030, 5, 1, 10, 0, 0, 0, location)
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	if err := eventService.CreateEvent(100, "birthday", "2030-05-03 12:45", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	upcoming, err := eventService.UpcomingEvents(100, now, 0)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	text := scheduler.CountdownText(upcoming, now, nil)
+	if !strings.Contains(text, "через 2 дня 2 часа") {
+		t.Errorf("Больше суток до события время должно округляться до часов:\n%s", text)
+	}
+	if text := scheduler.CountdownText(nil, now, location); !strings.Contains(text, "Ближайших событий нет") {
+		t.Errorf("Неожиданный текст пустого отсчёта: %s", text)
+	}
+}
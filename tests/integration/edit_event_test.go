@@ -0,0 +1,100 @@
+package integration
+
+import (
+	"os"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+// chdirTemp переключает рабочий каталог во временный, чтобы JSONStorage писал ./data туда
+func chdirTemp(t testing.TB) {
+	t.Helper()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(t.TempDir()); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(wd) })
+}
+
+func testEditEvents(t *testing.T, store storage.Storage) {
+	eventService := services.NewEventService(store)
+	userService := services.NewUserService(store)
+
+	if err := eventService.CreateEvent(100, "party", "2030-06-01 18:00", "Вечеринка"); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := eventService.CreateEvent(100, "trip", "2030-07-01 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	party, _ := eventService.GetEvent(100, "party")
+	if err := userService.AddEventToUser(100, 1, *party); err != nil {
+		t.Fatalf("Ошибка добавления события пользователю: %v", err)
+	}
+
+	// Перенос в прошлое делает событие устаревшим
+	if err := eventService.UpdateEventDate(100, "party", "2020-06-01 18:00"); err != nil {
+		t.Fatalf("Ошибка изменения даты: %v", err)
+	}
+	if err := eventService.UpdateEventDescription(100, "party", "Прошедшая вечеринка"); err != nil {
+		t.Fatalf("Ошибка изменения описания: %v", err)
+	}
+	updated, err := eventService.GetEvent(100, "party")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	if updated.EventID != party.EventID || updated.Date != "2020-06-01 18:00" || updated.Description != "Прошедшая вечеринка" {
+		t.Errorf("Событие изменено неверно: %+v", *updated)
+	}
+	if updated.Status != models.StatusOutdated {
+		t.Errorf("Ожидался статус outdated, получено %s", updated.Status)
+	}
+
+	// Переименование в занятое имя запрещено
+	if _, err := eventService.RenameEvent(100, "party", "trip"); err == nil {
+		t.Error("Переименование в существующее имя должно завершаться ошибкой")
+	}
+	if _, err := eventService.RenameEvent(100, "party", "old_party"); err != nil {
+		t.Fatalf("Ошибка переименования: %v", err)
+	}
+	if _, err := eventService.GetEvent(100, "party"); err == nil {
+		t.Error("Старое имя события не должно находиться после переименования")
+	}
+
+	user, err := userService.GetUser(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if len(user.Events) != 1 || user.Events[0].Name != "old_party" {
+		t.Errorf("Событие пользователя должно отражать переименование: %+v", user.Events)
+	}
+
+	if err := eventService.DeleteEvent(100, party.EventID); err != nil {
+		t.Fatalf("Ошибка удаления события: %v", err)
+	}
+	if err := eventService.DeleteEvent(100, party.EventID); err == nil {
+		t.Error("Повторное удаление должно завершаться ошибкой")
+	}
+	events, _ := eventService.ListEvents(100)
+	if len(events) != 1 || events[0].Name != "trip" {
+		t.Errorf("После удаления должно остаться только событие trip: %+v", events)
+	}
+	user, _ = userService.GetUser(100, 1)
+	if user != nil && len(user.Events) != 0 {
+		t.Errorf("Удалённое событие не должно оставаться у пользователя: %+v", user.Events)
+	}
+}
+
+func TestEditEventsJSON(t *testing.T) {
+	chdirTemp(t)
+	testEditEvents(t, storage.NewJSONStorage(storage.JSONOptions{}))
+}
+
+func TestEditEventsSQLite(t *testing.T) {
+	testEditEvents(t, newSQLiteStorage(t))
+}
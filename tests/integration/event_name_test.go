@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestCyrillicEventNames(t *testing.T) {
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+
+	event, err := eventService.AddEvent(100, models.Event{Name: "День рождения мамы", Date: "2030-05-12"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if event.Name != "den_rozhdeniya_mamy" || event.DisplayName != "День рождения мамы" || event.Title() != "День рождения мамы" {
+		t.Errorf("Ожидались команда den_rozhdeniya_mamy и отображаемое имя, получено %q / %q", event.Name, event.DisplayName)
+	}
+
+	// Совпадающая команда получает номер, а латинское имя по-прежнему должно быть уникальным
+	second, err := eventService.AddEvent(100, models.Event{Name: "день рождения мамы!", Date: "2031-05-12"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if second.Name != "den_rozhdeniya_mamy_2" {
+		t.Errorf("Ожидалась команда den_rozhdeniya_mamy_2, получено %q", second.Name)
+	}
+	if err := eventService.CreateEvent(100, "den_rozhdeniya_mamy", "2030-01-01", ""); err == nil {
+		t.Error("Латинское имя, совпадающее с командой события, должно отклоняться")
+	}
+	if err := eventService.CreateEvent(100, "Мама\nи папа", "2030-01-01", ""); err == nil {
+		t.Error("Многострочное имя должно отклоняться")
+	}
+
+	// Латинское имя длиннее 32 символов становится отображаемым, а команда обрезается
+	long, err := eventService.AddEvent(100, models.Event{Name: "grandmother_and_grandfather_golden_wedding", Date: "2030-01-01"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if long.Name != "grandmother_and_grandfather_gold" || long.DisplayName != "grandmother_and_grandfather_golden_wedding" {
+		t.Errorf("Ожидалась команда из 32 символов и отображаемое имя, получено %q / %q", long.Name, long.DisplayName)
+	}
+	if found, err := eventService.GetEvent(100, "grandmother_and_grandfather_golden_wedding"); err != nil || found.EventID != long.EventID {
+		t.Errorf("Событие должно находиться по полному имени, получено %v", err)
+	}
+
+	// Латинское имя не может совпадать с командой бота
+	if err := eventService.CreateEvent(100, "delete", "2030-01-01", ""); !errors.Is(err, services.ErrReservedEventName) {
+		t.Errorf("Ожидалась ошибка ErrReservedEventName, получено %v", err)
+	}
+	if _, err := eventService.RenameEvent(100, "den_rozhdeniya_mamy_2", "Policy"); !errors.Is(err, services.ErrReservedEventName) {
+		t.Errorf("Ожидалась ошибка ErrReservedEventName при переименовании, получено %v", err)
+	}
+
+	// Команда, совпадающая с командой бота, считается занятой: иначе событие нельзя было бы открыть
+	for name, expected := range map[string]string{"Help!": "help_2", "List?": "list_2", "New!": "new_2"} {
+		reserved, err := eventService.AddEvent(100, models.Event{Name: name, Date: "2030-01-01"})
+		if err != nil || reserved.Name != expected {
+			t.Errorf("Для %q ожидалась команда %s, получено %+v (%v)", name, expected, reserved, err)
+		}
+	}
+
+	// Событие находится по команде, по отображаемому имени и по имени, набранному по-русски
+	for _, name := range []string{"den_rozhdeniya_mamy", "день рождения мамы", "день_рождения_мамы"} {
+		found, err := eventService.GetEvent(100, name)
+		if err != nil || found.EventID != event.EventID {
+			t.Errorf("Событие должно находиться по %q, получено %v", name, err)
+		}
+	}
+
+	// Переименование пересчитывает команду; латинское имя убирает отображаемое
+	renamed, err := eventService.RenameEvent(100, "день_рождения_мамы", "Юбилей мамы")
+	if err != nil {
+		t.Fatalf("Ошибка переименования: %v", err)
+	}
+	if renamed.Name != "yubiley_mamy" || renamed.DisplayName != "Юбилей мамы" {
+		t.Errorf("Неожиданное имя после переименования: %q / %q", renamed.Name, renamed.DisplayName)
+	}
+	renamed, err = eventService.RenameEvent(100, "yubiley_mamy", "mom")
+	if err != nil {
+		t.Fatalf("Ошибка переименования: %v", err)
+	}
+	if renamed.Name != "mom" || renamed.DisplayName != "" || renamed.Title() != "mom" {
+		t.Errorf("Латинское имя должно стать командой без отображаемого имени, получено %q / %q", renamed.Name, renamed.DisplayName)
+	}
+	stored, err := eventService.GetEvent(100, "mom")
+	if err != nil || stored.DisplayName != "" {
+		t.Errorf("Переименование должно сохраняться, получено %+v (%v)", stored, err)
+	}
+}
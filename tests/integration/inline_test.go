@@ -0,0 +1,115 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestInlineSearch(t *testing.T) {
+	const (
+		userID     = 10
+		familyChat = -100
+		workChat   = -200
+		leftChat   = -300
+		otherChat  = -400
+	)
+	ctx := context.Background()
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	userService := services.NewUserService(store)
+	catalogService := services.NewCatalogService(store)
+
+	for _, event := range []struct {
+		chatID int64
+		name   string
+		date   string
+	}{
+		{userID, "my_birthday", "2030-08-01 00:00"},
+		{familyChat, "new_year", "2030-12-31 23:59"},
+		{familyChat, "old_party", "2020-01-01 00:00"},
+		{workChat, "new_office", "2030-06-01 09:00"},
+		{leftChat, "new_secret", "2030-06-02 00:00"},
+		{otherChat, "new_catalog", "2030-07-01 00:00"},
+		{otherChat, "new_hidden", "2030-07-02 00:00"},
+	} {
+		if err := eventService.CreateEvent(event.chatID, event.name, event.date, ""); err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+	}
+	if err := catalogService.Publish(otherChat, "shared", "new_catalog"); err != nil {
+		t.Fatalf("Ошибка публикации: %v", err)
+	}
+	if err := catalogService.Subscribe(familyChat, "shared"); err != nil {
+		t.Fatalf("Ошибка подписки: %v", err)
+	}
+	for _, chatID := range []int64{familyChat, workChat, leftChat} {
+		if err := userService.RememberMember(chatID, userID); err != nil {
+			t.Fatalf("Ошибка сохранения участника: %v", err)
+		}
+	}
+
+	checks := 0
+	inline := services.NewInlineService(eventService, userService, services.InlineOptions{
+		IsMember: func(ctx context.Context, chatID, userID int64) (bool, error) {
+			checks++
+			if chatID == workChat {
+				return false, errors.New("telegram недоступен")
+			}
+			return chatID != leftChat, nil
+		},
+	})
+
+	now := time.Date(2030, 5, 1, 12, 0, 0, 0, time.UTC)
+	events, err := inline.Search(ctx, userID, "NEW", now)
+	if err != nil {
+		t.Fatalf("Ошибка поиска: %v", err)
+	}
+	// Чат, из которого пользователь вышел, и чат, членство в котором не проверить, скрыты
+	if names := eventNames(events); len(names) != 2 || names[0] != "new_catalog" || names[1] != "new_year" {
+		t.Errorf("Ожидались new_catalog и new_year в порядке наступления, получено %v", names)
+	}
+
+	// Пустой запрос возвращает все видимые события, прошедшие - в конце
+	events, err = inline.Search(ctx, userID, "", now)
+	if err != nil {
+		t.Fatalf("Ошибка поиска: %v", err)
+	}
+	if names := eventNames(events); len(names) != 4 || names[0] != "new_catalog" || names[1] != "my_birthday" || names[3] != "old_party" {
+		t.Errorf("Неожиданный порядок событий: %v", names)
+	}
+
+	// Результаты и проверки членства кэшируются
+	checksBefore := checks
+	if err := eventService.CreateEvent(familyChat, "new_later", "2031-01-01 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if events, _ := inline.Search(ctx, userID, "new", now.Add(10*time.Second)); len(events) != 2 {
+		t.Errorf("В пределах времени жизни кэша ожидался прежний результат, получено %v", eventNames(events))
+	}
+	if events, _ := inline.Search(ctx, userID, "new", now.Add(time.Minute)); len(events) != 3 {
+		t.Errorf("После истечения кэша ожидалось новое событие, получено %v", eventNames(events))
+	}
+	if checks != checksBefore+1 {
+		t.Errorf("Успешные проверки членства должны кэшироваться, проверок: %d", checks-checksBefore)
+	}
+
+	// Другой пользователь не видит чужие группы
+	if events, _ := inline.Search(ctx, 20, "new", now); len(events) != 0 {
+		t.Errorf("Посторонний пользователь не должен видеть события, получено %v", eventNames(events))
+	}
+}
+
+func eventNames(events []models.Event) []string {
+	names := make([]string, len(events))
+	for i, event := range events {
+		names[i] = event.Name
+	}
+	return names
+}
@@ -0,0 +1,199 @@
+package integration
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func saveTestEvent(t *testing.T, store storage.Storage, name string) {
+	t.Helper()
+	event := models.Event{EventID: name, Name: name, Date: "2030-06-01 18:00", Status: models.StatusActive, ChatID: 100}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события %s: %v", name, err)
+	}
+}
+
+// tempJSONOptions возвращает настройки хранилища в отдельном временном каталоге теста
+func tempJSONOptions(t *testing.T) storage.JSONOptions {
+	t.Helper()
+	return storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")}
+}
+
+func backupNames(t *testing.T, options storage.JSONOptions) []string {
+	t.Helper()
+	entries, err := os.ReadDir(filepath.Join(filepath.Dir(options.Path), "backups"))
+	if err != nil {
+		t.Fatalf("Ошибка чтения каталога резервных копий: %v", err)
+	}
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	return names
+}
+
+func TestJSONStorageAtomicWriteLeavesNoTempFiles(t *testing.T) {
+	options := tempJSONOptions(t)
+	store := storage.NewJSONStorage(options)
+	saveTestEvent(t, store, "party")
+	saveTestEvent(t, store, "trip")
+
+	entries, err := os.ReadDir(filepath.Dir(options.Path))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, entry := range entries {
+		if strings.HasSuffix(entry.Name(), ".tmp") {
+			t.Errorf("После записи остался временный файл %s", entry.Name())
+		}
+	}
+	if !storage.NewJSONStorage(options).EventExists(100, "trip") {
+		t.Error("Записанное событие должно читаться из файла")
+	}
+}
+
+func TestJSONStorageRotatesBackups(t *testing.T) {
+	options := tempJSONOptions(t)
+	options.BackupCount = 3
+	store := storage.NewJSONStorage(options)
+	for _, name := range []string{"a", "b", "c", "d", "e"} {
+		saveTestEvent(t, store, name)
+	}
+
+	names := backupNames(t, options)
+	if len(names) != 3 {
+		t.Fatalf("Ожидалось 3 резервные копии, получено %d: %v", len(names), names)
+	}
+	// Самая новая копия содержит все события
+	raw, err := os.ReadFile(filepath.Join(filepath.Dir(options.Path), "backups", names[len(names)-1]))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(string(raw), `"name":"e"`) {
+		t.Error("Последняя резервная копия должна содержать последнее событие")
+	}
+}
+
+func TestJSONStorageBackupInterval(t *testing.T) {
+	options := tempJSONOptions(t)
+	options.BackupCount = 10
+	options.BackupInterval = time.Hour
+	store := storage.NewJSONStorage(options)
+	saveTestEvent(t, store, "a")
+	saveTestEvent(t, store, "b")
+
+	if names := backupNames(t, options); len(names) != 1 {
+		t.Errorf("В пределах интервала должна создаваться одна копия, получено %d", len(names))
+	}
+}
+
+func TestJSONStorageRecoversFromBackup(t *testing.T) {
+	options := tempJSONOptions(t)
+	options.BackupCount = 10
+	store := storage.NewJSONStorage(options)
+	saveTestEvent(t, store, "party")
+	saveTestEvent(t, store, "trip")
+
+	// Оборванная запись: файл данных обрезан посередине
+	path := options.Path
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw[:len(raw)/2], 0o644); err != nil {
+		t.Fatal(err)
+	}
+	// Самая новая резервная копия тоже повреждена - используется предыдущая
+	names := backupNames(t, options)
+	if err := os.WriteFile(filepath.Join(filepath.Dir(path), "backups", names[len(names)-1]), []byte("{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	recovered := storage.NewJSONStorage(storage.JSONOptions{Path: path})
+	if !recovered.EventExists(100, "party") {
+		t.Error("Событие из резервной копии должно быть восстановлено")
+	}
+	if recovered.EventExists(100, "trip") {
+		t.Error("Событие из повреждённой копии не должно появиться")
+	}
+	if _, err := recovered.GetAllEvents(); err != nil {
+		t.Errorf("После восстановления хранилище должно читаться: %v", err)
+	}
+
+	corrupt, err := filepath.Glob(path + ".corrupt-*")
+	if err != nil || len(corrupt) != 1 {
+		t.Errorf("Повреждённый файл должен сохраниться для разбора, найдено %v", corrupt)
+	}
+}
+
+func TestJSONStorageServesFromMemory(t *testing.T) {
+	options := tempJSONOptions(t)
+	store := storage.NewJSONStorage(options)
+	saveTestEvent(t, store, "party")
+
+	// Данные загружены при запуске: удаление файла не влияет на чтение
+	if err := os.Remove(options.Path); err != nil {
+		t.Fatal(err)
+	}
+	if !store.EventExists(100, "party") {
+		t.Error("Событие должно читаться из памяти")
+	}
+
+	// Изменение полученного среза не меняет данные хранилища
+	events, _ := store.GetEvents(100)
+	events[0].Name = "changed"
+	if !store.EventExists(100, "party") {
+		t.Error("Хранилище не должно отдавать свои внутренние данные")
+	}
+}
+
+func TestJSONStorageDelayedFlush(t *testing.T) {
+	options := tempJSONOptions(t)
+	options.FlushDelay = time.Hour
+	store := storage.NewJSONStorage(options)
+	saveTestEvent(t, store, "party")
+	saveTestEvent(t, store, "trip")
+
+	if _, err := os.Stat(options.Path); !os.IsNotExist(err) {
+		t.Fatalf("До истечения задержки файл не должен записываться, err=%v", err)
+	}
+	if !store.EventExists(100, "trip") {
+		t.Error("Отложенные изменения должны быть видны сразу")
+	}
+
+	// Close записывает отложенные изменения
+	if err := store.Close(); err != nil {
+		t.Fatalf("Ошибка закрытия хранилища: %v", err)
+	}
+	reopened := storage.NewJSONStorage(storage.JSONOptions{Path: options.Path})
+	if !reopened.EventExists(100, "party") || !reopened.EventExists(100, "trip") {
+		t.Error("После Close все изменения должны быть на диске")
+	}
+}
+
+func TestJSONStorageCorruptFileWithoutBackup(t *testing.T) {
+	options := tempJSONOptions(t)
+	path := options.Path
+	if err := os.WriteFile(path, []byte("[{"), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := storage.NewJSONStorage(options)
+	if _, err := store.GetAllEvents(); err == nil {
+		t.Error("Повреждённый файл без резервной копии должен давать ошибку")
+	}
+	event := models.Event{EventID: "1", Name: "party", Date: "2030-06-01 18:00", ChatID: 100}
+	if err := store.SaveEvent(100, event); err == nil {
+		t.Error("Запись не должна затирать повреждённый файл")
+	}
+	raw, _ := os.ReadFile(path)
+	if string(raw) != "[{" {
+		t.Error("Повреждённый файл должен остаться без изменений")
+	}
+}
@@ -0,0 +1,85 @@
+package integration
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+const benchChats = 100
+
+// writeBenchData записывает в path файл с total событиями, равномерно распределёнными по чатам
+func writeBenchData(b *testing.B, path string, total int) {
+	b.Helper()
+	data := make([]storage.ChatData, benchChats)
+	for i := range data {
+		data[i] = storage.ChatData{ChatID: int64(i + 1), Users: []models.User{}}
+	}
+	for n := 0; n < total; n++ {
+		chat := &data[n%benchChats]
+		name := fmt.Sprintf("event_%d", n)
+		chat.Events = append(chat.Events, models.Event{
+			EventID:    name,
+			Name:       name,
+			Date:       "2030-06-01 18:00",
+			Status:     models.StatusActive,
+			ChatID:     chat.ChatID,
+			Visibility: models.VisibilityPublic,
+		})
+	}
+	raw, err := json.Marshal(data)
+	if err != nil {
+		b.Fatal(err)
+	}
+	if err := os.WriteFile(path, raw, 0o644); err != nil {
+		b.Fatal(err)
+	}
+}
+
+// Время поиска не должно расти вместе с размером файла
+func BenchmarkJSONStorageLookup(b *testing.B) {
+	for _, total := range []int{100, 1000, 10000} {
+		b.Run(fmt.Sprintf("events=%d", total), func(b *testing.B) {
+			path := filepath.Join(b.TempDir(), "events.json")
+			writeBenchData(b, path, total)
+			store := storage.NewJSONStorage(storage.JSONOptions{Path: path})
+			// Последнее событие последнего чата - худший случай для перебора
+			name := fmt.Sprintf("event_%d", total-1)
+			chatID := int64((total-1)%benchChats + 1)
+
+			b.Run("GetEvent", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := store.GetEvent(chatID, name); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("EventExists", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if store.EventExists(chatID, "missing") {
+						b.Fatal("Событие не должно существовать")
+					}
+				}
+			})
+			b.Run("FindEventAcrossChats", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, _, err := store.FindEventAcrossChats(name, 0); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("GetEvents", func(b *testing.B) {
+				for i := 0; i < b.N; i++ {
+					if _, err := store.GetEvents(chatID); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
@@ -0,0 +1,102 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func testMilestoneScheduler(t *testing.T, store storage.Storage) {
+	eventService := services.NewEventService(store)
+	clock := &fakeClock{}
+	var sent []sentMessage
+	tick := func(at time.Time) int {
+		t.Helper()
+		clock.now = at
+		send := func(ctx context.Context, chatID int64, text string) error {
+			sent = append(sent, sentMessage{chatID: chatID, text: text})
+			return nil
+		}
+		// Новый экземпляр на каждый проход, как после перезапуска бота
+		milestones := scheduler.NewMilestoneScheduler(eventService, services.NewReminderService(store), send, clock,
+			scheduler.MilestoneOptions{})
+		count, err := milestones.Tick(context.Background())
+		if err != nil {
+			t.Fatalf("Ошибка прохода планировщика: %v", err)
+		}
+		return count
+	}
+
+	if _, err := eventService.AddEvent(100, models.Event{Name: "Переезд в Мурманск", Date: "2021-08-15", TimeZone: "UTC"}); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if _, err := eventService.AddEvent(100, models.Event{Name: "quiet", Date: "2021-08-15", TimeZone: "UTC"}); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if _, err := eventService.SetEventCountUp(100, "pereezd_v_murmansk", true, true); err != nil {
+		t.Fatalf("Ошибка включения счёта: %v", err)
+	}
+	// Без объявлений круглые даты только показываются
+	if _, err := eventService.SetEventCountUp(100, "quiet", true, false); err != nil {
+		t.Fatalf("Ошибка включения счёта: %v", err)
+	}
+
+	start := time.Date(2021, 8, 15, 0, 0, 0, 0, time.UTC)
+	thousand := start.AddDate(0, 0, 1000)
+	if n := tick(thousand.Add(-time.Minute)); n != 0 {
+		t.Errorf("До круглой даты объявлений быть не должно, отправлено %d", n)
+	}
+	if n := tick(thousand.Add(time.Hour)); n != 1 {
+		t.Fatalf("Ожидалось одно объявление, отправлено %d", n)
+	}
+	if sent[0].chatID != 100 || !strings.Contains(sent[0].text, "Сегодня 1000 дней с события 'Переезд в Мурманск'") {
+		t.Errorf("Неожиданное объявление: %+v", sent[0])
+	}
+	// Объявление не повторяется после перезапуска
+	if n := tick(thousand.Add(2 * time.Hour)); n != 0 {
+		t.Errorf("Объявление не должно повторяться, отправлено %d", n)
+	}
+
+	// Дата, пропущенная дольше DefaultCatchUp, не объявляется
+	fiveYears := start.AddDate(5, 0, 0)
+	if n := tick(fiveYears.Add(scheduler.DefaultCatchUp + time.Hour)); n != 0 {
+		t.Errorf("Пропущенная круглая дата не должна объявляться, отправлено %d", n)
+	}
+
+	// После выключения режима объявлений нет
+	if _, err := eventService.SetEventCountUp(100, "pereezd_v_murmansk", false, true); err != nil {
+		t.Fatalf("Ошибка выключения счёта: %v", err)
+	}
+	if n := tick(start.AddDate(0, 0, 2000).Add(time.Minute)); n != 0 {
+		t.Errorf("Без режима счёта объявлений быть не должно, отправлено %d", n)
+	}
+	event, err := eventService.GetEvent(100, "pereezd_v_murmansk")
+	if err != nil || event.IsCountUp() || event.AnnounceMilestones {
+		t.Errorf("Режим счёта должен выключиться, получено %+v, %v", event, err)
+	}
+
+	// Для повторяющихся событий режим недоступен
+	if _, err := eventService.AddEvent(100, models.Event{Name: "anniversary", Date: "2021-08-15",
+		Recurrence: &models.Recurrence{Frequency: models.FrequencyYearly, Interval: 1}}); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if _, err := eventService.SetEventCountUp(100, "anniversary", true, false); !errors.Is(err, services.ErrRecurringCountUp) {
+		t.Errorf("Ожидалась ошибка ErrRecurringCountUp, получено %v", err)
+	}
+}
+
+func TestMilestoneSchedulerJSON(t *testing.T) {
+	testMilestoneScheduler(t, storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")}))
+}
+
+func TestMilestoneSchedulerSQLite(t *testing.T) {
+	testMilestoneScheduler(t, newSQLiteStorage(t))
+}
@@ -0,0 +1,129 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+const (
+	groupChatID = -100
+	chatAdmin   = 1
+	eventOwner  = 2
+	member      = 3
+	superAdmin  = 4
+)
+
+func TestPermissionPolicies(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+	userService := services.NewUserService(store)
+	chatService := services.NewChatService(store)
+	permissions := services.NewPermissionService(store, services.PermissionOptions{
+		SuperAdmins: []int64{superAdmin},
+		ChatAdmins: func(ctx context.Context, chatID int64) ([]int64, error) {
+			return []int64{chatAdmin}, nil
+		},
+	})
+
+	event, err := eventService.AddEvent(groupChatID, models.Event{Name: "party", Date: "2030-07-01 18:00"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	userService.AddEventToUser(groupChatID, eventOwner, *event)
+
+	// По умолчанию создают все, а изменяют создатель и администраторы
+	if err := permissions.CanCreate(ctx, groupChatID, member); err != nil {
+		t.Errorf("Участник должен создавать события по умолчанию, получено %v", err)
+	}
+	for _, userID := range []int64{eventOwner, chatAdmin, superAdmin} {
+		if err := permissions.CanEdit(ctx, groupChatID, userID, event.EventID); err != nil {
+			t.Errorf("Пользователь %d должен изменять событие, получено %v", userID, err)
+		}
+	}
+	if err := permissions.CanEdit(ctx, groupChatID, member, event.EventID); !errors.Is(err, services.ErrPermissionDenied) {
+		t.Errorf("Участник не должен изменять чужое событие, получено %v", err)
+	}
+
+	if err := chatService.SetCreatePolicy(groupChatID, models.PolicyOwners); !errors.Is(err, services.ErrInvalidCreatePolicy) {
+		t.Errorf("Ожидалась ошибка ErrInvalidCreatePolicy, получено %v", err)
+	}
+	if err := chatService.SetCreatePolicy(groupChatID, models.PolicyAdmins); err != nil {
+		t.Fatalf("Ошибка изменения политики: %v", err)
+	}
+	if err := chatService.SetEditPolicy(groupChatID, models.PolicyAdmins); err != nil {
+		t.Fatalf("Ошибка изменения политики: %v", err)
+	}
+	if err := permissions.CanCreate(ctx, groupChatID, member); !errors.Is(err, services.ErrPermissionDenied) {
+		t.Errorf("Участник не должен создавать события при политике admins, получено %v", err)
+	}
+	if err := permissions.CanCreate(ctx, groupChatID, chatAdmin); err != nil {
+		t.Errorf("Администратор должен создавать события, получено %v", err)
+	}
+	if err := permissions.CanEdit(ctx, groupChatID, eventOwner, event.EventID); !errors.Is(err, services.ErrPermissionDenied) {
+		t.Errorf("При политике admins создатель не должен изменять событие, получено %v", err)
+	}
+
+	// В личном чате собеседник - администратор
+	if err := permissions.CanManageChat(ctx, member, member); err != nil {
+		t.Errorf("В личном чате пользователь должен управлять настройками, получено %v", err)
+	}
+	if err := permissions.CanManageChat(ctx, groupChatID, member); !errors.Is(err, services.ErrPermissionDenied) {
+		t.Errorf("Участник не должен управлять настройками группы, получено %v", err)
+	}
+
+	if err := chatService.SetEditPolicy(groupChatID, models.PolicyEveryone); err != nil {
+		t.Fatalf("Ошибка изменения политики: %v", err)
+	}
+	if err := permissions.CanEdit(ctx, groupChatID, member, event.EventID); err != nil {
+		t.Errorf("При политике everyone участник должен изменять события, получено %v", err)
+	}
+}
+
+func TestPermissionAdminCache(t *testing.T) {
+	ctx := context.Background()
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	calls := 0
+	fail := false
+	chatAdmins := func(ctx context.Context, chatID int64) ([]int64, error) {
+		calls++
+		if fail {
+			return nil, errors.New("telegram unavailable")
+		}
+		return []int64{chatAdmin}, nil
+	}
+
+	cached := services.NewPermissionService(store, services.PermissionOptions{ChatAdmins: chatAdmins, AdminCacheTTL: time.Hour})
+	for i := 0; i < 3; i++ {
+		if admin, err := cached.IsChatAdmin(ctx, groupChatID, chatAdmin); err != nil || !admin {
+			t.Fatalf("Ожидался администратор, получено %v, %v", admin, err)
+		}
+	}
+	if calls != 1 {
+		t.Errorf("Администраторы должны загружаться один раз за время жизни кэша, загрузок: %d", calls)
+	}
+
+	// Устаревший список используется, если Telegram недоступен
+	expiring := services.NewPermissionService(store, services.PermissionOptions{ChatAdmins: chatAdmins, AdminCacheTTL: time.Nanosecond})
+	if admin, err := expiring.IsChatAdmin(ctx, groupChatID, chatAdmin); err != nil || !admin {
+		t.Fatalf("Ожидался администратор, получено %v, %v", admin, err)
+	}
+	time.Sleep(time.Millisecond)
+	fail = true
+	if admin, err := expiring.IsChatAdmin(ctx, groupChatID, chatAdmin); err != nil || !admin {
+		t.Errorf("При ошибке Telegram ожидался устаревший список, получено %v, %v", admin, err)
+	}
+
+	// Без кэша ошибка загрузки возвращается
+	expiring.InvalidateChat(groupChatID)
+	if _, err := expiring.IsChatAdmin(ctx, groupChatID, chatAdmin); err == nil {
+		t.Error("Без кэша ошибка загрузки администраторов должна возвращаться")
+	}
+}
@@ -0,0 +1,163 @@
+package integration
+
+import (
+	"database/sql"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+// newPostgresStorage создаёт хранилище в отдельной схеме локального PostgreSQL.
+// Тест пропускается, если TEST_DATABASE_URL не задан.
+func newPostgresStorage(t *testing.T) *storage.PostgresStorage {
+	t.Helper()
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL не задан, пропускаем тесты PostgreSQL")
+	}
+
+	admin, err := sql.Open("pgx", dsn)
+	if err != nil {
+		t.Fatalf("Ошибка подключения к PostgreSQL: %v", err)
+	}
+	defer admin.Close()
+
+	schema := fmt.Sprintf("test_%d", time.Now().UnixNano())
+	if _, err := admin.Exec("CREATE SCHEMA " + schema); err != nil {
+		t.Fatalf("Ошибка создания схемы: %v", err)
+	}
+	t.Cleanup(func() {
+		cleanup, err := sql.Open("pgx", dsn)
+		if err == nil {
+			cleanup.Exec("DROP SCHEMA " + schema + " CASCADE")
+			cleanup.Close()
+		}
+	})
+
+	u, err := url.Parse(dsn)
+	if err != nil {
+		t.Fatalf("Некорректный TEST_DATABASE_URL: %v", err)
+	}
+	query := u.Query()
+	query.Set("search_path", schema)
+	u.RawQuery = query.Encode()
+
+	store, err := storage.NewPostgresStorage(u.String())
+	if err != nil {
+		t.Fatalf("Ошибка инициализации PostgresStorage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestPostgresStorageEvents(t *testing.T) {
+	store := newPostgresStorage(t)
+
+	event := models.Event{
+		EventID: models.GenerateEventID(),
+		Name:    "new_year",
+		Date:    "2030-12-31 00:00",
+		Status:  models.StatusActive,
+		ChatID:  100,
+	}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+
+	if !store.EventExists(100, "new_year") {
+		t.Error("Событие должно существовать в своём чате")
+	}
+	if store.EventExists(200, "new_year") {
+		t.Error("Событие не должно существовать в другом чате")
+	}
+
+	got, err := store.GetEvent(100, "new_year")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	if got.EventID != event.EventID || got.Date != event.Date {
+		t.Errorf("Ожидалось %+v, получено %+v", event, *got)
+	}
+
+	// Повторное сохранение с тем же EventID обновляет событие
+	event.Status = models.StatusOutdated
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	events, err := store.GetEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 1 || events[0].Status != models.StatusOutdated {
+		t.Errorf("Ожидалось одно устаревшее событие, получено %+v", events)
+	}
+
+	if _, err := store.GetEvent(200, "new_year"); err != storage.ErrEventNotFound {
+		t.Errorf("Ожидалась ошибка ErrEventNotFound, получено %v", err)
+	}
+}
+
+func TestPostgresStorageFindAcrossChats(t *testing.T) {
+	store := newPostgresStorage(t)
+
+	for _, chatID := range []int64{100, 200} {
+		err := store.SaveEvent(chatID, models.Event{
+			EventID:    models.GenerateEventID(),
+			Name:       "birthday",
+			Date:       "2030-05-01 00:00",
+			Status:     models.StatusActive,
+			ChatID:     chatID,
+			Visibility: models.VisibilityPublic,
+		})
+		if err != nil {
+			t.Fatalf("Ошибка сохранения события: %v", err)
+		}
+	}
+
+	event, chatID, err := store.FindEventAcrossChats("birthday", 100)
+	if err != nil {
+		t.Fatalf("Ошибка поиска события: %v", err)
+	}
+	if chatID != 200 || event.ChatID != 200 {
+		t.Errorf("Ожидалось событие из чата 200, получено из %d", chatID)
+	}
+
+	if _, _, err := store.FindEventAcrossChats("unknown", 100); err == nil {
+		t.Error("Несуществующее событие не должно находиться")
+	}
+}
+
+func TestPostgresStorageUsers(t *testing.T) {
+	store := newPostgresStorage(t)
+
+	if _, err := store.GetUser(100, 1); err != storage.ErrUserNotFound {
+		t.Errorf("Ожидалась ошибка ErrUserNotFound, получено %v", err)
+	}
+
+	event := models.Event{
+		EventID: models.GenerateEventID(),
+		Name:    "trip",
+		Date:    "2030-07-01 00:00",
+		Status:  models.StatusActive,
+		ChatID:  100,
+	}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+	if err := store.AddEventToUser(100, 1, event); err != nil {
+		t.Fatalf("Ошибка добавления события пользователю: %v", err)
+	}
+
+	user, err := store.GetUser(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if len(user.Events) != 1 || user.Events[0].Name != "trip" {
+		t.Errorf("Ожидалось событие trip у пользователя, получено %+v", user.Events)
+	}
+}
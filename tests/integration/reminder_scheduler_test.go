@@ -0,0 +1,182 @@
+package integration
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+// fakeClock - управляемые часы для планировщика
+type fakeClock struct {
+	now time.Time
+}
+
+func (c *fakeClock) Now() time.Time {
+	return c.now
+}
+
+// sentMessage - напоминание, "отправленное" в тесте
+type sentMessage struct {
+	chatID int64
+	text   string
+}
+
+type reminderFixture struct {
+	store        storage.Storage
+	eventService *services.EventService
+	clock        *fakeClock
+	sent         []sentMessage
+	failSend     bool
+}
+
+func newReminderFixture(store storage.Storage) *reminderFixture {
+	return &reminderFixture{
+		store:        store,
+		eventService: services.NewEventService(store),
+		clock:        &fakeClock{},
+	}
+}
+
+// scheduler создаёт новый экземпляр планировщика, как после перезапуска бота
+func (f *reminderFixture) scheduler() *scheduler.ReminderScheduler {
+	send := func(ctx context.Context, chatID int64, text string) error {
+		if f.failSend {
+			return errors.New("telegram недоступен")
+		}
+		f.sent = append(f.sent, sentMessage{chatID: chatID, text: text})
+		return nil
+	}
+	return scheduler.NewReminderScheduler(f.eventService, services.NewReminderService(f.store), send, f.clock,
+		scheduler.ReminderOptions{Defaults: []string{"1d"}})
+}
+
+func (f *reminderFixture) tick(t *testing.T, at time.Time) int {
+	t.Helper()
+	f.clock.now = at
+	sent, err := f.scheduler().Tick(context.Background())
+	if err != nil {
+		t.Fatalf("Ошибка прохода планировщика: %v", err)
+	}
+	return sent
+}
+
+func testReminderScheduler(t *testing.T, store storage.Storage) {
+	f := newReminderFixture(store)
+	location := getMoscow(t)
+	eventTime := time.Date(2030, 1, 10, 12, 0, 0, 0, location)
+
+	if err := f.eventService.CreateEvent(100, "party", "2030-01-10 12:00", "Вечеринка"); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := f.eventService.SetEventReminders(100, "party", []string{"1h", "7d", "1d", "0"}); err != nil {
+		t.Fatalf("Ошибка настройки напоминаний: %v", err)
+	}
+
+	// Раньше первого напоминания ничего не отправляется
+	if sent := f.tick(t, eventTime.Add(-8*24*time.Hour)); sent != 0 {
+		t.Errorf("Ожидалось 0 напоминаний, отправлено %d", sent)
+	}
+
+	// Напоминание за 7 дней отправляется один раз, в том числе после "перезапуска"
+	if sent := f.tick(t, eventTime.Add(-7*24*time.Hour+time.Minute)); sent != 1 {
+		t.Fatalf("Ожидалось напоминание за 7 дней, отправлено %d", sent)
+	}
+	if !strings.Contains(f.sent[0].text, "осталось 6 дней 23 часа 59 минут") || f.sent[0].chatID != 100 {
+		t.Errorf("Неожиданное напоминание: %+v", f.sent[0])
+	}
+	if sent := f.tick(t, eventTime.Add(-7*24*time.Hour+2*time.Minute)); sent != 0 {
+		t.Errorf("Напоминание не должно дублироваться, отправлено %d", sent)
+	}
+
+	// Ошибка отправки не помечает напоминание доставленным
+	f.failSend = true
+	if sent := f.tick(t, eventTime.Add(-24*time.Hour)); sent != 0 {
+		t.Errorf("При ошибке отправки ничего не должно считаться отправленным, получено %d", sent)
+	}
+	f.failSend = false
+	if sent := f.tick(t, eventTime.Add(-24*time.Hour+time.Minute)); sent != 1 {
+		t.Errorf("Напоминание за 1 день должно быть отправлено повторно, отправлено %d", sent)
+	}
+
+	// Бот "лежал" с момента напоминания за 1 час: отправляется только оно, а не вся пачка
+	if sent := f.tick(t, eventTime.Add(-30*time.Minute)); sent != 1 {
+		t.Errorf("Ожидалось одно пропущенное напоминание, отправлено %d", sent)
+	}
+
+	if sent := f.tick(t, eventTime.Add(time.Minute)); sent != 1 {
+		t.Fatalf("Ожидалось напоминание в момент события, отправлено %d", sent)
+	}
+	if last := f.sent[len(f.sent)-1]; !strings.Contains(last.text, "наступило") {
+		t.Errorf("Неожиданный текст напоминания: %s", last.text)
+	}
+
+	// Отключённые напоминания не отправляются
+	if err := f.eventService.CreateEvent(100, "quiet", "2030-02-01 00:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := f.eventService.SetEventReminders(100, "quiet", []string{}); err != nil {
+		t.Fatalf("Ошибка отключения напоминаний: %v", err)
+	}
+	if sent := f.tick(t, time.Date(2030, 1, 31, 1, 0, 0, 0, location)); sent != 0 {
+		t.Errorf("Отключённые напоминания не должны отправляться, отправлено %d", sent)
+	}
+}
+
+func testRecurringReminders(t *testing.T, store storage.Storage) {
+	f := newReminderFixture(store)
+	location := getMoscow(t)
+
+	_, err := f.eventService.AddEvent(200, models.Event{
+		Name:       "mom_birthday",
+		Date:       "1990-05-12 00:00",
+		Recurrence: &models.Recurrence{Frequency: models.FrequencyYearly},
+	})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	// Напоминание по умолчанию (1d) приходит каждый год
+	for _, year := range []int{2030, 2031} {
+		at := time.Date(year, 5, 11, 0, 30, 0, 0, location)
+		if sent := f.tick(t, at); sent != 1 {
+			t.Errorf("Ожидалось напоминание в %d году, отправлено %d", year, sent)
+		}
+		if sent := f.tick(t, at.Add(time.Minute)); sent != 0 {
+			t.Errorf("Напоминание в %d году не должно дублироваться, отправлено %d", year, sent)
+		}
+	}
+}
+
+func getMoscow(t *testing.T) *time.Location {
+	t.Helper()
+	location, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Fatalf("Failed to load test timezone: %v", err)
+	}
+	return location
+}
+
+func TestReminderSchedulerJSON(t *testing.T) {
+	chdirTemp(t)
+	testReminderScheduler(t, storage.NewJSONStorage(storage.JSONOptions{}))
+}
+
+func TestReminderSchedulerSQLite(t *testing.T) {
+	testReminderScheduler(t, newSQLiteStorage(t))
+}
+
+func TestRecurringRemindersJSON(t *testing.T) {
+	chdirTemp(t)
+	testRecurringReminders(t, storage.NewJSONStorage(storage.JSONOptions{}))
+}
+
+func TestRecurringRemindersSQLite(t *testing.T) {
+	testRecurringReminders(t, newSQLiteStorage(t))
+}
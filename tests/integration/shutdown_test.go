@@ -0,0 +1,36 @@
+package integration
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestJSONStorageRejectsWritesAfterClose(t *testing.T) {
+	chdirTemp(t)
+	store := storage.NewJSONStorage(storage.JSONOptions{})
+
+	event := models.Event{EventID: "1", Name: "party", Date: "2030-06-01 18:00", Status: models.StatusActive, ChatID: 100}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+	if err := store.Close(); err != nil {
+		t.Fatalf("Ошибка закрытия хранилища: %v", err)
+	}
+
+	event.Name = "late"
+	if err := store.SaveEvent(100, event); !errors.Is(err, storage.ErrStorageClosed) {
+		t.Errorf("Ожидалась ошибка закрытого хранилища, получено %v", err)
+	}
+
+	// Сохранённые до закрытия данные остаются на диске
+	reopened := storage.NewJSONStorage(storage.JSONOptions{})
+	if !reopened.EventExists(100, "party") {
+		t.Error("Событие, сохранённое до закрытия, должно остаться в файле")
+	}
+	if reopened.EventExists(100, "late") {
+		t.Error("Запись после закрытия не должна попасть в файл")
+	}
+}
@@ -0,0 +1,213 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func newSQLiteStorage(t *testing.T) *storage.SQLiteStorage {
+	t.Helper()
+	store, err := storage.NewSQLiteStorage(filepath.Join(t.TempDir(), "bot.db"))
+	if err != nil {
+		t.Fatalf("Ошибка инициализации SQLiteStorage: %v", err)
+	}
+	t.Cleanup(func() { store.Close() })
+	return store
+}
+
+func TestSQLiteStorageEvents(t *testing.T) {
+	store := newSQLiteStorage(t)
+
+	event := models.Event{
+		EventID: models.GenerateEventID(),
+		Name:    "new_year",
+		Date:    "2030-12-31 00:00",
+		Status:  models.StatusActive,
+		ChatID:  100,
+	}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+	if !store.EventExists(100, "new_year") {
+		t.Error("Событие должно существовать в своём чате")
+	}
+	if store.EventExists(200, "new_year") {
+		t.Error("Событие не должно существовать в другом чате")
+	}
+
+	// Повторное сохранение с тем же EventID обновляет событие
+	event.Status = models.StatusOutdated
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	events, err := store.GetEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 1 || events[0].Status != models.StatusOutdated {
+		t.Errorf("Ожидалось одно устаревшее событие, получено %+v", events)
+	}
+
+	// Событие с тем же именем в том же чате отклоняется ограничением БД
+	duplicate := event
+	duplicate.EventID = models.GenerateEventID()
+	if err := store.SaveEvent(100, duplicate); err != storage.ErrDuplicateEvent {
+		t.Errorf("Ожидалась ошибка ErrDuplicateEvent, получено %v", err)
+	}
+
+	// В другом чате то же имя допустимо
+	duplicate.ChatID = 200
+	duplicate.Visibility = models.VisibilityPublic
+	if err := store.SaveEvent(200, duplicate); err != nil {
+		t.Errorf("Событие с тем же именем в другом чате должно сохраняться: %v", err)
+	}
+
+	found, chatID, err := store.FindEventAcrossChats("new_year", 100)
+	if err != nil {
+		t.Fatalf("Ошибка поиска события: %v", err)
+	}
+	if chatID != 200 || found.EventID != duplicate.EventID {
+		t.Errorf("Ожидалось событие из чата 200, получено %+v", *found)
+	}
+}
+
+func TestSQLiteStoragePersistsAcrossReopen(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bot.db")
+	store, err := storage.NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Ошибка инициализации SQLiteStorage: %v", err)
+	}
+	event := models.Event{
+		EventID: models.GenerateEventID(),
+		Name:    "trip",
+		Date:    "2030-07-01 00:00",
+		Status:  models.StatusActive,
+		ChatID:  100,
+	}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+	if err := store.AddEventToUser(100, 1, event); err != nil {
+		t.Fatalf("Ошибка добавления события пользователю: %v", err)
+	}
+	store.Close()
+
+	// Повторное открытие не должно заново применять миграции
+	reopened, err := storage.NewSQLiteStorage(path)
+	if err != nil {
+		t.Fatalf("Ошибка повторного открытия SQLiteStorage: %v", err)
+	}
+	defer reopened.Close()
+
+	user, err := reopened.GetUser(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if len(user.Events) != 1 || user.Events[0].EventID != event.EventID {
+		t.Errorf("Ожидалось событие trip у пользователя, получено %+v", user.Events)
+	}
+}
+
+func TestSQLiteImportJSON(t *testing.T) {
+	dir := t.TempDir()
+	eventID := models.GenerateEventID()
+	active := models.Event{EventID: eventID, Name: "birthday", Date: "2020-05-01 00:00", Status: models.StatusActive, ChatID: 100}
+	outdated := active
+	outdated.Status = models.StatusOutdated
+	conflicting := models.Event{EventID: models.GenerateEventID(), Name: "birthday", Date: "2021-05-01 00:00", Status: models.StatusActive, ChatID: 100}
+
+	// Файл воспроизводит старую ошибку: обновлённое событие дописано повторно
+	data := []storage.ChatData{
+		{
+			ChatID: 100,
+			Events: []models.Event{active, outdated, conflicting},
+			Users: []models.User{
+				{UserID: 1, ChatID: 100, Events: []models.Event{active}},
+			},
+		},
+		{
+			ChatID: 200,
+			Events: []models.Event{{EventID: models.GenerateEventID(), Name: "trip", Date: "2030-07-01 00:00", Status: models.StatusActive, ChatID: 200}},
+			Users:  []models.User{},
+		},
+	}
+	jsonPath := filepath.Join(dir, "events.json")
+	content, err := json.Marshal(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(jsonPath, content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	store := newSQLiteStorage(t)
+	result, err := store.ImportJSON(jsonPath)
+	if err != nil {
+		t.Fatalf("Ошибка импорта: %v", err)
+	}
+	if result.Chats != 2 || result.Events != 2 || result.Users != 1 || result.Skipped != 1 {
+		t.Errorf("Неожиданный результат импорта: %+v", result)
+	}
+
+	event, err := store.GetEvent(100, "birthday")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	if event.EventID != eventID || event.Status != models.StatusOutdated {
+		t.Errorf("Ожидалась последняя версия события, получено %+v", *event)
+	}
+
+	// Повторный импорт не создаёт дубликатов
+	if _, err := store.ImportJSON(jsonPath); err != nil {
+		t.Fatalf("Ошибка повторного импорта: %v", err)
+	}
+	events, _ := store.GetEvents(100)
+	if len(events) != 1 {
+		t.Errorf("Ожидалось одно событие после повторного импорта, получено %d", len(events))
+	}
+	user, err := store.GetUser(100, 1)
+	if err != nil {
+		t.Fatalf("Ошибка получения пользователя: %v", err)
+	}
+	if len(user.Events) != 1 {
+		t.Errorf("Ожидалась одна привязка события к пользователю, получено %d", len(user.Events))
+	}
+}
+
+func TestSQLiteStorageRecurrence(t *testing.T) {
+	store := newSQLiteStorage(t)
+
+	event := models.Event{
+		EventID:    models.GenerateEventID(),
+		Name:       "mom_birthday",
+		Date:       "1990-05-12 00:00",
+		Status:     models.StatusActive,
+		ChatID:     100,
+		Recurrence: &models.Recurrence{Frequency: models.FrequencyYearly, Interval: 1, Until: "2090-05-12 00:00"},
+	}
+	if err := store.SaveEvent(100, event); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+
+	got, err := store.GetEvent(100, "mom_birthday")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	if got.Recurrence == nil || *got.Recurrence != *event.Recurrence {
+		t.Errorf("Правило повторения не сохранилось: %+v", got.Recurrence)
+	}
+
+	got.Recurrence = nil
+	if err := store.UpdateEvent(100, *got); err != nil {
+		t.Fatalf("Ошибка обновления события: %v", err)
+	}
+	got, _ = store.GetEvent(100, "mom_birthday")
+	if got.Recurrence != nil {
+		t.Errorf("Правило повторения должно быть удалено: %+v", got.Recurrence)
+	}
+}
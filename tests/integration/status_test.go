@@ -0,0 +1,111 @@
+package integration
+
+import (
+	"encoding/json"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestUpdateEventStatusDoesNotDuplicate(t *testing.T) {
+	chdirTemp(t)
+	store := storage.NewJSONStorage(storage.JSONOptions{})
+	eventService := services.NewEventService(store)
+
+	past := models.Event{
+		EventID: models.GenerateEventID(),
+		Name:    "old_party",
+		Date:    "2020-01-01 00:00",
+		Status:  models.StatusActive,
+		ChatID:  100,
+	}
+	if err := store.SaveEvent(100, past); err != nil {
+		t.Fatalf("Ошибка сохранения события: %v", err)
+	}
+
+	// Каждый запрос /old_party раньше дописывал копию события
+	for i := 0; i < 3; i++ {
+		if err := eventService.UpdateEventStatus(100, "old_party"); err != nil {
+			t.Fatalf("Ошибка обновления статуса: %v", err)
+		}
+	}
+
+	events, err := eventService.ListEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 1 {
+		t.Fatalf("Ожидалось одно событие, получено %d", len(events))
+	}
+	if events[0].Status != models.StatusOutdated {
+		t.Errorf("Ожидался статус outdated, получено %s", events[0].Status)
+	}
+}
+
+func TestRefreshStatuses(t *testing.T) {
+	chdirTemp(t)
+	store := storage.NewJSONStorage(storage.JSONOptions{})
+	eventService := services.NewEventService(store)
+
+	if err := eventService.CreateEvent(100, "soon", "2030-01-01 12:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if err := eventService.CreateEvent(200, "later", "2031-01-01 12:00", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	// Момент между двумя событиями: первое уже прошло, второе ещё нет
+	now := time.Date(2030, 6, 1, 0, 0, 0, 0, time.UTC)
+	updated, err := eventService.RefreshStatuses(now)
+	if err != nil {
+		t.Fatalf("Ошибка обновления статусов: %v", err)
+	}
+	if updated != 1 {
+		t.Errorf("Ожидалось одно обновлённое событие, получено %d", updated)
+	}
+
+	soon, _ := eventService.GetEvent(100, "soon")
+	later, _ := eventService.GetEvent(200, "later")
+	if soon.Status != models.StatusOutdated || later.Status != models.StatusActive {
+		t.Errorf("Неверные статусы: soon=%s, later=%s", soon.Status, later.Status)
+	}
+
+	// Повторный проход ничего не меняет
+	if updated, _ := eventService.RefreshStatuses(now); updated != 0 {
+		t.Errorf("Повторный проход не должен менять события, изменено %d", updated)
+	}
+	events, _ := eventService.GetAllEvents()
+	if len(events) != 2 {
+		t.Errorf("Ожидалось два события, получено %d", len(events))
+	}
+}
+
+func TestJSONStorageCollapsesLegacyDuplicates(t *testing.T) {
+	chdirTemp(t)
+
+	event := models.Event{EventID: models.GenerateEventID(), Name: "birthday", Date: "2020-05-01 00:00", Status: models.StatusActive, ChatID: 100}
+	outdated := event
+	outdated.Status = models.StatusOutdated
+	content, err := json.Marshal([]storage.ChatData{{ChatID: 100, Events: []models.Event{event, outdated, outdated}}})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Mkdir("data", os.ModePerm); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile("data/events.json", content, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	events, err := storage.NewJSONStorage(storage.JSONOptions{}).GetEvents(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения событий: %v", err)
+	}
+	if len(events) != 1 || events[0].Status != models.StatusOutdated {
+		t.Errorf("Ожидалась одна последняя версия события, получено %+v", events)
+	}
+}
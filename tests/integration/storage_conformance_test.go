@@ -0,0 +1,41 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+	"github.com/TheReshkin/tg-bot-family/internal/storage/storagetest"
+)
+
+func TestJSONStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+func TestJSONStorageDelayedFlushConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		store := storage.NewJSONStorage(storage.JSONOptions{
+			Path:       filepath.Join(t.TempDir(), "events.json"),
+			FlushDelay: 10 * time.Millisecond,
+		})
+		t.Cleanup(func() { store.Close() })
+		return store
+	})
+}
+
+func TestSQLiteStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return newSQLiteStorage(t)
+	})
+}
+
+func TestPostgresStorageConformance(t *testing.T) {
+	storagetest.Run(t, func(t *testing.T) storage.Storage {
+		return newPostgresStorage(t)
+	})
+}
@@ -0,0 +1,95 @@
+package integration
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func suggestedCommands(suggestions []services.Suggestion) []string {
+	commands := make([]string, len(suggestions))
+	for i, suggestion := range suggestions {
+		commands[i] = suggestion.Command
+	}
+	return commands
+}
+
+func TestSuggestEvents(t *testing.T) {
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+
+	for _, event := range []models.Event{
+		{Name: "new_year", Date: "2030-12-31 23:59"},
+		{Name: "День рождения мамы", Date: "2030-05-12"},
+		{Name: "birthday_dad", Date: "2030-08-01"},
+		{Name: "vacation", Date: "2030-07-01"},
+	} {
+		if _, err := eventService.AddEvent(100, event); err != nil {
+			t.Fatalf("Ошибка создания события %s: %v", event.Name, err)
+		}
+	}
+	if _, err := eventService.AddAlias(100, "new_year", "ny"); err != nil {
+		t.Fatalf("Ошибка добавления псевдонима: %v", err)
+	}
+	if err := eventService.CreateEvent(200, "vacaton", "2030-07-01", ""); err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	tests := []struct {
+		input string
+		want  []string
+	}{
+		// Опечатки, перестановки и регистр
+		{"new_yaer", []string{"new_year"}},
+		{"NewYear", []string{"new_year"}},
+		{"vacaton", []string{"vacation"}},
+		// Предлагаются и псевдонимы
+		{"nyy", []string{"ny"}},
+		// Начало команды
+		{"birth", []string{"birthday_dad"}},
+		// Набрано по-русски: транслитерация, в том числе неполная
+		{"день_рождения", []string{"den_rozhdeniya_mamy"}},
+		{"den_rojdeniya_mamy", []string{"den_rozhdeniya_mamy"}},
+		// Непохожие команды ничего не предлагают
+		{"start_game", nil},
+		{"x", nil},
+	}
+	for _, tt := range tests {
+		suggestions, err := eventService.SuggestEvents(100, tt.input, services.MaxSuggestions)
+		if err != nil {
+			t.Fatalf("Ошибка подсказок для %q: %v", tt.input, err)
+		}
+		if got := suggestedCommands(suggestions); !equalStrings(got, tt.want) {
+			t.Errorf("Для %q ожидались подсказки %v, получено %v", tt.input, tt.want, got)
+		}
+	}
+
+	// Подсказок не больше limit, ближайшие - первыми
+	for _, name := range []string{"party1", "party2", "party3", "party4"} {
+		if err := eventService.CreateEvent(100, name, "2030-09-01", ""); err != nil {
+			t.Fatalf("Ошибка создания события: %v", err)
+		}
+	}
+	suggestions, err := eventService.SuggestEvents(100, "party2", services.MaxSuggestions)
+	if err != nil {
+		t.Fatalf("Ошибка подсказок: %v", err)
+	}
+	if got := suggestedCommands(suggestions); len(got) != services.MaxSuggestions || got[0] != "party2" {
+		t.Errorf("Ожидались %d подсказки, первая party2, получено %v", services.MaxSuggestions, got)
+	}
+}
+
+func equalStrings(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,114 @@
+package integration
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/scheduler"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func testTimeZones(t *testing.T, store storage.Storage) {
+	chatService := services.NewChatService(store)
+	eventService := services.NewEventService(store)
+
+	// Неизвестный чат получает настройки по умолчанию
+	settings, err := chatService.GetSettings(100)
+	if err != nil {
+		t.Fatalf("Ошибка получения настроек чата: %v", err)
+	}
+	if settings.TimeZone != "" {
+		t.Errorf("Ожидался часовой пояс по умолчанию, получено %q", settings.TimeZone)
+	}
+	if _, err := chatService.SetTimeZone(100, "Mars/Olympus"); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного часового пояса")
+	}
+
+	timeZone, err := chatService.SetTimeZone(100, "asia/yekaterinburg")
+	if err != nil {
+		t.Fatalf("Ошибка установки часового пояса: %v", err)
+	}
+	if timeZone != "Asia/Yekaterinburg" {
+		t.Errorf("Ожидалось каноническое имя, получено %s", timeZone)
+	}
+	if location := chatService.Location(100); location.String() != "Asia/Yekaterinburg" {
+		t.Errorf("Ожидался часовой пояс чата, получено %s", location)
+	}
+
+	// Новое событие понимается в часовом поясе чата
+	event, err := eventService.AddEvent(100, models.Event{Name: "call", Date: "2030-01-10 18:00"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+	if event.TimeZone != "Asia/Yekaterinburg" {
+		t.Errorf("Событие должно получить часовой пояс чата, получено %q", event.TimeZone)
+	}
+	stored, err := eventService.GetEvent(100, "call")
+	if err != nil {
+		t.Fatalf("Ошибка получения события: %v", err)
+	}
+	start, err := stored.Time()
+	if err != nil {
+		t.Fatalf("Ошибка получения даты события: %v", err)
+	}
+	if expected := time.Date(2030, 1, 10, 16, 0, 0, 0, getMoscow(t)); !start.Equal(expected) {
+		t.Errorf("Ожидалось %v, получено %v", expected, start)
+	}
+
+	// Собственный часовой пояс события переопределяет пояс чата, не меняя записанную дату
+	if err := eventService.SetEventTimeZone(100, "call", "Europe/Moscow"); err != nil {
+		t.Fatalf("Ошибка установки часового пояса события: %v", err)
+	}
+	stored, _ = eventService.GetEvent(100, "call")
+	start, _ = stored.Time()
+	if stored.Date != "2030-01-10 18:00" || !start.Equal(time.Date(2030, 1, 10, 18, 0, 0, 0, getMoscow(t))) {
+		t.Errorf("Неожиданное событие после смены часового пояса: %+v", *stored)
+	}
+
+	// Сброс часового пояса чата не затрагивает другие чаты и уже созданные события
+	if _, err := chatService.SetTimeZone(200, "Asia/Vladivostok"); err != nil {
+		t.Fatalf("Ошибка установки часового пояса: %v", err)
+	}
+	if _, err := chatService.SetTimeZone(100, ""); err != nil {
+		t.Fatalf("Ошибка сброса часового пояса: %v", err)
+	}
+	if settings, _ := chatService.GetSettings(200); settings.TimeZone != "Asia/Vladivostok" {
+		t.Errorf("Часовой пояс другого чата изменился: %q", settings.TimeZone)
+	}
+	stored, _ = eventService.GetEvent(100, "call")
+	if stored.TimeZone != "Europe/Moscow" {
+		t.Errorf("Часовой пояс события не должен меняться вместе с чатом, получено %q", stored.TimeZone)
+	}
+
+	// Напоминание показывает дату в часовом поясе чата, куда оно отправлено
+	var sent []string
+	send := func(ctx context.Context, chatID int64, text string) error {
+		sent = append(sent, text)
+		return nil
+	}
+	if _, err := chatService.SetTimeZone(100, "Asia/Yekaterinburg"); err != nil {
+		t.Fatalf("Ошибка установки часового пояса: %v", err)
+	}
+	clock := &fakeClock{now: time.Date(2030, 1, 10, 17, 0, 0, 0, getMoscow(t))}
+	reminders := scheduler.NewReminderScheduler(eventService, services.NewReminderService(store), send, clock,
+		scheduler.ReminderOptions{Defaults: []string{"1h"}, ChatLocation: chatService.Location})
+	if _, err := reminders.Tick(context.Background()); err != nil {
+		t.Fatalf("Ошибка прохода планировщика: %v", err)
+	}
+	if len(sent) != 1 || !strings.Contains(sent[0], "Дата: 2030-01-10 20:00 (Asia/Yekaterinburg)") {
+		t.Errorf("Неожиданные напоминания: %q", sent)
+	}
+}
+
+func TestTimeZonesJSON(t *testing.T) {
+	chdirTemp(t)
+	testTimeZones(t, storage.NewJSONStorage(storage.JSONOptions{}))
+}
+
+func TestTimeZonesSQLite(t *testing.T) {
+	testTimeZones(t, newSQLiteStorage(t))
+}
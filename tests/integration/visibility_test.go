@@ -0,0 +1,44 @@
+package integration
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+	"github.com/TheReshkin/tg-bot-family/internal/services"
+	"github.com/TheReshkin/tg-bot-family/internal/storage"
+)
+
+func TestEventVisibilityControlsCrossChatLookup(t *testing.T) {
+	store := storage.NewJSONStorage(storage.JSONOptions{Path: filepath.Join(t.TempDir(), "events.json")})
+	eventService := services.NewEventService(store)
+
+	_, err := eventService.AddEvent(100, models.Event{Name: "surprise", Date: "2030-05-01 19:00"})
+	if err != nil {
+		t.Fatalf("Ошибка создания события: %v", err)
+	}
+
+	// По умолчанию событие приватное и в других чатах не находится
+	if _, err := eventService.FindEventAcrossChats("surprise", 200); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Приватное событие не должно находиться в другом чате, получено %v", err)
+	}
+
+	if err := eventService.SetEventVisibility(100, "surprise", models.VisibilityShared, []int64{200}); err != nil {
+		t.Fatalf("Ошибка изменения видимости: %v", err)
+	}
+	found, err := eventService.FindEventAcrossChats("surprise", 200)
+	if err != nil || found.ChatID != 100 {
+		t.Errorf("Событие должно быть видно чату 200, получено %+v, %v", found, err)
+	}
+	if _, err := eventService.FindEventAcrossChats("surprise", 300); !errors.Is(err, storage.ErrEventNotFound) {
+		t.Errorf("Событие не должно быть видно чату 300, получено %v", err)
+	}
+
+	if err := eventService.SetEventVisibility(100, "surprise", models.VisibilityPublic, nil); err != nil {
+		t.Fatalf("Ошибка изменения видимости: %v", err)
+	}
+	if _, err := eventService.FindEventAcrossChats("surprise", 300); err != nil {
+		t.Errorf("Публичное событие должно быть видно любому чату: %v", err)
+	}
+}
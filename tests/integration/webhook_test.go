@@ -0,0 +1,150 @@
+package integration
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/webhook"
+	"github.com/go-telegram/bot"
+	tgmodels "github.com/go-telegram/bot/models"
+)
+
+const testUpdate = `{"update_id": 1, "message": {"message_id": 10, "date": 0,
+	"chat": {"id": 100, "type": "private"}, "from": {"id": 1, "is_bot": false, "first_name": "Тест"},
+	"text": "/list"}}`
+
+// startWebhookServer запускает бота в режиме webhook на локальном порту и возвращает
+// адрес сервера и канал с текстами полученных сообщений
+func startWebhookServer(t *testing.T, secretToken string) (string, <-chan string) {
+	t.Helper()
+	b, err := bot.New("123456:test", bot.WithSkipGetMe())
+	if err != nil {
+		t.Fatalf("Ошибка создания бота: %v", err)
+	}
+	received := make(chan string, 1)
+	b.RegisterHandler(bot.HandlerTypeMessageText, "/", bot.MatchTypePrefix, func(ctx context.Context, b *bot.Bot, update *tgmodels.Update) {
+		received <- update.Message.Text
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	go b.StartWebhook(ctx)
+
+	server := webhook.NewServer(webhook.Options{
+		ListenAddr:  "127.0.0.1:0",
+		Path:        "/telegram/webhook",
+		SecretToken: secretToken,
+	}, b.WebhookHandler())
+	if err := server.Listen(); err != nil {
+		t.Fatalf("Ошибка запуска сервера: %v", err)
+	}
+	go server.Serve()
+	t.Cleanup(func() {
+		cancel()
+		server.Shutdown(context.Background())
+	})
+	return "http://" + server.Addr().String(), received
+}
+
+func postUpdate(t *testing.T, url, secretToken string) int {
+	t.Helper()
+	request, err := http.NewRequest(http.MethodPost, url, strings.NewReader(testUpdate))
+	if err != nil {
+		t.Fatal(err)
+	}
+	request.Header.Set("Content-Type", "application/json")
+	if secretToken != "" {
+		request.Header.Set(webhook.SecretTokenHeader, secretToken)
+	}
+	response, err := http.DefaultClient.Do(request)
+	if err != nil {
+		t.Fatalf("Ошибка запроса к webhook: %v", err)
+	}
+	response.Body.Close()
+	return response.StatusCode
+}
+
+func TestWebhookDeliversUpdates(t *testing.T) {
+	baseURL, received := startWebhookServer(t, "s3cret")
+	url := baseURL + "/telegram/webhook"
+
+	if status := postUpdate(t, url, "s3cret"); status != http.StatusOK {
+		t.Fatalf("Ожидался статус 200, получено %d", status)
+	}
+	select {
+	case text := <-received:
+		if text != "/list" {
+			t.Errorf("Ожидалось сообщение /list, получено %q", text)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("Обновление не дошло до обработчика")
+	}
+}
+
+func TestWebhookRejectsInvalidRequests(t *testing.T) {
+	baseURL, received := startWebhookServer(t, "s3cret")
+	url := baseURL + "/telegram/webhook"
+
+	if status := postUpdate(t, url, ""); status != http.StatusUnauthorized {
+		t.Errorf("Без секрета ожидался статус 401, получено %d", status)
+	}
+	if status := postUpdate(t, url, "wrong"); status != http.StatusUnauthorized {
+		t.Errorf("С неверным секретом ожидался статус 401, получено %d", status)
+	}
+	response, err := http.Get(url)
+	if err != nil {
+		t.Fatal(err)
+	}
+	response.Body.Close()
+	if response.StatusCode != http.StatusMethodNotAllowed {
+		t.Errorf("Для GET ожидался статус 405, получено %d", response.StatusCode)
+	}
+
+	select {
+	case text := <-received:
+		t.Errorf("Отклонённое обновление не должно обрабатываться, получено %q", text)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	health, err := http.Get(baseURL + webhook.HealthPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	health.Body.Close()
+	if health.StatusCode != http.StatusOK {
+		t.Errorf("Проверка здоровья должна отвечать 200, получено %d", health.StatusCode)
+	}
+}
+
+// fakeRegistrar запоминает вызовы setWebhook и deleteWebhook
+type fakeRegistrar struct {
+	set     *bot.SetWebhookParams
+	deleted bool
+}
+
+func (r *fakeRegistrar) SetWebhook(ctx context.Context, params *bot.SetWebhookParams) (bool, error) {
+	r.set = params
+	return true, nil
+}
+
+func (r *fakeRegistrar) DeleteWebhook(ctx context.Context, params *bot.DeleteWebhookParams) (bool, error) {
+	r.deleted = true
+	return true, nil
+}
+
+func TestWebhookRegistration(t *testing.T) {
+	registrar := &fakeRegistrar{}
+	options := webhook.Options{PublicURL: "https://bot.example.com/", Path: "/telegram/webhook", SecretToken: "s3cret"}
+
+	if err := webhook.Register(context.Background(), registrar, options); err != nil {
+		t.Fatalf("Ошибка регистрации webhook: %v", err)
+	}
+	if registrar.set.URL != "https://bot.example.com/telegram/webhook" || registrar.set.SecretToken != "s3cret" {
+		t.Errorf("Неожиданные параметры setWebhook: %+v", registrar.set)
+	}
+	if err := webhook.Unregister(context.Background(), registrar); err != nil || !registrar.deleted {
+		t.Errorf("Webhook должен быть снят, ошибка: %v", err)
+	}
+}
@@ -0,0 +1,181 @@
+package unit
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/config"
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+// clearConfigEnv сбрасывает переменные окружения, влияющие на конфигурацию
+func clearConfigEnv(t *testing.T) {
+	t.Helper()
+	for _, name := range []string{
+		"TELEGRAM_BOT_TOKEN", "TELEGRAM_TOKEN", "BOT_MODE", "DATA_DIR", "LOG_LEVEL", "DEFAULT_TIME_ZONE",
+		"ADMIN_IDS", "TEST_CHAT_ID", "TEST_CHAT_CATALOG", "STORAGE_BACKEND", "DATABASE_URL", "SQLITE_PATH", "JSON_STORAGE_PATH",
+		"JSON_BACKUP_COUNT", "JSON_BACKUP_INTERVAL", "JSON_FLUSH_DELAY", "WEBHOOK_URL", "WEBHOOK_LISTEN_ADDR",
+		"PORT", "WEBHOOK_PATH", "WEBHOOK_SECRET_TOKEN", "WEBHOOK_TLS_CERT", "WEBHOOK_TLS_KEY",
+		"WEBHOOK_SELF_SIGNED", "REMINDER_OFFSETS", "REMINDER_CHECK_INTERVAL", "STATUS_SWEEP_INTERVAL", "COUNTDOWN_INTERVAL",
+		"COMMAND_MENU_INTERVAL", "CONVERSATION_TIMEOUT", "ADMIN_CACHE_TTL", "SHUTDOWN_TIMEOUT",
+	} {
+		t.Setenv(name, "")
+	}
+}
+
+func writeConfigFile(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o644); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestConfigDefaults(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("TELEGRAM_BOT_TOKEN", "token")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	if cfg.Mode != config.ModePolling || cfg.Storage.Backend != config.StorageJSON {
+		t.Errorf("Неожиданные значения по умолчанию: %+v", cfg)
+	}
+	if cfg.Storage.JSONPath != filepath.Join("data", "events.json") || cfg.Storage.SQLitePath != filepath.Join("data", "bot.db") {
+		t.Errorf("Пути данных должны строиться от DATA_DIR, получено %s и %s", cfg.Storage.JSONPath, cfg.Storage.SQLitePath)
+	}
+	if cfg.DefaultTimeZone != models.DefaultTimeZone {
+		t.Errorf("Ожидался часовой пояс %s, получено %s", models.DefaultTimeZone, cfg.DefaultTimeZone)
+	}
+	if len(cfg.DefaultReminders()) != len(models.DefaultReminders) {
+		t.Errorf("Ожидались напоминания по умолчанию, получено %v", cfg.DefaultReminders())
+	}
+}
+
+func TestConfigLegacyTokenVariable(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("TELEGRAM_TOKEN", "legacy")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	if cfg.TelegramToken != "legacy" {
+		t.Errorf("Прежняя переменная TELEGRAM_TOKEN должна поддерживаться, получено %q", cfg.TelegramToken)
+	}
+
+	t.Setenv("TELEGRAM_BOT_TOKEN", "current")
+	cfg, err = config.Load("")
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	if cfg.TelegramToken != "current" {
+		t.Errorf("TELEGRAM_BOT_TOKEN должен иметь приоритет, получено %q", cfg.TelegramToken)
+	}
+}
+
+func TestConfigYAMLWithEnvOverride(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfigFile(t, `
+telegram_token: from-yaml
+data_dir: /var/lib/bot
+log_level: debug
+default_time_zone: asia/yekaterinburg
+admin_ids: [1, 2]
+storage:
+  backend: sqlite
+  backup_interval: 30m
+reminders:
+  offsets: ["7d", "1d"]
+shutdown_timeout: 20s
+`)
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("ADMIN_IDS", "3, 4")
+
+	cfg, err := config.Load(path)
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	if cfg.TelegramToken != "from-yaml" || cfg.Storage.Backend != config.StorageSQLite {
+		t.Errorf("Значения из YAML не применены: %+v", cfg)
+	}
+	if cfg.Storage.SQLitePath != filepath.Join("/var/lib/bot", "bot.db") {
+		t.Errorf("Путь SQLite должен строиться от data_dir, получено %s", cfg.Storage.SQLitePath)
+	}
+	if cfg.LogLevel != "warn" {
+		t.Errorf("Переменная окружения должна переопределять YAML, получено %s", cfg.LogLevel)
+	}
+	if len(cfg.AdminIDs) != 2 || cfg.AdminIDs[0] != 3 || !cfg.IsAdmin(4) || cfg.IsAdmin(1) {
+		t.Errorf("Ожидались администраторы 3 и 4, получено %v", cfg.AdminIDs)
+	}
+	if cfg.DefaultTimeZone != "Asia/Yekaterinburg" {
+		t.Errorf("Часовой пояс должен нормализоваться, получено %s", cfg.DefaultTimeZone)
+	}
+	if cfg.Storage.BackupInterval != 30*time.Minute || cfg.ShutdownTimeout != 20*time.Second {
+		t.Errorf("Длительности из YAML не применены: %s, %s", cfg.Storage.BackupInterval, cfg.ShutdownTimeout)
+	}
+	if got := cfg.DefaultReminders(); len(got) != 2 || got[0] != "7d" {
+		t.Errorf("Ожидались напоминания 7d и 1d, получено %v", got)
+	}
+}
+
+func TestConfigRejectsUnknownYAMLField(t *testing.T) {
+	clearConfigEnv(t)
+	path := writeConfigFile(t, "telegram_token: x\nstorage_backend: sqlite\n")
+
+	if _, err := config.Load(path); err == nil {
+		t.Error("Неизвестный параметр в YAML должен давать ошибку")
+	}
+}
+
+func TestConfigDisabledReminders(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("TELEGRAM_BOT_TOKEN", "token")
+	t.Setenv("REMINDER_OFFSETS", "off")
+
+	cfg, err := config.Load("")
+	if err != nil {
+		t.Fatalf("Ошибка загрузки конфигурации: %v", err)
+	}
+	if got := cfg.DefaultReminders(); len(got) != 0 {
+		t.Errorf("REMINDER_OFFSETS=off должен отключать напоминания, получено %v", got)
+	}
+}
+
+func TestConfigValidationReportsAllErrors(t *testing.T) {
+	clearConfigEnv(t)
+	t.Setenv("BOT_MODE", "webhook")
+	t.Setenv("WEBHOOK_URL", "http://bot.example.com")
+	t.Setenv("STORAGE_BACKEND", "postgres")
+	t.Setenv("DEFAULT_TIME_ZONE", "Mars/Olympus")
+	t.Setenv("SHUTDOWN_TIMEOUT", "soon")
+	t.Setenv("WEBHOOK_TLS_CERT", "cert.pem")
+	t.Setenv("TEST_CHAT_CATALOG", "семья")
+
+	_, err := config.Load("")
+	if err == nil {
+		t.Fatal("Ожидалась ошибка проверки конфигурации")
+	}
+	for _, expected := range []string{"SHUTDOWN_TIMEOUT"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Ошибка разбора должна упоминать %s: %v", expected, err)
+		}
+	}
+
+	// После исправления разбора остаются ошибки проверки значений - все сразу
+	t.Setenv("SHUTDOWN_TIMEOUT", "")
+	_, err = config.Load("")
+	if err == nil {
+		t.Fatal("Ожидалась ошибка проверки конфигурации")
+	}
+	for _, expected := range []string{"TELEGRAM_BOT_TOKEN", "WEBHOOK_URL", "DATABASE_URL", "DEFAULT_TIME_ZONE", "WEBHOOK_TLS_KEY", "TEST_CHAT_CATALOG"} {
+		if !strings.Contains(err.Error(), expected) {
+			t.Errorf("Ошибка проверки должна упоминать %s: %v", expected, err)
+		}
+	}
+}
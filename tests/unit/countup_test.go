@@ -0,0 +1,93 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestElapsedSince(t *testing.T) {
+	moscow, err := time.LoadLocation("Europe/Moscow")
+	if err != nil {
+		t.Skipf("Нет базы часовых поясов: %v", err)
+	}
+	start := time.Date(2021, 8, 15, 12, 0, 0, 0, moscow)
+
+	tests := []struct {
+		now       time.Time
+		expected  string
+		totalDays int
+	}{
+		{start.Add(-time.Hour), "меньше часа", 0},
+		{start.Add(30 * time.Minute), "меньше часа", 0},
+		{start.Add(5 * time.Hour), "5 часов", 0},
+		{time.Date(2021, 8, 16, 11, 0, 0, 0, moscow), "23 часа", 0},
+		{time.Date(2022, 8, 15, 12, 0, 0, 0, moscow), "1 год", 365},
+		{time.Date(2024, 10, 18, 15, 0, 0, 0, moscow), "3 года 2 месяца 3 дня 3 часа", 1160},
+		// Момент в другом часовом поясе сравнивается в поясе события
+		{time.Date(2021, 9, 15, 9, 0, 0, 0, time.UTC), "1 месяц", 31},
+	}
+	for _, tt := range tests {
+		elapsed := models.ElapsedSince(start, tt.now)
+		if elapsed.String() != tt.expected || elapsed.TotalDays != tt.totalDays {
+			t.Errorf("ElapsedSince(%s) = %q (%d), ожидалось %q (%d)", tt.now, elapsed, elapsed.TotalDays, tt.expected, tt.totalDays)
+		}
+	}
+
+	// Месяцы считаются по календарю: с 31 января до 28 февраля прошёл месяц
+	january := time.Date(2023, 1, 31, 0, 0, 0, 0, time.UTC)
+	if elapsed := models.ElapsedSince(january, time.Date(2023, 2, 28, 0, 0, 0, 0, time.UTC)); elapsed.String() != "1 месяц" {
+		t.Errorf("Ожидался 1 месяц, получено %q", elapsed)
+	}
+	if got := models.ElapsedSince(start, time.Date(2024, 10, 18, 15, 0, 0, 0, moscow)).DescribeTotalDays(); got != "1160 дней" {
+		t.Errorf("Ожидалось \"1160 дней\", получено %q", got)
+	}
+}
+
+func TestMilestones(t *testing.T) {
+	start := time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	// Вехи идут в порядке наступления: дни и годовщины вперемешку
+	milestones := models.MilestonesBetween(start, start, start.AddDate(11, 0, 0))
+	var keys []string
+	for _, milestone := range milestones {
+		keys = append(keys, milestone.Key())
+	}
+	expected := []string{"100d", "1y", "500d", "1000d", "5y", "2000d", "3000d", "10y", "4000d"}
+	if len(keys) != len(expected) {
+		t.Fatalf("Ожидались вехи %v, получено %v", expected, keys)
+	}
+	for i := range expected {
+		if keys[i] != expected[i] {
+			t.Fatalf("Ожидались вехи %v, получено %v", expected, keys)
+		}
+	}
+	if !milestones[0].At.Equal(time.Date(2020, 4, 10, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("100 дней должны наступить 2020-04-10, получено %s", milestones[0].At)
+	}
+
+	// Граница: веха в момент from не входит, в момент to - входит
+	at := start.AddDate(0, 0, 500)
+	if got := models.MilestonesBetween(start, at, at.Add(time.Hour)); len(got) != 0 {
+		t.Errorf("Веха в момент from не должна входить, получено %v", got)
+	}
+	if got := models.MilestonesBetween(start, at.Add(-time.Hour), at); len(got) != 1 || got[0].String() != "500 дней" {
+		t.Errorf("Ожидалась веха 500 дней, получено %v", got)
+	}
+
+	next := models.NextMilestone(start, at)
+	if next.Key() != "1000d" || next.String() != "1000 дней" {
+		t.Errorf("После 500 дней ожидалась веха 1000 дней, получено %s", next.Key())
+	}
+	if next := models.NextMilestone(start, start.AddDate(4, 6, 0)); next.String() != "5 лет" {
+		t.Errorf("Ожидалась веха 5 лет, получено %s", next)
+	}
+
+	// Годовщина события 29 февраля в невисокосный год - 28 февраля
+	leap := time.Date(2024, 2, 29, 0, 0, 0, 0, time.UTC)
+	first := models.NextMilestone(leap, leap.AddDate(0, 4, 0))
+	if first.Key() != "1y" || !first.At.Equal(time.Date(2025, 2, 28, 0, 0, 0, 0, time.UTC)) {
+		t.Errorf("Первая годовщина должна быть 2025-02-28, получено %s %s", first.Key(), first.At)
+	}
+}
@@ -0,0 +1,150 @@
+package unit
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestDefaultDateParser(t *testing.T) {
+	location := getTestLocation(t)
+	// Среда, 14 октября 2026, 12:00
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, location)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+		consumed int
+	}{
+		{"2025-12-31 14:30 party", time.Date(2025, 12, 31, 14, 30, 0, 0, location), 2},
+		{"2025-12-31 party", time.Date(2025, 12, 31, 0, 0, 0, 0, location), 1},
+		{"31.12.2025 party", time.Date(2025, 12, 31, 0, 0, 0, 0, location), 1},
+		{"завтра в 18:00 party", time.Date(2026, 10, 15, 18, 0, 0, 0, location), 3},
+		{"Завтра party", time.Date(2026, 10, 15, 0, 0, 0, 0, location), 1},
+		{"послезавтра в 9 вечера party", time.Date(2026, 10, 16, 21, 0, 0, 0, location), 4},
+		{"через 3 недели trip", time.Date(2026, 11, 4, 0, 0, 0, 0, location), 3},
+		{"через неделю trip", time.Date(2026, 10, 21, 0, 0, 0, 0, location), 2},
+		{"через 2 часа call", time.Date(2026, 10, 14, 14, 0, 0, 0, location), 3},
+		{"через месяц в 10:30 trip", time.Date(2026, 11, 14, 10, 30, 0, 0, location), 4},
+		{"31 декабря new_year", time.Date(2026, 12, 31, 0, 0, 0, 0, location), 2},
+		{"1 марта spring", time.Date(2027, 3, 1, 0, 0, 0, 0, location), 2},
+		{"12 марта 2026 в 9 утра meeting", time.Date(2026, 3, 12, 9, 0, 0, 0, location), 6},
+		{"12 марта 2027 года meeting", time.Date(2027, 3, 12, 0, 0, 0, 0, location), 4},
+		{"в пятницу party", time.Date(2026, 10, 16, 0, 0, 0, 0, location), 2},
+		{"в следующую пятницу в 19:30 party", time.Date(2026, 10, 23, 19, 30, 0, 0, location), 5},
+		{"в следующую среду party", time.Date(2026, 10, 21, 0, 0, 0, 0, location), 3},
+		{"во вторник в полдень lunch", time.Date(2026, 10, 20, 12, 0, 0, 0, location), 4},
+		{"в 18:00 party", time.Date(2026, 10, 14, 18, 0, 0, 0, location), 2},
+		{"сегодня 23:15 party", time.Date(2026, 10, 14, 23, 15, 0, 0, location), 2},
+		{"сегодня в 12 ночи party", time.Date(2026, 10, 15, 0, 0, 0, 0, location), 4},
+		{"в 12 ночи party", time.Date(2026, 10, 15, 0, 0, 0, 0, location), 3},
+		{"завтра в 12 ночи party", time.Date(2026, 10, 16, 0, 0, 0, 0, location), 4},
+		{"завтра в 2 ночи party", time.Date(2026, 10, 15, 2, 0, 0, 0, location), 4},
+	}
+
+	for _, tt := range tests {
+		parsed, err := models.DefaultDateParser.ParseDate(strings.Fields(tt.input), now, location)
+		if err != nil {
+			t.Errorf("%q: неожиданная ошибка %v", tt.input, err)
+			continue
+		}
+		if !parsed.Time.Equal(tt.expected) {
+			t.Errorf("%q: ожидалось %v, получено %v", tt.input, tt.expected, parsed.Time)
+		}
+		if parsed.Consumed != tt.consumed {
+			t.Errorf("%q: ожидалось слов %d, получено %d", tt.input, tt.consumed, parsed.Consumed)
+		}
+	}
+}
+
+func TestDateParserNextWeekday(t *testing.T) {
+	location := getTestLocation(t)
+	// Четверг, 15 октября 2026
+	now := time.Date(2026, 10, 15, 12, 0, 0, 0, location)
+
+	tests := []struct {
+		input    string
+		expected time.Time
+	}{
+		{"в пятницу party", time.Date(2026, 10, 16, 0, 0, 0, 0, location)},
+		{"в следующую пятницу party", time.Date(2026, 10, 23, 0, 0, 0, 0, location)},
+		{"в следующий понедельник party", time.Date(2026, 10, 19, 0, 0, 0, 0, location)},
+		{"в следующее воскресенье party", time.Date(2026, 10, 25, 0, 0, 0, 0, location)},
+		{"в ближайшую пятницу party", time.Date(2026, 10, 16, 0, 0, 0, 0, location)},
+	}
+
+	for _, tt := range tests {
+		parsed, err := models.DefaultDateParser.ParseDate(strings.Fields(tt.input), now, location)
+		if err != nil {
+			t.Errorf("%q: неожиданная ошибка %v", tt.input, err)
+			continue
+		}
+		if !parsed.Time.Equal(tt.expected) {
+			t.Errorf("%q: ожидалось %v, получено %v", tt.input, tt.expected, parsed.Time)
+		}
+	}
+}
+
+func TestDateParserAmbiguous(t *testing.T) {
+	location := getTestLocation(t)
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, location)
+
+	for _, input := range []string{
+		"завтра в 9 party", // утра или вечера
+		"в среду party",    // сегодня среда
+		"в 10:00 party",    // сегодня уже прошло
+	} {
+		_, err := models.DefaultDateParser.ParseDate(strings.Fields(input), now, location)
+		var ambiguous *models.AmbiguousDateError
+		if !errors.As(err, &ambiguous) {
+			t.Errorf("%q: ожидалась ошибка неоднозначности, получено %v", input, err)
+			continue
+		}
+		if ambiguous.Suggestion == "" {
+			t.Errorf("%q: ожидалась подсказка", input)
+		}
+	}
+}
+
+func TestDateParserRejects(t *testing.T) {
+	location := getTestLocation(t)
+	now := time.Date(2026, 10, 14, 12, 0, 0, 0, location)
+
+	for _, input := range []string{"party 2025-12-31", "когда-нибудь party"} {
+		if _, err := models.DefaultDateParser.ParseDate(strings.Fields(input), now, location); !errors.Is(err, models.ErrDateNotRecognized) {
+			t.Errorf("%q: ожидалась ошибка ErrDateNotRecognized, получено %v", input, err)
+		}
+	}
+	for _, input := range []string{"31 февраля 2027 party", "завтра в 25:00 party", "завтра в -5 party", "завтра в +5 вечера party", "через 3 попугая party", "в 13 утра party",
+		"+5 декабря party", "-5 декабря party", "через +3 дня party", "через -3 дня party", "через 0 дней party"} {
+		if _, err := models.DefaultDateParser.ParseDate(strings.Fields(input), now, location); err == nil {
+			t.Errorf("%q: ожидалась ошибка", input)
+		}
+	}
+}
+
+func TestDateParserUsesLocation(t *testing.T) {
+	yekaterinburg, err := models.LoadTimeZone("Asia/Yekaterinburg")
+	if err != nil {
+		t.Fatal(err)
+	}
+	// 23:00 по Москве - это уже 01:00 следующего дня в Екатеринбурге
+	now := time.Date(2026, 10, 14, 23, 0, 0, 0, getTestLocation(t))
+	parsed, err := models.DefaultDateParser.ParseDate([]string{"завтра", "в", "18:00"}, now, yekaterinburg)
+	if err != nil {
+		t.Fatalf("Ошибка разбора: %v", err)
+	}
+	if expected := time.Date(2026, 10, 16, 18, 0, 0, 0, yekaterinburg); !parsed.Time.Equal(expected) {
+		t.Errorf("Ожидалось %v, получено %v", expected, parsed.Time)
+	}
+}
+
+func TestDescribeDate(t *testing.T) {
+	date := time.Date(2026, 12, 31, 18, 0, 0, 0, getTestLocation(t))
+	if got := models.DescribeDate(date); got != "четверг, 31 декабря 2026, 18:00" {
+		t.Errorf("Неожиданное описание даты: %s", got)
+	}
+}
@@ -0,0 +1,64 @@
+package unit
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestEventSlug(t *testing.T) {
+	tests := map[string]string{
+		"День рождения мамы":             "den_rozhdeniya_mamy",
+		"день_рождения_мамы":             "den_rozhdeniya_mamy",
+		"Новый год 2027!":                "novyy_god_2027",
+		"  Ёлка -- у Щукиных  ":          "elka_u_shchukinykh",
+		"Подъезд, объявление":            "podezd_obyavlenie",
+		"Party у Пети":                   "party_u_peti",
+		"🎉🎉":                             "event",
+		"Очень длинное название события": "ochen_dlinnoe_nazvanie_sobytiya",
+	}
+	for input, expected := range tests {
+		if slug := models.EventSlug(input); slug != expected {
+			t.Errorf("EventSlug(%q) = %q, ожидалось %q", input, slug, expected)
+		}
+	}
+
+	// Команда обрезается до 32 символов без "_" на конце
+	slug := models.EventSlug("Юбилей дедушки и бабушки в деревне")
+	if len(slug) > models.MaxCommandLength || strings.HasSuffix(slug, "_") || !models.IsValidEventName(slug) {
+		t.Errorf("Некорректная длинная команда: %q", slug)
+	}
+}
+
+func TestNumberedSlug(t *testing.T) {
+	if slug := models.NumberedSlug("party", 2); slug != "party_2" {
+		t.Errorf("Ожидалось party_2, получено %q", slug)
+	}
+	long := strings.Repeat("a", models.MaxCommandLength)
+	if slug := models.NumberedSlug(long, 12); len(slug) != models.MaxCommandLength || !strings.HasSuffix(slug, "_12") {
+		t.Errorf("Номер должен помещаться в %d символов, получено %q", models.MaxCommandLength, slug)
+	}
+}
+
+func TestIsValidDisplayName(t *testing.T) {
+	for _, name := range []string{"День рождения мамы", "Party 🎉", "Новый год"} {
+		if !models.IsValidDisplayName(name) {
+			t.Errorf("Имя %q должно быть допустимым", name)
+		}
+	}
+	for _, name := range []string{"", "   ", "Мама\nи папа", strings.Repeat("я", models.MaxDisplayNameLength+1)} {
+		if models.IsValidDisplayName(name) {
+			t.Errorf("Имя %q должно отклоняться", name)
+		}
+	}
+}
+
+func TestEventTitle(t *testing.T) {
+	if title := (models.Event{Name: "party"}).Title(); title != "party" {
+		t.Errorf("Без отображаемого имени должно показываться имя команды, получено %q", title)
+	}
+	if title := (models.Event{Name: "den_rozhdeniya", DisplayName: "День рождения"}).Title(); title != "День рождения" {
+		t.Errorf("Ожидалось отображаемое имя, получено %q", title)
+	}
+}
@@ -0,0 +1,69 @@
+package unit
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/lifecycle"
+)
+
+func TestTrackerWaitsForRunningWork(t *testing.T) {
+	var tracker lifecycle.Tracker
+	release := make(chan struct{})
+	finished := make(chan struct{})
+
+	if !tracker.Go(func() {
+		<-release
+		close(finished)
+	}) {
+		t.Fatal("Задача должна запускаться до остановки")
+	}
+
+	waited := make(chan error, 1)
+	go func() { waited <- tracker.Wait(context.Background()) }()
+
+	select {
+	case <-waited:
+		t.Fatal("Wait не должен завершаться, пока задача выполняется")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	if err := <-waited; err != nil {
+		t.Fatalf("Неожиданная ошибка ожидания: %v", err)
+	}
+	select {
+	case <-finished:
+	default:
+		t.Error("Wait завершился раньше задачи")
+	}
+}
+
+func TestTrackerWaitRespectsDeadline(t *testing.T) {
+	var tracker lifecycle.Tracker
+	if !tracker.Enter() {
+		t.Fatal("Enter должен быть успешным до остановки")
+	}
+	defer tracker.Leave()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if err := tracker.Wait(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Ожидалась ошибка истечения таймаута, получено %v", err)
+	}
+}
+
+func TestTrackerRejectsWorkAfterWait(t *testing.T) {
+	var tracker lifecycle.Tracker
+	if err := tracker.Wait(context.Background()); err != nil {
+		t.Fatalf("Неожиданная ошибка ожидания: %v", err)
+	}
+	if tracker.Enter() {
+		t.Error("После остановки новые задачи не должны приниматься")
+	}
+	if tracker.Go(func() { t.Error("Задача не должна запускаться после остановки") }) {
+		t.Error("Go должен возвращать false после остановки")
+	}
+}
@@ -0,0 +1,33 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestParsePermissionPolicy(t *testing.T) {
+	for input, expected := range map[string]models.PermissionPolicy{
+		"everyone": models.PolicyEveryone,
+		"Owners":   models.PolicyOwners,
+		"ADMINS":   models.PolicyAdmins,
+	} {
+		policy, err := models.ParsePermissionPolicy(input)
+		if err != nil || policy != expected {
+			t.Errorf("ParsePermissionPolicy(%q) = %q, %v; ожидалось %q", input, policy, err, expected)
+		}
+	}
+	if _, err := models.ParsePermissionPolicy("moderators"); err == nil {
+		t.Error("Неизвестная политика должна отклоняться")
+	}
+}
+
+func TestChatSettingsDefaultPolicies(t *testing.T) {
+	var settings models.ChatSettings
+	if settings.EffectiveCreatePolicy() != models.PolicyEveryone {
+		t.Errorf("По умолчанию создавать события должны все, получено %q", settings.EffectiveCreatePolicy())
+	}
+	if settings.EffectiveEditPolicy() != models.PolicyOwners {
+		t.Errorf("По умолчанию изменять события должны создатель и администраторы, получено %q", settings.EffectiveEditPolicy())
+	}
+}
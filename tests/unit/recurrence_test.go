@@ -0,0 +1,116 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestNextOccurrenceYearly(t *testing.T) {
+	location := getTestLocation(t)
+	rule := &models.Recurrence{Frequency: models.FrequencyYearly}
+	start := time.Date(1990, 5, 12, 0, 0, 0, 0, location)
+
+	now := time.Date(2026, 6, 1, 0, 0, 0, 0, location)
+	next, ok := rule.NextOccurrence(start, now)
+	expected := time.Date(2027, 5, 12, 0, 0, 0, 0, location)
+	if !ok || !next.Equal(expected) {
+		t.Errorf("Ожидалось %v, получено %v (ok=%v)", expected, next, ok)
+	}
+
+	// В сам день события повторение ещё не прошло
+	now = time.Date(2026, 5, 12, 0, 0, 0, 0, location)
+	next, _ = rule.NextOccurrence(start, now)
+	if !next.Equal(now) {
+		t.Errorf("Ожидалось %v, получено %v", now, next)
+	}
+}
+
+func TestNextOccurrenceClampsDay(t *testing.T) {
+	location := getTestLocation(t)
+
+	// 29 февраля в невисокосный год переносится на 28 февраля
+	leap := &models.Recurrence{Frequency: models.FrequencyYearly}
+	next, _ := leap.NextOccurrence(time.Date(2024, 2, 29, 0, 0, 0, 0, location), time.Date(2025, 1, 1, 0, 0, 0, 0, location))
+	if expected := time.Date(2025, 2, 28, 0, 0, 0, 0, location); !next.Equal(expected) {
+		t.Errorf("Ожидалось %v, получено %v", expected, next)
+	}
+
+	// 31 число каждый месяц не "перескакивает" на следующий месяц
+	monthly := &models.Recurrence{Frequency: models.FrequencyMonthly}
+	next, _ = monthly.NextOccurrence(time.Date(2025, 1, 31, 10, 0, 0, 0, location), time.Date(2025, 2, 1, 0, 0, 0, 0, location))
+	if expected := time.Date(2025, 2, 28, 10, 0, 0, 0, location); !next.Equal(expected) {
+		t.Errorf("Ожидалось %v, получено %v", expected, next)
+	}
+}
+
+func TestNextOccurrenceIntervalAndUntil(t *testing.T) {
+	location := getTestLocation(t)
+	start := time.Date(2025, 1, 1, 9, 0, 0, 0, location)
+
+	rule := &models.Recurrence{Frequency: models.FrequencyWeekly, Interval: 2}
+	next, ok := rule.NextOccurrence(start, time.Date(2025, 1, 10, 0, 0, 0, 0, location))
+	if expected := time.Date(2025, 1, 15, 9, 0, 0, 0, location); !ok || !next.Equal(expected) {
+		t.Errorf("Ожидалось %v, получено %v", expected, next)
+	}
+
+	rule.Until = "2025-01-20 00:00"
+	if _, ok := rule.NextOccurrence(start, time.Date(2025, 1, 16, 0, 0, 0, 0, location)); ok {
+		t.Error("Серия должна закончиться после даты until")
+	}
+}
+
+func TestRecurringEventStaysActive(t *testing.T) {
+	event := models.Event{Name: "mom_birthday", Date: "1990-05-12 00:00", Recurrence: &models.Recurrence{Frequency: models.FrequencyYearly}}
+	status, err := event.StatusAt(time.Now())
+	if err != nil {
+		t.Fatalf("Ошибка расчёта статуса: %v", err)
+	}
+	if status != models.StatusActive {
+		t.Errorf("Повторяющееся событие не должно устаревать, получено %s", status)
+	}
+
+	event.Recurrence = nil
+	if status, _ := event.StatusAt(time.Now()); status != models.StatusOutdated {
+		t.Errorf("Разовое прошедшее событие должно устареть, получено %s", status)
+	}
+}
+
+func TestParseRecurrence(t *testing.T) {
+	rule, consumed := models.ParseRecurrence([]string{"every", "2", "weeks", "until", "2030-01-01", "Уборка"})
+	if rule == nil {
+		t.Fatal("Правило не разобрано")
+	}
+	if consumed != 5 || rule.Frequency != models.FrequencyWeekly || rule.Interval != 2 || rule.Until != "2030-01-01 00:00" {
+		t.Errorf("Правило разобрано неверно: %+v, использовано слов %d", *rule, consumed)
+	}
+	if rule.String() != "каждые 2 недели до 2030-01-01 00:00" {
+		t.Errorf("Неверное описание правила: %s", rule.String())
+	}
+
+	rule, consumed = models.ParseRecurrence([]string{"every", "год", "День", "рождения"})
+	if rule == nil || consumed != 2 || rule.Frequency != models.FrequencyYearly || rule.String() != "каждый год" {
+		t.Errorf("Правило с русской единицей разобрано неверно: %+v, %d", rule, consumed)
+	}
+
+	// Окончание без даты остаётся описанием
+	rule, consumed = models.ParseRecurrence([]string{"every", "year", "до", "встречи"})
+	if rule == nil || consumed != 2 || rule.Until != "" {
+		t.Errorf("Окончание без даты не должно входить в правило: %+v, %d", rule, consumed)
+	}
+
+	// Без явного every и с нераспознанным правилом слова остаются описанием
+	for _, args := range [][]string{
+		{"Новый", "год"},
+		{"Каждый", "год", "собираемся", "у", "бабушки"},
+		{"каждый", "гость", "приносит", "салат"},
+		{"every", "fortnight"},
+		{"every", "0", "weeks"},
+		{"every"},
+	} {
+		if rule, consumed := models.ParseRecurrence(args); rule != nil || consumed != 0 {
+			t.Errorf("%q не должно считаться правилом повторения, получено %+v", args, rule)
+		}
+	}
+}
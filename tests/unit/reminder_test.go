@@ -0,0 +1,49 @@
+package unit
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestNormalizeReminders(t *testing.T) {
+	reminders, err := models.NormalizeReminders([]string{"1h", "7d,1d", "0", "24h", "2w", "90m"})
+	if err != nil {
+		t.Fatalf("Ошибка разбора напоминаний: %v", err)
+	}
+	expected := []string{"14d", "7d", "1d", "90m", "1h", "0"}
+	if !reflect.DeepEqual(reminders, expected) {
+		t.Errorf("Ожидалось %v, получено %v", expected, reminders)
+	}
+
+	if _, err := models.NormalizeReminders([]string{"soon"}); err == nil {
+		t.Error("Некорректное смещение должно приводить к ошибке")
+	}
+}
+
+func TestReminderOffsetsDefaults(t *testing.T) {
+	event := models.Event{}
+	offsets, err := event.ReminderOffsets([]string{"1d", "0"})
+	if err != nil || !reflect.DeepEqual(offsets, []time.Duration{24 * time.Hour, 0}) {
+		t.Errorf("Ожидались напоминания по умолчанию, получено %v (%v)", offsets, err)
+	}
+
+	event.Reminders = []string{}
+	if offsets, _ := event.ReminderOffsets([]string{"1d"}); len(offsets) != 0 {
+		t.Errorf("Пустой список должен отключать напоминания, получено %v", offsets)
+	}
+}
+
+func TestDescribeDuration(t *testing.T) {
+	if text := models.DescribeDuration(7*24*time.Hour + 3*time.Hour); text != "7 дней 3 часа" {
+		t.Errorf("Неверное описание длительности: %s", text)
+	}
+	if text := models.DescribeReminderOffset(21 * time.Minute); text != "за 21 минуту" {
+		t.Errorf("Неверное описание смещения: %s", text)
+	}
+	if text := models.DescribeTimeUntil(2*time.Hour + time.Minute); text != "через 2 часа 1 минуту" {
+		t.Errorf("Неверное описание времени до события: %s", text)
+	}
+}
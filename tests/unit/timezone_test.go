@@ -0,0 +1,91 @@
+package unit
+
+import (
+	"testing"
+	"time"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestParseEventDateIn(t *testing.T) {
+	yekaterinburg, err := models.LoadTimeZone("Asia/Yekaterinburg")
+	if err != nil {
+		t.Fatalf("Ошибка загрузки часового пояса: %v", err)
+	}
+
+	parsed, err := models.ParseEventDateIn("2030-12-31 18:00", yekaterinburg)
+	if err != nil {
+		t.Fatalf("Ошибка разбора даты: %v", err)
+	}
+	// 18:00 в Екатеринбурге (UTC+5) - это 16:00 по Москве (UTC+3)
+	moscow := getTestLocation(t)
+	if got := parsed.In(moscow).Format("15:04"); got != "16:00" {
+		t.Errorf("Ожидалось 16:00 по Москве, получено %s", got)
+	}
+}
+
+func TestEventTimeUsesEventZone(t *testing.T) {
+	event := models.Event{Name: "call", Date: "2030-01-10 12:00"}
+	moscowTime, err := event.Time()
+	if err != nil {
+		t.Fatalf("Ошибка получения даты события: %v", err)
+	}
+	expected := time.Date(2030, 1, 10, 12, 0, 0, 0, getTestLocation(t))
+	if !moscowTime.Equal(expected) {
+		t.Errorf("Событие без часового пояса должно использовать пояс по умолчанию, получено %v", moscowTime)
+	}
+
+	event.TimeZone = "Asia/Yekaterinburg"
+	localTime, err := event.Time()
+	if err != nil {
+		t.Fatalf("Ошибка получения даты события: %v", err)
+	}
+	if diff := moscowTime.Sub(localTime); diff != 2*time.Hour {
+		t.Errorf("Ожидалась разница 2 часа, получено %v", diff)
+	}
+
+	event.TimeZone = "Mars/Olympus"
+	if _, err := event.Time(); err == nil {
+		t.Error("Ожидалась ошибка для неизвестного часового пояса")
+	}
+}
+
+func TestNormalizeTimeZone(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		valid    bool
+	}{
+		{"Asia/Yekaterinburg", "Asia/Yekaterinburg", true},
+		{"asia/yekaterinburg", "Asia/Yekaterinburg", true},
+		{"america/new_york", "America/New_York", true},
+		{"utc", "UTC", true},
+		{"Local", "", false},
+		{"", "", false},
+		{"Mars/Olympus", "", false},
+	}
+
+	for _, tt := range tests {
+		got, err := models.NormalizeTimeZone(tt.input)
+		if tt.valid && (err != nil || got != tt.expected) {
+			t.Errorf("NormalizeTimeZone(%q) = %q, %v; ожидалось %q", tt.input, got, err, tt.expected)
+		}
+		if !tt.valid && err == nil {
+			t.Errorf("NormalizeTimeZone(%q) должна вернуть ошибку", tt.input)
+		}
+	}
+}
+
+func TestFormatEventDateIn(t *testing.T) {
+	moscow := getTestLocation(t)
+	instant := time.Date(2030, 12, 31, 14, 30, 0, 0, moscow)
+
+	if got := models.FormatEventDateIn(instant, moscow); got != "2030-12-31 14:30" {
+		t.Errorf("В часовом поясе по умолчанию название не добавляется, получено %s", got)
+	}
+
+	yekaterinburg, _ := models.LoadTimeZone("Asia/Yekaterinburg")
+	if got := models.FormatEventDateIn(instant, yekaterinburg); got != "2030-12-31 16:30 (Asia/Yekaterinburg)" {
+		t.Errorf("Неожиданная дата: %s", got)
+	}
+}
@@ -0,0 +1,41 @@
+package unit
+
+import (
+	"testing"
+
+	"github.com/TheReshkin/tg-bot-family/internal/models"
+)
+
+func TestParseVisibility(t *testing.T) {
+	for input, expected := range map[string]models.EventVisibility{
+		"private": models.VisibilityPrivate,
+		"Shared":  models.VisibilityShared,
+		"PUBLIC":  models.VisibilityPublic,
+	} {
+		visibility, err := models.ParseVisibility(input)
+		if err != nil || visibility != expected {
+			t.Errorf("ParseVisibility(%q) = %q, %v; ожидалось %q", input, visibility, err, expected)
+		}
+	}
+	if _, err := models.ParseVisibility("friends"); err == nil {
+		t.Error("Неизвестная видимость должна отклоняться")
+	}
+}
+
+func TestEventIsVisibleTo(t *testing.T) {
+	event := models.Event{ChatID: 100}
+	if !event.IsVisibleTo(100) || event.IsVisibleTo(200) {
+		t.Error("Событие без видимости должно быть видно только своему чату")
+	}
+
+	event.Visibility = models.VisibilityShared
+	event.SharedWith = []int64{200}
+	if !event.IsVisibleTo(200) || event.IsVisibleTo(300) {
+		t.Error("Открытое событие должно быть видно только перечисленным чатам")
+	}
+
+	event.Visibility = models.VisibilityPublic
+	if !event.IsVisibleTo(300) {
+		t.Error("Публичное событие должно быть видно любому чату")
+	}
+}